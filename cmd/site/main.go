@@ -27,6 +27,20 @@ func main() {
 		for _, v := range written {
 			fmt.Printf("build: wrote %s\n", v)
 		}
+	case "check":
+		code, err := commands.RunCheck(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+		os.Exit(code)
+	case "new":
+		path, code, err := commands.RunNew(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(code)
+		}
+
+		fmt.Printf("new: wrote %s\n", path)
 	case "serve":
 		code, err := commands.RunServe(os.Args[2:])
 		if err != nil {
@@ -44,11 +58,16 @@ func main() {
 
 func usage() {
 	const msg = `Usage:
-	site build [--out <dir>]
+	site build [--out <dir>] [--cache <dir>] [--image-widths <w,w,...>]
+	           [--drafts] [--future] [--now <date>]
+	site check [--format text|json]
+	site new post [--slug <slug>] [--date <date>] <title>
 	site serve [--dir <dir>] [--addr <host:port>]
 
 Commands:
 	build    Generate static site output (placeholder for now)
+	check    Validate content without writing output
+	new      Scaffold a draft post from content/archetypes/post.md, if present
 	serve    Serve a directory over HTTP for local preview
 `
 	fmt.Fprint(os.Stderr, msg)
@@ -4,7 +4,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
 	"github.com/spcameron/seanpatrickcameron.com/internal/site"
 )
 
@@ -13,14 +18,84 @@ func RunBuild(args []string) ([]string, int, error) {
 	fs.SetOutput(os.Stderr)
 
 	out := fs.String("out", "build/public", "output directory")
+	cache := fs.String("cache", "build/cache", "directory for cached processed media")
+	widths := fs.String("image-widths", "480,960,1920", "comma-separated widths of responsive image variants")
+	drafts := fs.Bool("drafts", false, "include draft posts")
+	future := fs.Bool("future", false, "include posts dated after --now")
+	nowFlag := fs.String("now", "", "build as of this date (YYYY-MM-DD) or time (RFC 3339) instead of the current time")
 	if err := fs.Parse(args); err != nil {
 		return nil, 2, err
 	}
 
-	written, err := site.BuildSite(*out)
+	imageWidths, err := parseWidths(*widths)
 	if err != nil {
-		return nil, 1, fmt.Errorf("build: %w", err)
+		return nil, 2, fmt.Errorf("build: --image-widths: %w", err)
+	}
+
+	now, err := parseNow(*nowFlag)
+	if err != nil {
+		return nil, 2, fmt.Errorf("build: --now: %w", err)
+	}
+
+	written, err := site.BuildSite(site.BuildOptions{
+		OutDir:      *out,
+		CacheDir:    *cache,
+		ImageWidths: imageWidths,
+		Warn: func(w content.SourceError) {
+			fmt.Fprintf(os.Stderr, "build: warning: %v\n", w)
+		},
+		Drafts: *drafts,
+		Future: *future,
+		Now:    now,
+	})
+	if err != nil {
+		return nil, 1, reportError("build", err)
 	}
 
 	return written, 0, nil
 }
+
+// parseWidths parses a comma-separated list of distinct positive pixel
+// widths. The list must name at least one width.
+func parseWidths(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("no widths given")
+	}
+
+	var widths []int
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+
+		w, err := strconv.Atoi(f)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid width %q", f)
+		}
+		if slices.Contains(widths, w) {
+			return nil, fmt.Errorf("duplicate width %d", w)
+		}
+		widths = append(widths, w)
+	}
+
+	return widths, nil
+}
+
+// parseNow parses a build time given as a date or an RFC 3339 time. A date
+// is taken in the local time zone, and an empty string yields the zero
+// time.
+func parseNow(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (expected YYYY-MM-DD or RFC 3339)", s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+)
+
+func TestParseWidths(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr string
+	}{
+		{name: "single width", input: "480", want: []int{480}},
+		{name: "list keeps its order", input: "960,480,1920", want: []int{960, 480, 1920}},
+		{name: "spaces around widths", input: " 480, 960 ", want: []int{480, 960}},
+		{name: "empty list", input: "", wantErr: "no widths given"},
+		{name: "blank list", input: "  ", wantErr: "no widths given"},
+		{name: "zero", input: "0", wantErr: `invalid width "0"`},
+		{name: "negative", input: "-480", wantErr: `invalid width "-480"`},
+		{name: "not a number", input: "a,100", wantErr: `invalid width "a"`},
+		{name: "empty entry", input: "480,,960", wantErr: `invalid width ""`},
+		{name: "trailing comma", input: "480,", wantErr: `invalid width ""`},
+		{name: "duplicate", input: "480,960,480", wantErr: "duplicate width 480"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseWidths(tc.input)
+
+			if tc.wantErr != "" {
+				assert.NotNil(t, err)
+				if err != nil {
+					assert.Equal(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseNow(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("EST", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty is the zero time", input: "", want: "0001-01-01T00:00:00Z"},
+		{name: "blank is the zero time", input: "  ", want: "0001-01-01T00:00:00Z"},
+		{name: "date is midnight local time", input: "2026-02-17", want: "2026-02-17T00:00:00-05:00"},
+		{name: "surrounding space is ignored", input: " 2026-02-17 ", want: "2026-02-17T00:00:00-05:00"},
+		{name: "UTC time", input: "2026-02-17T09:30:00Z", want: "2026-02-17T09:30:00Z"},
+		{name: "time keeps its offset", input: "2026-02-17T09:30:00+02:00", want: "2026-02-17T09:30:00+02:00"},
+		{name: "time without an offset", input: "2026-02-17T09:30:00", wantErr: true},
+		{name: "date without padding", input: "2026-2-17", wantErr: true},
+		{name: "impossible date", input: "2026-02-30", wantErr: true},
+		{name: "not a time", input: "yesterday", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseNow(tc.input)
+
+			if tc.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got.Format(time.RFC3339), tc.want)
+		})
+	}
+}
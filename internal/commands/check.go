@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/site"
+)
+
+// checkDiagnostic is one entry of the JSON check report. Line and Column
+// are 1-based, and omitted for errors that are not located in a file.
+type checkDiagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type checkReport struct {
+	Errors      int               `json:"errors"`
+	Warnings    int               `json:"warnings"`
+	Diagnostics []checkDiagnostic `json:"diagnostics"`
+}
+
+// RunCheck validates the site's content without writing any output. It
+// reports every error and warning found, in text on stderr or as JSON on
+// stdout, and exits non-zero when there are errors.
+func RunCheck(args []string) (int, error) {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	format := fs.String("format", "text", "report format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return 2, err
+	}
+	if *format != "text" && *format != "json" {
+		return 2, fmt.Errorf("check: --format: unknown format %q", *format)
+	}
+
+	warnings, err := site.CheckSite()
+
+	var list content.ErrorList
+	if err != nil && !errors.As(err, &list) {
+		return 1, fmt.Errorf("check: %w", err)
+	}
+
+	if *format == "json" {
+		if err := writeCheckJSON(os.Stdout, list, warnings); err != nil {
+			return 1, fmt.Errorf("check: %w", err)
+		}
+		if len(list) > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "check: warning: %v\n", w)
+	}
+
+	if len(list) > 0 {
+		return 1, reportError("check", list)
+	}
+
+	fmt.Fprintf(os.Stderr, "check: ok (%d %s)\n", len(warnings), plural(len(warnings), "warning", "warnings"))
+	return 0, nil
+}
+
+func writeCheckJSON(w io.Writer, errs content.ErrorList, warnings []content.SourceError) error {
+	report := checkReport{
+		Errors:      len(errs),
+		Warnings:    len(warnings),
+		Diagnostics: make([]checkDiagnostic, 0, len(errs)+len(warnings)),
+	}
+
+	for _, err := range errs {
+		report.Diagnostics = append(report.Diagnostics, newCheckDiagnostic(err, "error"))
+	}
+	for _, w := range warnings {
+		report.Diagnostics = append(report.Diagnostics, newCheckDiagnostic(w, "warning"))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func newCheckDiagnostic(err error, severity string) checkDiagnostic {
+	var serr content.SourceError
+	if !errors.As(err, &serr) {
+		return checkDiagnostic{
+			Severity: severity,
+			Message:  err.Error(),
+		}
+	}
+
+	line, col := serr.Position()
+	return checkDiagnostic{
+		File:     serr.Path,
+		Line:     line,
+		Column:   col,
+		Severity: severity,
+		Message:  strings.TrimSpace(serr.Diagnostic.Message),
+	}
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestWriteCheckJSON(t *testing.T) {
+	src := source.NewSource("---\ntitle: Hello\n---\nSee [x](/nowhere/).\n")
+
+	located := func(start source.BytePos, msg string) content.SourceError {
+		return content.SourceError{
+			Path:   "content/posts/hello/index.md",
+			Source: src,
+			Diagnostic: markdown.Diagnostic{
+				Message: msg,
+				Span:    source.ByteSpan{Start: start, End: start + 1},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		errs     content.ErrorList
+		warnings []content.SourceError
+		want     string
+	}{
+		{
+			name: "no findings",
+			want: `{
+  "errors": 0,
+  "warnings": 0,
+  "diagnostics": []
+}`,
+		},
+		{
+			name:     "located errors come before warnings",
+			errs:     content.ErrorList{located(25, "no page or file at internal link: /nowhere/\n")},
+			warnings: []content.SourceError{located(4, "heading level 4 skips level 2")},
+			want: `{
+  "errors": 1,
+  "warnings": 1,
+  "diagnostics": [
+    {
+      "file": "content/posts/hello/index.md",
+      "line": 4,
+      "column": 5,
+      "severity": "error",
+      "message": "no page or file at internal link: /nowhere/"
+    },
+    {
+      "file": "content/posts/hello/index.md",
+      "line": 2,
+      "column": 1,
+      "severity": "warning",
+      "message": "heading level 4 skips level 2"
+    }
+  ]
+}`,
+		},
+		{
+			name: "errors without a location omit the file and position",
+			errs: content.ErrorList{errors.New("site config page_size must be a positive integer")},
+			want: `{
+  "errors": 1,
+  "warnings": 0,
+  "diagnostics": [
+    {
+      "severity": "error",
+      "message": "site config page_size must be a positive integer"
+    }
+  ]
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			require.NoError(t, writeCheckJSON(&b, tc.errs, tc.warnings))
+
+			assert.Equal(t, b.String(), tc.want+"\n")
+		})
+	}
+}
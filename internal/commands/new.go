@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+)
+
+// RunNew scaffolds new content. The only kind is a post, created from its
+// title as a draft under content/posts/; it returns the path of the new
+// post's index.md.
+func RunNew(args []string) (string, int, error) {
+	if len(args) == 0 || args[0] != "post" {
+		return "", 2, fmt.Errorf("new: expected %q followed by a title", "post")
+	}
+
+	fs := flag.NewFlagSet("new post", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	slug := fs.String("slug", "", "slug for the post (default derived from the title)")
+	date := fs.String("date", "", "date of the post, YYYY-MM-DD (default today)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return "", 2, err
+	}
+
+	title := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if title == "" {
+		return "", 2, fmt.Errorf("new: post: missing title")
+	}
+
+	when, err := parseNow(*date)
+	if err != nil {
+		return "", 2, fmt.Errorf("new: --date: %w", err)
+	}
+
+	path, err := content.NewPost("content", content.NewPostOptions{
+		Title: title,
+		Slug:  strings.TrimSpace(*slug),
+		Date:  when,
+	})
+	if err != nil {
+		return "", 1, fmt.Errorf("new: %w", err)
+	}
+
+	return path, 0, nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+)
+
+// reportError prefixes err with the name of the command that raised it.
+// A list of content errors is expanded into a report with each error set
+// apart and a count of them at the top.
+func reportError(cmd string, err error) error {
+	var list content.ErrorList
+	if !errors.As(err, &list) {
+		return fmt.Errorf("%s: %w", cmd, err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d %s\n", cmd, len(list), plural(len(list), "error", "errors"))
+	for _, e := range list {
+		fmt.Fprintf(&sb, "\n%s\n", strings.TrimSuffix(e.Error(), "\n"))
+	}
+
+	return errors.New(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,57 @@
+package content
+
+import "time"
+
+// YearArchive holds the posts dated in one year, grouped by month.
+type YearArchive struct {
+	Year   int
+	Months []MonthArchive
+}
+
+// MonthArchive holds the posts dated in one month, newest first.
+type MonthArchive struct {
+	Year  int
+	Month time.Month
+	Posts []Post
+}
+
+// Count returns the number of posts in the year.
+func (y YearArchive) Count() int {
+	n := 0
+	for _, m := range y.Months {
+		n += len(m.Posts)
+	}
+	return n
+}
+
+// ArchivePosts groups posts by the year and month of their dates, newest
+// first at every level. Posts without a date are left out.
+func ArchivePosts(posts []Post) []YearArchive {
+	dated := make([]Post, 0, len(posts))
+	for _, p := range posts {
+		if !p.FrontMatter.Date.IsZero() {
+			dated = append(dated, p)
+		}
+	}
+
+	SortPosts(dated)
+
+	var years []YearArchive
+	for _, p := range dated {
+		year, month := p.FrontMatter.Date.Year(), p.FrontMatter.Date.Month()
+
+		if n := len(years); n == 0 || years[n-1].Year != year {
+			years = append(years, YearArchive{Year: year})
+		}
+		y := &years[len(years)-1]
+
+		if n := len(y.Months); n == 0 || y.Months[n-1].Month != month {
+			y.Months = append(y.Months, MonthArchive{Year: year, Month: month})
+		}
+		m := &y.Months[len(y.Months)-1]
+
+		m.Posts = append(m.Posts, p)
+	}
+
+	return years
+}
@@ -0,0 +1,50 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestArchivePosts(t *testing.T) {
+	post := func(slug string, year int, month time.Month, day int) Post {
+		return Post{FrontMatter: FrontMatter{
+			Slug: slug,
+			Date: time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		}}
+	}
+
+	posts := []Post{
+		post("jan-early", 2025, time.January, 3),
+		post("mar", 2026, time.March, 4),
+		post("jan-late", 2025, time.January, 20),
+		post("feb", 2026, time.February, 1),
+		{FrontMatter: FrontMatter{Slug: "undated"}},
+	}
+
+	years := ArchivePosts(posts)
+	require.Equal(t, len(years), 2)
+
+	var got [][]string
+	for _, y := range years {
+		for _, m := range y.Months {
+			var slugs []string
+			for _, p := range m.Posts {
+				slugs = append(slugs, p.FrontMatter.Slug)
+			}
+			got = append(got, slugs)
+		}
+	}
+
+	assert.Equal(t, years[0].Year, 2026)
+	assert.Equal(t, years[0].Months[0].Month, time.March)
+	assert.Equal(t, years[0].Count(), 2)
+	assert.Equal(t, years[1].Year, 2025)
+	assert.Equal(t, got, [][]string{
+		{"mar"},
+		{"feb"},
+		{"jan-late", "jan-early"},
+	})
+}
@@ -0,0 +1,202 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	ErrInvalidConfig   = errors.New("site config is malformed")
+	ErrTagConflict     = errors.New("conflicting tags")
+	ErrInvalidPageSize = errors.New("site config page_size must be a positive integer")
+	ErrInvalidBaseURL  = errors.New("site config base_url must be an absolute http or https URL")
+)
+
+// DefaultPageSize is the number of posts listed on each page of the blog
+// index when the site config does not set one.
+const DefaultPageSize = 10
+
+// Config holds the site-wide settings read from the site config file.
+type Config struct {
+	// PageSize is the number of posts listed on each page of the blog
+	// index.
+	PageSize int
+
+	// BaseURL is the absolute URL the site is published at, without a
+	// trailing slash, such as https://example.com. It is empty when the
+	// config does not set one.
+	BaseURL string
+
+	// Tags configures tags by slug.
+	Tags map[string]TagConfig
+}
+
+// TagConfig configures one tag. Name is the name the tag is shown under,
+// and Aliases lists the other names posts may give the tag.
+type TagConfig struct {
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases"`
+}
+
+// ConfigPath returns the path of the site config file in contentRoot.
+func ConfigPath(contentRoot string) string {
+	return filepath.Join(contentRoot, "site.yaml")
+}
+
+// LoadConfig reads and decodes the site config file at path. A missing
+// file yields the default Config. Errors in the file are returned as
+// SourceErrors located within it.
+func LoadConfig(path string) (Config, error) {
+	path = filepath.Clean(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{PageSize: DefaultPageSize}, nil
+		}
+		return Config{}, fmt.Errorf("read: %s: %w", path, err)
+	}
+
+	cfg, err := DecodeConfig(data)
+	if err != nil {
+		return Config{}, locateYAMLErrors(path, string(data), err, 0)
+	}
+
+	return cfg, nil
+}
+
+// DecodeConfig decodes and validates a site config. PageSize defaults to
+// DefaultPageSize, and BaseURL must be an absolute http or https URL with
+// no query or fragment. Tag slugs and aliases are normalized with Slugify, and
+// each must name a single tag. Every invalid entry is reported, each as a
+// FrontMatterError locating it within data.
+func DecodeConfig(data []byte) (Config, error) {
+	var raw struct {
+		PageSize *int                 `yaml:"page_size"`
+		BaseURL  string               `yaml:"base_url"`
+		Tags     map[string]TagConfig `yaml:"tags"`
+	}
+
+	var root yaml.Node
+	errs, err := decodeYAML(data, &root, &raw, ErrInvalidConfig)
+	if err != nil {
+		return Config{}, err
+	}
+
+	pageSize := DefaultPageSize
+	if raw.PageSize != nil {
+		pageSize = *raw.PageSize
+		if pageSize <= 0 {
+			errs = append(errs, fieldError(data, "page_size", fmt.Errorf("%w: %d", ErrInvalidPageSize, pageSize)))
+		}
+	}
+
+	baseURL, err := parseBaseURL(raw.BaseURL)
+	if err != nil {
+		errs = append(errs, fieldError(data, "base_url", err))
+	}
+
+	fail := func(key string, err error) {
+		line, col := tagKeyPosition(&root, key)
+		errs = append(errs, FrontMatterError{Err: err, Line: line, Column: col})
+	}
+
+	// owner maps each normalized tag slug and alias to the configured tag
+	// it names.
+	owner := make(map[string]string)
+	tags := make(map[string]TagConfig, len(raw.Tags))
+
+	for _, key := range slices.Sorted(maps.Keys(raw.Tags)) {
+		slug := Slugify(key)
+		if slug == "" {
+			fail(key, fmt.Errorf("%w: %q", ErrInvalidTag, key))
+			continue
+		}
+		if prev, ok := owner[slug]; ok {
+			fail(key, fmt.Errorf("%w: %q is also configured by %q", ErrTagConflict, slug, prev))
+			continue
+		}
+		owner[slug] = key
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(raw.Tags)) {
+		slug := Slugify(key)
+		if owner[slug] != key {
+			continue
+		}
+
+		tc := raw.Tags[key]
+		var aliases []string
+		for _, a := range tc.Aliases {
+			alias := Slugify(a)
+			switch prev, ok := owner[alias]; {
+			case alias == "":
+				fail(key, fmt.Errorf("%w: alias %q", ErrInvalidTag, a))
+			case ok && prev != key:
+				fail(key, fmt.Errorf("%w: alias %q is also configured by %q", ErrTagConflict, alias, prev))
+			case !ok:
+				owner[alias] = key
+				aliases = append(aliases, alias)
+			}
+		}
+
+		tags[slug] = TagConfig{Name: tc.Name, Aliases: aliases}
+	}
+
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	return Config{PageSize: pageSize, BaseURL: baseURL, Tags: tags}, nil
+}
+
+// parseBaseURL validates the base_url setting and returns it without a
+// trailing slash. An empty setting is allowed.
+func parseBaseURL(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBaseURL, raw)
+	}
+
+	return strings.TrimSuffix(raw, "/"), nil
+}
+
+// tagKeyPosition returns the 1-based position of the key under tags in
+// the config document root, or zeros when it is absent.
+func tagKeyPosition(root *yaml.Node, key string) (int, int) {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return 0, 0
+	}
+
+	m := root.Content[0]
+	if m.Kind != yaml.MappingNode {
+		return 0, 0
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != "tags" || m.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+
+		tags := m.Content[i+1]
+		for j := 0; j+1 < len(tags.Content); j += 2 {
+			if k := tags.Content[j]; k.Value == key {
+				return k.Line, k.Column
+			}
+		}
+	}
+
+	return 0, 0
+}
@@ -30,9 +30,36 @@ func DiscoverPosts(contentRoot string) ([]string, error) {
 		return nil, fmt.Errorf("not a directory: %s", postsDir)
 	}
 
+	return discoverIndexFiles(postsDir, false)
+}
+
+// DiscoverPages returns the index.md of every page under the pages
+// directory of contentRoot, including one at its top level. A content root
+// without a pages directory has no pages.
+func DiscoverPages(contentRoot string) ([]string, error) {
+	pagesDir := filepath.Join(filepath.Clean(contentRoot), "pages")
+
+	info, err := os.Stat(pagesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("stat %s: %w", pagesDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", pagesDir)
+	}
+
+	return discoverIndexFiles(pagesDir, true)
+}
+
+// discoverIndexFiles returns the sorted paths of the index.md files beneath
+// dir, skipping hidden and tooling directories. An index.md directly in dir
+// is included only when includeRoot is set.
+func discoverIndexFiles(dir string, includeRoot bool) ([]string, error) {
 	var candidates []string
 
-	walkErr := filepath.WalkDir(postsDir, func(path string, d fs.DirEntry, err error) error {
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walk %s: %w", path, err)
 		}
@@ -40,7 +67,7 @@ func DiscoverPosts(contentRoot string) ([]string, error) {
 		name := d.Name()
 
 		if d.IsDir() {
-			if shouldSkipDir(name) {
+			if path != dir && shouldSkipDir(name) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -51,7 +78,7 @@ func DiscoverPosts(contentRoot string) ([]string, error) {
 		}
 
 		if strings.EqualFold(name, "index.md") {
-			if filepath.Clean(filepath.Dir(path)) == filepath.Clean(postsDir) {
+			if !includeRoot && filepath.Clean(filepath.Dir(path)) == filepath.Clean(dir) {
 				return nil
 			}
 			candidates = append(candidates, path)
@@ -1,9 +1,13 @@
 package content
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,51 +15,433 @@ import (
 )
 
 var (
-	ErrMissingTitle       = errors.New("frontmatter is missing title")
-	ErrMissingSlug        = errors.New("frontmatter is missing slug")
-	ErrMissingDate        = errors.New("frontmatter is missing date")
-	ErrInvalidDate        = errors.New("frontmatter contains an invalid date format")
-	ErrInvalidFrontMatter = errors.New("frontmatter is malformed")
+	ErrMissingTitle        = errors.New("frontmatter is missing title")
+	ErrMissingSlug         = errors.New("frontmatter is missing slug")
+	ErrMissingDate         = errors.New("frontmatter is missing date")
+	ErrInvalidDate         = errors.New("frontmatter contains an invalid date format")
+	ErrInvalidFrontMatter  = errors.New("frontmatter is malformed")
+	ErrUpdatedBeforeDate   = errors.New("frontmatter updated date is before date")
+	ErrEmptyListEntry      = errors.New("frontmatter list contains an empty entry")
+	ErrInvalidCover        = errors.New("frontmatter cover image must be a file under media/")
+	ErrMissingCoverAlt     = errors.New("frontmatter cover is missing alt text")
+	ErrMissingCoverFile    = errors.New("frontmatter cover image does not exist")
+	ErrInvalidCanonicalURL = errors.New("frontmatter canonical_url must be an absolute http or https URL")
+	ErrInvalidLang         = errors.New("frontmatter lang is not a valid language tag")
 )
 
+// FrontMatterError locates an error within a front matter block. Line and
+// Column are 1-based and count from the first line of the block; they are
+// zero for an error that belongs to the block as a whole, such as a
+// missing field.
+type FrontMatterError struct {
+	Err    error
+	Line   int
+	Column int
+}
+
+func (e FrontMatterError) Error() string {
+	if e.Line == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("line %d:%d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e FrontMatterError) Unwrap() error {
+	return e.Err
+}
+
 type FrontMatter struct {
 	Title string
 	Slug  string
 	Date  time.Time
+
+	// Updated is the date the post was last revised, or the zero time if
+	// it never was. It is never before Date.
+	Updated time.Time
+
+	Description string
+
+	// Summary replaces the summary taken from the start of the body when
+	// the body has no <!--more--> marker.
+	Summary string
+
+	Tags         []string
+	Authors      []string
+	Cover        Cover
+	CanonicalURL string
+	Lang         string
+	Series       string
+
+	// Draft excludes the post from builds unless drafts are requested.
+	Draft bool
+
+	// Typography enables smart quotes, dashes, and ellipses for the post
+	// body. It defaults to true and can be disabled per post.
+	Typography bool
+
+	// Breaks renders every line break in the post body's paragraphs as a
+	// <br>, for poetry and lyrics. It defaults to false.
+	Breaks bool
 }
 
+// Cover is a post's cover image. Image is a path relative to the post
+// directory and always lies under its media/ directory.
+type Cover struct {
+	Image string
+	Alt   string
+}
+
+// IsZero reports whether the post has no cover image.
+func (c Cover) IsZero() bool {
+	return c.Image == ""
+}
+
+// DecodeFrontMatter decodes and validates a post's front matter. Every
+// invalid field is reported, each as a FrontMatterError locating it within
+// data.
 func DecodeFrontMatter(data []byte) (FrontMatter, error) {
 	var raw struct {
-		Title string `yaml:"title"`
-		Slug  string `yaml:"slug"`
-		Date  string `yaml:"date"`
+		Title   string `yaml:"title"`
+		Slug    string `yaml:"slug"`
+		Date    string `yaml:"date"`
+		Updated string `yaml:"updated"`
+
+		Description string   `yaml:"description"`
+		Summary     string   `yaml:"summary"`
+		Tags        []string `yaml:"tags"`
+		Authors     []string `yaml:"authors"`
+		Cover       *struct {
+			Image string `yaml:"image"`
+			Alt   string `yaml:"alt"`
+		} `yaml:"cover"`
+		CanonicalURL string `yaml:"canonical_url"`
+		Lang         string `yaml:"lang"`
+		Series       string `yaml:"series"`
+		Draft        bool   `yaml:"draft"`
+
+		Typography *bool `yaml:"typography"`
+		Breaks     bool  `yaml:"breaks"`
 	}
 
-	dec := yaml.NewDecoder(bytes.NewReader(data))
-	dec.KnownFields(true)
+	var root yaml.Node
+	errs, err := decodeYAML(data, &root, &raw, ErrInvalidFrontMatter)
+	if err != nil {
+		return FrontMatter{}, err
+	}
 
-	if err := dec.Decode(&raw); err != nil {
-		return FrontMatter{}, fmt.Errorf("%w: %w", ErrInvalidFrontMatter, err)
+	fail := func(key string, err error) {
+		line, col := keyPosition(&root, key)
+		errs = append(errs, FrontMatterError{Err: err, Line: line, Column: col})
 	}
 
 	if strings.TrimSpace(raw.Title) == "" {
-		return FrontMatter{}, ErrMissingTitle
+		fail("", ErrMissingTitle)
 	}
 	if strings.TrimSpace(raw.Slug) == "" {
-		return FrontMatter{}, ErrMissingSlug
+		fail("", ErrMissingSlug)
 	}
+
+	var t time.Time
 	if strings.TrimSpace(raw.Date) == "" {
-		return FrontMatter{}, ErrMissingDate
+		fail("", ErrMissingDate)
+	} else if d, err := time.Parse("2006-01-02", raw.Date); err != nil {
+		fail("date", fmt.Errorf("%w (expected YYYY-MM-DD): %q", ErrInvalidDate, raw.Date))
+	} else {
+		t = d
+	}
+
+	var updated time.Time
+	if strings.TrimSpace(raw.Updated) != "" {
+		d, err := time.Parse("2006-01-02", raw.Updated)
+		switch {
+		case err != nil:
+			fail("updated", fmt.Errorf("%w (expected YYYY-MM-DD): updated: %q", ErrInvalidDate, raw.Updated))
+		case !t.IsZero() && d.Before(t):
+			fail("updated", fmt.Errorf("%w: %s before %s", ErrUpdatedBeforeDate, raw.Updated, raw.Date))
+		default:
+			updated = d
+		}
 	}
 
-	t, err := time.Parse("2006-01-02", raw.Date)
+	tags, err := decodeList("tags", raw.Tags)
 	if err != nil {
-		return FrontMatter{}, fmt.Errorf("%w (expected YYYY-MM-DD): %q", ErrInvalidDate, raw.Date)
+		fail("tags", err)
+	}
+
+	authors, err := decodeList("authors", raw.Authors)
+	if err != nil {
+		fail("authors", err)
+	}
+
+	var cover Cover
+	if raw.Cover != nil {
+		cover, err = decodeCover(raw.Cover.Image, raw.Cover.Alt)
+		if err != nil {
+			fail("cover", err)
+		}
+	}
+
+	canonical := strings.TrimSpace(raw.CanonicalURL)
+	if canonical != "" {
+		u, err := url.Parse(canonical)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("canonical_url", fmt.Errorf("%w: %q", ErrInvalidCanonicalURL, raw.CanonicalURL))
+		}
+	}
+
+	lang := strings.TrimSpace(raw.Lang)
+	if lang != "" && !isLanguageTag(lang) {
+		fail("lang", fmt.Errorf("%w: %q", ErrInvalidLang, raw.Lang))
+	}
+
+	if len(errs) > 0 {
+		return FrontMatter{}, errors.Join(errs...)
+	}
+
+	typography := true
+	if raw.Typography != nil {
+		typography = *raw.Typography
 	}
 
 	return FrontMatter{
-		Title: raw.Title,
-		Slug:  raw.Slug,
-		Date:  t,
+		Title:        raw.Title,
+		Slug:         raw.Slug,
+		Date:         t,
+		Updated:      updated,
+		Description:  strings.TrimSpace(raw.Description),
+		Summary:      strings.TrimSpace(raw.Summary),
+		Tags:         tags,
+		Authors:      authors,
+		Cover:        cover,
+		CanonicalURL: canonical,
+		Lang:         lang,
+		Series:       strings.TrimSpace(raw.Series),
+		Draft:        raw.Draft,
+		Typography:   typography,
+		Breaks:       raw.Breaks,
 	}, nil
 }
+
+// yamlLine matches the line number yaml prefixes to its error messages.
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+
+// yamlError converts an error from decoding YAML into one FrontMatterError
+// for each problem reported, located at its line and wrapping kind.
+func yamlError(err, kind error) error {
+	msgs := []string{err.Error()}
+
+	var terr *yaml.TypeError
+	if errors.As(err, &terr) {
+		msgs = terr.Errors
+	}
+
+	errs := make([]error, 0, len(msgs))
+	for _, msg := range msgs {
+		var fe FrontMatterError
+		if m := yamlLine.FindStringSubmatch(msg); m != nil {
+			fe.Line, _ = strconv.Atoi(m[1])
+			fe.Column = 1
+			msg = msg[len(m[0]):]
+		}
+
+		fe.Err = fmt.Errorf("%w: %s", kind, msg)
+		errs = append(errs, fe)
+	}
+
+	return errors.Join(errs...)
+}
+
+// decodeYAML parses data into root and decodes root into v, a pointer to a
+// struct. A key that names no field of v is not fatal: each is returned
+// among unknown as a FrontMatterError wrapping kind, so the caller can go
+// on validating the fields it did decode. Malformed YAML and values of the
+// wrong type are returned as err, along with any unknown keys.
+func decodeYAML(data []byte, root *yaml.Node, v any, kind error) (unknown []error, err error) {
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, yamlError(err, kind)
+	}
+	if root.Kind == 0 {
+		return nil, nil
+	}
+
+	unknown = unknownFields(root, reflect.TypeOf(v), kind)
+
+	if err := root.Decode(v); err != nil {
+		return nil, errors.Join(append(unknown, yamlError(err, kind))...)
+	}
+
+	return unknown, nil
+}
+
+// unknownFields reports each mapping key under n that names no field of
+// the struct type t decodes it into, located at the key.
+func unknownFields(n *yaml.Node, t reflect.Type, kind error) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	var errs []error
+	switch {
+	case n.Kind == yaml.DocumentNode:
+		for _, c := range n.Content {
+			errs = append(errs, unknownFields(c, t, kind)...)
+		}
+
+	case n.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			field, ok := yamlField(t, key.Value)
+			if !ok {
+				errs = append(errs, FrontMatterError{
+					Err:    fmt.Errorf("%w: unknown field %s", kind, key.Value),
+					Line:   key.Line,
+					Column: key.Column,
+				})
+				continue
+			}
+			errs = append(errs, unknownFields(n.Content[i+1], field.Type, kind)...)
+		}
+
+	case n.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			errs = append(errs, unknownFields(n.Content[i+1], t.Elem(), kind)...)
+		}
+
+	case n.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for _, c := range n.Content {
+			errs = append(errs, unknownFields(c, t.Elem(), kind)...)
+		}
+	}
+
+	return errs
+}
+
+// yamlField returns the field of the struct type t that the yaml key name
+// decodes into.
+func yamlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if tag == "" {
+			tag = strings.ToLower(f.Name)
+		}
+		if tag == name {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// fieldError returns err as a FrontMatterError located at the value of the
+// top-level key in the front matter data.
+func fieldError(data []byte, key string, err error) FrontMatterError {
+	var root yaml.Node
+	_ = yaml.Unmarshal(data, &root)
+	line, col := keyPosition(&root, key)
+
+	return FrontMatterError{Err: err, Line: line, Column: col}
+}
+
+// keyPosition returns the 1-based position of the value of the top-level
+// key in the front matter document root, or zeros when the key is empty
+// or absent.
+func keyPosition(root *yaml.Node, key string) (int, int) {
+	if key == "" || root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return 0, 0
+	}
+
+	m := root.Content[0]
+	if m.Kind != yaml.MappingNode {
+		return 0, 0
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			v := m.Content[i+1]
+			return v.Line, v.Column
+		}
+	}
+
+	return 0, 0
+}
+
+// decodeList trims each entry of the list named key, dropping duplicates
+// and rejecting empty entries.
+func decodeList(key string, list []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]struct{}, len(list))
+
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, fmt.Errorf("%w: %s", ErrEmptyListEntry, key)
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+
+	return out, nil
+}
+
+// decodeCover validates a cover image path, which must name a file inside
+// the post's media/ directory, and its alt text.
+func decodeCover(image, alt string) (Cover, error) {
+	image = strings.TrimSpace(image)
+	clean := path.Clean(image)
+	if image == "" || path.IsAbs(clean) || !strings.HasPrefix(clean, "media/") {
+		return Cover{}, fmt.Errorf("%w: %q", ErrInvalidCover, image)
+	}
+
+	alt = strings.TrimSpace(alt)
+	if alt == "" {
+		return Cover{}, ErrMissingCoverAlt
+	}
+
+	return Cover{Image: clean, Alt: alt}, nil
+}
+
+// isLanguageTag reports whether s is shaped like a BCP 47 language tag: a
+// primary language subtag of two or three letters followed by hyphenated
+// alphanumeric subtags of up to eight characters.
+func isLanguageTag(s string) bool {
+	parts := strings.Split(s, "-")
+
+	if n := len(parts[0]); n < 2 || n > 3 || !isLetters(parts[0]) {
+		return false
+	}
+
+	for _, p := range parts[1:] {
+		if p == "" || len(p) > 8 || !isAlnum(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		b := s[i] | 0x20
+		if b < 'a' || b > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isLetters(s[i:i+1]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
 )
 
 func TestDecodeFrontMatter(t *testing.T) {
@@ -23,12 +24,185 @@ func TestDecodeFrontMatter(t *testing.T) {
 				"slug: test-slug",
 			}, "\n")),
 			fm: FrontMatter{
-				Title: "test title",
-				Date:  time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
-				Slug:  "test-slug",
+				Title:      "test title",
+				Date:       time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
+				Slug:       "test-slug",
+				Typography: true,
 			},
 			wantErr: nil,
 		},
+		{
+			name: "typography false disables smart typography",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"typography: false",
+			}, "\n")),
+			fm: FrontMatter{
+				Title:      "test title",
+				Date:       time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
+				Slug:       "test-slug",
+				Typography: false,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "draft true marks the post as a draft",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"draft: true",
+			}, "\n")),
+			fm: FrontMatter{
+				Title:      "test title",
+				Date:       time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
+				Slug:       "test-slug",
+				Draft:      true,
+				Typography: true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "breaks true enables hard line breaks",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"breaks: true",
+			}, "\n")),
+			fm: FrontMatter{
+				Title:      "test title",
+				Date:       time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
+				Slug:       "test-slug",
+				Typography: true,
+				Breaks:     true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "extended fields are decoded and normalized",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"updated: 1987-07-01",
+				"slug: test-slug",
+				"description: '  A short summary. '",
+				"tags: [go, ' markdown ', go]",
+				"authors:",
+				"  - Sean",
+				"cover:",
+				"  image: media/cover.jpg",
+				"  alt: A cover",
+				"canonical_url: https://example.com/post/",
+				"lang: en-US",
+				"series: Compilers",
+			}, "\n")),
+			fm: FrontMatter{
+				Title:        "test title",
+				Date:         time.Date(1987, 06, 21, 0, 0, 0, 0, time.UTC),
+				Updated:      time.Date(1987, 07, 01, 0, 0, 0, 0, time.UTC),
+				Slug:         "test-slug",
+				Description:  "A short summary.",
+				Tags:         []string{"go", "markdown"},
+				Authors:      []string{"Sean"},
+				Cover:        Cover{Image: "media/cover.jpg", Alt: "A cover"},
+				CanonicalURL: "https://example.com/post/",
+				Lang:         "en-US",
+				Series:       "Compilers",
+				Typography:   true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "updated before date returns ErrUpdatedBeforeDate",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"updated: 1987-06-20",
+				"slug: test-slug",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrUpdatedBeforeDate,
+		},
+		{
+			name: "invalid updated date returns ErrInvalidDate",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"updated: soon",
+				"slug: test-slug",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrInvalidDate,
+		},
+		{
+			name: "empty tag returns ErrEmptyListEntry",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"tags: [go, '']",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrEmptyListEntry,
+		},
+		{
+			name: "cover outside media returns ErrInvalidCover",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"cover: {image: media/../secret.jpg, alt: A cover}",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrInvalidCover,
+		},
+		{
+			name: "cover without alt returns ErrMissingCoverAlt",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"cover: {image: media/cover.jpg}",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrMissingCoverAlt,
+		},
+		{
+			name: "misspelled cover key returns ErrInvalidFrontMatter",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"cover: {img: media/cover.jpg, alt: A cover}",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrInvalidFrontMatter,
+		},
+		{
+			name: "relative canonical url returns ErrInvalidCanonicalURL",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"canonical_url: /blog/test-slug/",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrInvalidCanonicalURL,
+		},
+		{
+			name: "malformed lang returns ErrInvalidLang",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"date: 1987-06-21",
+				"slug: test-slug",
+				"lang: english",
+			}, "\n")),
+			fm:      FrontMatter{},
+			wantErr: ErrInvalidLang,
+		},
 		{
 			name: "invalid yaml fields return ErrInvalidFrontMatter",
 			data: []byte(strings.Join([]string{
@@ -87,3 +261,80 @@ func TestDecodeFrontMatter(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeFrontMatter_ErrorPositions(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{
+			name: "every invalid field is reported at its value",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"slug: test-slug",
+				"date: 1987-06-21",
+				"tags: [a, '']",
+				"lang:   not a tag",
+			}, "\n")),
+			want: []string{
+				"line 4:7: frontmatter list contains an empty entry: tags",
+				`line 5:9: frontmatter lang is not a valid language tag: "not a tag"`,
+			},
+		},
+		{
+			name: "missing fields belong to the whole block",
+			data: []byte(strings.Join([]string{
+				"date: 1987-06-21",
+			}, "\n")),
+			want: []string{
+				"frontmatter is missing title",
+				"frontmatter is missing slug",
+			},
+		},
+		{
+			name: "unknown fields are reported at their line",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"tite: title with typo",
+				"date: 1987-06-21",
+				"slug: test-slug",
+			}, "\n")),
+			want: []string{
+				"line 2:1: frontmatter is malformed: unknown field tite",
+			},
+		},
+		{
+			name: "unknown fields do not hide other invalid fields",
+			data: []byte(strings.Join([]string{
+				"title: test title",
+				"slug: test-slug",
+				"date: 21/06/1987",
+				"cover: {image: media/cover.jpg, text: A cover}",
+				"colour: blue",
+			}, "\n")),
+			want: []string{
+				"line 4:33: frontmatter is malformed: unknown field text",
+				"line 5:1: frontmatter is malformed: unknown field colour",
+				`line 3:7: frontmatter contains an invalid date format (expected YYYY-MM-DD): "21/06/1987"`,
+				"line 4:8: frontmatter cover is missing alt text",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeFrontMatter(tc.data)
+			require.NotNil(t, err)
+
+			var got []string
+			for _, e := range leafErrors(err) {
+				var fe FrontMatterError
+				assert.ErrorAs(t, e, &fe)
+				got = append(got, e.Error())
+			}
+
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package content
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+)
+
+// imageSizer returns an ImageSizer that reads the intrinsic size of local
+// PNG, JPEG, and GIF images from their file headers, resolving relative
+// destinations against dir.
+//
+// Remote images, absolute paths, and files that cannot be read or decoded
+// are left unsized.
+func imageSizer(dir string) markdown.ImageSizer {
+	return func(ref markdown.URLRef) (markdown.ImageSize, bool) {
+		u, err := url.Parse(ref.URL)
+		if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || path.IsAbs(u.Path) {
+			return markdown.ImageSize{}, false
+		}
+
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(u.Path)))
+		if err != nil {
+			return markdown.ImageSize{}, false
+		}
+		defer f.Close()
+
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return markdown.ImageSize{}, false
+		}
+
+		return markdown.ImageSize{Width: cfg.Width, Height: cfg.Height}, true
+	}
+}
@@ -0,0 +1,103 @@
+package content
+
+import (
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestImageSizer(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+
+	writeImage(t, filepath.Join(dir, "media", "a.png"), func(w io.Writer) error {
+		return png.Encode(w, img)
+	})
+	writeImage(t, filepath.Join(dir, "media", "b.jpg"), func(w io.Writer) error {
+		return jpeg.Encode(w, img, nil)
+	})
+	writeImage(t, filepath.Join(dir, "c.gif"), func(w io.Writer) error {
+		return gif.Encode(w, img, nil)
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o644))
+
+	testCases := []struct {
+		name   string
+		url    string
+		want   markdown.ImageSize
+		wantOK bool
+	}{
+		{
+			name:   "png header",
+			url:    "media/a.png",
+			want:   markdown.ImageSize{Width: 40, Height: 30},
+			wantOK: true,
+		},
+		{
+			name:   "jpeg header",
+			url:    "media/b.jpg",
+			want:   markdown.ImageSize{Width: 40, Height: 30},
+			wantOK: true,
+		},
+		{
+			name:   "gif header with query",
+			url:    "c.gif?v=1",
+			want:   markdown.ImageSize{Width: 40, Height: 30},
+			wantOK: true,
+		},
+		{
+			name:   "missing file",
+			url:    "media/missing.png",
+			want:   markdown.ImageSize{},
+			wantOK: false,
+		},
+		{
+			name:   "undecodable file",
+			url:    "notes.txt",
+			want:   markdown.ImageSize{},
+			wantOK: false,
+		},
+		{
+			name:   "remote image",
+			url:    "https://example.com/a.png",
+			want:   markdown.ImageSize{},
+			wantOK: false,
+		},
+		{
+			name:   "absolute path",
+			url:    "/media/a.png",
+			want:   markdown.ImageSize{},
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := imageSizer(dir)(markdown.URLRef{Kind: markdown.URLImage, URL: tc.url})
+
+			assert.Equal(t, got, tc.want)
+			assert.Equal(t, ok, tc.wantOK)
+		})
+	}
+}
+
+func writeImage(t *testing.T, path string, encode func(io.Writer) error) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, encode(f))
+}
@@ -0,0 +1,90 @@
+package content
+
+import "sort"
+
+// PostRef identifies a post to link to.
+type PostRef struct {
+	Title string
+	Slug  string
+}
+
+// PostNav locates a post among the others. Prev is the post published
+// before it and Next the one after; either is nil at the ends. Series is
+// nil for a post outside any series.
+type PostNav struct {
+	Prev   *PostRef
+	Next   *PostRef
+	Series *SeriesNav
+}
+
+// SeriesNav lists the parts of a series, oldest first, and the position
+// of one post among them. Part counts from 1.
+type SeriesNav struct {
+	Name  string
+	Part  int
+	Parts []PostRef
+}
+
+// SortPosts orders posts newest first, in place. Posts dated the same day
+// are ordered by slug, so every listing and the links between posts agree.
+func SortPosts(posts []Post) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		return newerPost(posts[i], posts[j])
+	})
+}
+
+// newerPost reports whether a comes before b in the order of SortPosts.
+func newerPost(a, b Post) bool {
+	if !a.FrontMatter.Date.Equal(b.FrontMatter.Date) {
+		return a.FrontMatter.Date.After(b.FrontMatter.Date)
+	}
+	return a.FrontMatter.Slug < b.FrontMatter.Slug
+}
+
+// LinkPosts sets Nav on every post in place, linking each to its
+// chronological neighbours among posts and to the other parts of its
+// series. Chronological order is the reverse of SortPosts.
+func LinkPosts(posts []Post) {
+	order := make([]int, len(posts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return newerPost(posts[order[j]], posts[order[i]])
+	})
+
+	series := make(map[string][]PostRef)
+	for _, i := range order {
+		if name := posts[i].FrontMatter.Series; name != "" {
+			series[name] = append(series[name], postRef(posts[i]))
+		}
+	}
+
+	for n, i := range order {
+		var nav PostNav
+		if n > 0 {
+			prev := postRef(posts[order[n-1]])
+			nav.Prev = &prev
+		}
+		if n+1 < len(order) {
+			next := postRef(posts[order[n+1]])
+			nav.Next = &next
+		}
+
+		if name := posts[i].FrontMatter.Series; name != "" {
+			parts := series[name]
+			for k, ref := range parts {
+				if ref.Slug == posts[i].FrontMatter.Slug {
+					nav.Series = &SeriesNav{Name: name, Part: k + 1, Parts: parts}
+					break
+				}
+			}
+		}
+
+		posts[i].Nav = nav
+	}
+}
+
+func postRef(p Post) PostRef {
+	return PostRef{Title: p.FrontMatter.Title, Slug: p.FrontMatter.Slug}
+}
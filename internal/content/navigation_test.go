@@ -0,0 +1,77 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestSortPosts(t *testing.T) {
+	post := func(slug string, day int) Post {
+		return Post{FrontMatter: FrontMatter{
+			Slug: slug,
+			Date: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC),
+		}}
+	}
+
+	posts := []Post{
+		post("old", 1),
+		post("c-same-day", 3),
+		post("a-same-day", 3),
+		post("new", 5),
+		post("b-same-day", 3),
+	}
+
+	SortPosts(posts)
+
+	var slugs []string
+	for _, p := range posts {
+		slugs = append(slugs, p.FrontMatter.Slug)
+	}
+	assert.Equal(t, slugs, []string{"new", "a-same-day", "b-same-day", "c-same-day", "old"})
+}
+
+func TestLinkPosts(t *testing.T) {
+	post := func(slug string, day int, series string) Post {
+		return Post{FrontMatter: FrontMatter{
+			Title:  slug,
+			Slug:   slug,
+			Date:   time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC),
+			Series: series,
+		}}
+	}
+
+	posts := []Post{
+		post("part-2", 5, "Blog"),
+		post("b-same-day", 3, ""),
+		post("a-same-day", 3, ""),
+		post("part-1", 1, "Blog"),
+	}
+
+	LinkPosts(posts)
+
+	ref := func(slug string) *PostRef {
+		return &PostRef{Title: slug, Slug: slug}
+	}
+
+	// same-day posts are linked in the reverse of their index order
+	assert.Equal(t, posts[3].Nav.Prev, (*PostRef)(nil))
+	assert.Equal(t, posts[3].Nav.Next, ref("b-same-day"))
+	assert.Equal(t, posts[1].Nav.Next, ref("a-same-day"))
+	assert.Equal(t, posts[2].Nav.Prev, ref("b-same-day"))
+	assert.Equal(t, posts[0].Nav.Prev, ref("a-same-day"))
+	assert.Equal(t, posts[0].Nav.Next, (*PostRef)(nil))
+
+	assert.Equal(t, posts[1].Nav.Series, (*SeriesNav)(nil))
+
+	require.NotNil(t, posts[0].Nav.Series)
+	assert.Equal(t, *posts[0].Nav.Series, SeriesNav{
+		Name:  "Blog",
+		Part:  2,
+		Parts: []PostRef{*ref("part-1"), *ref("part-2")},
+	})
+	require.NotNil(t, posts[3].Nav.Series)
+	assert.Equal(t, posts[3].Nav.Series.Part, 1)
+}
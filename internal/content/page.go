@@ -0,0 +1,301 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	ErrMissingPath   = errors.New("frontmatter is missing path")
+	ErrInvalidPath   = errors.New("frontmatter path must be an absolute path of lowercase letters, digits, hyphens, and underscores")
+	ErrUnknownLayout = errors.New("frontmatter layout is not a known page layout")
+	ErrInvalidMenu   = errors.New("frontmatter menu must be a positive integer")
+	ErrReservedPath  = errors.New("page path is reserved for generated pages")
+	ErrDuplicatePath = errors.New("duplicate page path")
+)
+
+// Page layouts. LayoutPage renders the title above the body and is the
+// default; LayoutPlain renders the body alone.
+const (
+	LayoutPage  = "page"
+	LayoutPlain = "plain"
+)
+
+// Page is a standalone page served at a path of its own, outside the blog.
+type Page struct {
+	SourcePath   string
+	SourceDir    string
+	FrontMatter  PageFrontMatter
+	Body         string
+	BodyHTMLTree markdown.Document
+
+	// Warnings holds the diagnostics raised while compiling the body that
+	// did not prevent it from compiling.
+	Warnings []SourceError
+
+	// raw holds the full source file and bodyOffset the position of Body
+	// within it, so compile diagnostics can be located in the file.
+	raw        string
+	bodyOffset int
+}
+
+type PageFrontMatter struct {
+	Title       string
+	Description string
+
+	// Path is the URL path the page is served at. It begins and ends with
+	// a slash, and "/" replaces the home page.
+	Path string
+
+	// Layout names the layout the page is rendered with.
+	Layout string
+
+	// Menu orders the page among the site navigation links, lowest first.
+	// A page with no menu position is left out of the navigation.
+	Menu int
+
+	// Typography enables smart quotes, dashes, and ellipses for the page
+	// body. It defaults to true and can be disabled per page.
+	Typography bool
+}
+
+// DecodePageFrontMatter decodes and validates a page's front matter. Every
+// invalid field is reported, each as a FrontMatterError locating it within
+// data.
+func DecodePageFrontMatter(data []byte) (PageFrontMatter, error) {
+	var raw struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+		Path        string `yaml:"path"`
+		Layout      string `yaml:"layout"`
+		Menu        *int   `yaml:"menu"`
+		Typography  *bool  `yaml:"typography"`
+	}
+
+	var root yaml.Node
+	errs, err := decodeYAML(data, &root, &raw, ErrInvalidFrontMatter)
+	if err != nil {
+		return PageFrontMatter{}, err
+	}
+
+	if strings.TrimSpace(raw.Title) == "" {
+		errs = append(errs, FrontMatterError{Err: ErrMissingTitle})
+	}
+
+	path := strings.TrimSpace(raw.Path)
+	if path == "" {
+		errs = append(errs, FrontMatterError{Err: ErrMissingPath})
+	} else if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	if path != "" && !isPagePath(path) {
+		errs = append(errs, fieldError(data, "path", fmt.Errorf("%w: %q", ErrInvalidPath, raw.Path)))
+	}
+
+	layout := strings.TrimSpace(raw.Layout)
+	switch layout {
+	case "":
+		layout = LayoutPage
+	case LayoutPage, LayoutPlain:
+	default:
+		errs = append(errs, fieldError(data, "layout", fmt.Errorf("%w: %q", ErrUnknownLayout, raw.Layout)))
+	}
+
+	var menu int
+	if raw.Menu != nil {
+		menu = *raw.Menu
+		if menu <= 0 {
+			errs = append(errs, fieldError(data, "menu", fmt.Errorf("%w: %d", ErrInvalidMenu, menu)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return PageFrontMatter{}, errors.Join(errs...)
+	}
+
+	typography := true
+	if raw.Typography != nil {
+		typography = *raw.Typography
+	}
+
+	return PageFrontMatter{
+		Title:       raw.Title,
+		Description: strings.TrimSpace(raw.Description),
+		Path:        path,
+		Layout:      layout,
+		Menu:        menu,
+		Typography:  typography,
+	}, nil
+}
+
+// isPagePath reports whether path is "/" or a run of slash-terminated
+// segments of lowercase letters, digits, hyphens, and underscores.
+func isPagePath(path string) bool {
+	if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+		return false
+	}
+	if path == "/" {
+		return true
+	}
+
+	for _, seg := range strings.Split(path[1:len(path)-1], "/") {
+		if seg == "" {
+			return false
+		}
+		for i := 0; i < len(seg); i++ {
+			b := seg[i]
+			if (b < 'a' || b > 'z') && (b < '0' || b > '9') && b != '-' && b != '_' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// LoadPages loads each page in paths without compiling their bodies. It
+// returns every page that loaded, along with an ErrorList of the errors
+// raised by the rest.
+func LoadPages(paths []string) ([]Page, error) {
+	var pages []Page
+	var errs ErrorList
+	for _, s := range paths {
+		pg, err := LoadPage(s)
+		if err != nil {
+			errs = errs.Append(err)
+			continue
+		}
+		pages = append(pages, pg)
+	}
+
+	return pages, errs.Err()
+}
+
+// LoadPage reads the page at path and decodes its front matter, leaving the
+// Markdown body uncompiled. Errors are located within the file as they are
+// for LoadPost.
+func LoadPage(path string) (Page, error) {
+	path = filepath.Clean(path)
+
+	data, fmBytes, mdBytes, err := readSource(path)
+	if err != nil {
+		return Page{}, err
+	}
+
+	fm, err := DecodePageFrontMatter(fmBytes)
+	if err != nil {
+		return Page{}, frontMatterErrors(path, string(data), err)
+	}
+
+	page := Page{
+		SourcePath:  path,
+		SourceDir:   filepath.Dir(path),
+		FrontMatter: fm,
+		Body:        string(mdBytes),
+		raw:         string(data),
+		bodyOffset:  len(data) - len(mdBytes),
+	}
+
+	return page, nil
+}
+
+// CheckPagePaths reports every page served at a path already used by an
+// earlier page or lying under one of the reserved path prefixes, located
+// at the path in its front matter.
+func CheckPagePaths(pages []Page, reserved []string) error {
+	var errs ErrorList
+	seen := make(map[string]Page, len(pages))
+
+	for _, pg := range pages {
+		path := pg.FrontMatter.Path
+		fmBytes, _, _ := SplitPost([]byte(pg.raw))
+
+		var err error
+		if prev, ok := seen[path]; ok {
+			err = fmt.Errorf("%w: %q is also used by %s", ErrDuplicatePath, path, prev.SourcePath)
+		}
+		for _, prefix := range reserved {
+			if strings.HasPrefix(path, prefix) {
+				err = fmt.Errorf("%w: %q is under %s", ErrReservedPath, path, prefix)
+				break
+			}
+		}
+
+		if err != nil {
+			errs = errs.Append(frontMatterErrors(pg.SourcePath, pg.raw, fieldError(fmBytes, "path", err)))
+			continue
+		}
+		seen[path] = pg
+	}
+
+	return errs.Err()
+}
+
+// PageCompileOptions configures how page bodies are compiled. Its fields
+// behave as their counterparts in CompileOptions do for posts.
+type PageCompileOptions struct {
+	ResolveWikiLink markdown.WikiLinkResolver
+	RewriteURL      func(pg Page) markdown.URLRewriter
+	CheckOutline    bool
+	CheckURL        func(pg Page, ref markdown.URLRef) error
+}
+
+// CompilePages compiles the body of every page in place using opts. A page
+// that fails to compile is left as it was apart from its Warnings, and the
+// errors of all such pages are returned as an ErrorList.
+func CompilePages(pages []Page, opts PageCompileOptions) error {
+	var errs ErrorList
+	for i, pg := range pages {
+		compiled, err := CompilePage(pg, opts)
+		if err != nil {
+			errs = errs.Append(err)
+			pages[i].Warnings = compiled.Warnings
+			continue
+		}
+		pages[i] = compiled
+	}
+
+	return errs.Err()
+}
+
+// CompilePage compiles the Markdown body of pg using opts. Diagnostics are
+// located within the page's file. When the body fails to compile, the
+// returned page holds only the warnings raised.
+func CompilePage(pg Page, opts PageCompileOptions) (Page, error) {
+	var rewrite markdown.URLRewriter
+	if opts.RewriteURL != nil {
+		rewrite = opts.RewriteURL(pg)
+	}
+
+	var checkURL func(markdown.URLRef) error
+	if opts.CheckURL != nil {
+		checkURL = func(ref markdown.URLRef) error {
+			return opts.CheckURL(pg, ref)
+		}
+	}
+
+	mdOpts := markdown.Options{
+		Extensions:      bodyExtensions(pg.FrontMatter.Typography, false),
+		ResolveWikiLink: opts.ResolveWikiLink,
+		RewriteURL:      rewrite,
+		HeadingOffset:   headingOffset,
+		CheckOutline:    opts.CheckOutline,
+		Figures:         true,
+		LazyImages:      true,
+		ImageSize:       imageSizer(pg.SourceDir),
+	}
+
+	md, warnings, err := compileBody(pg.SourcePath, pg.raw, pg.bodyOffset, pg.Body, mdOpts, checkURL)
+	if err != nil {
+		return Page{Warnings: warnings}, err
+	}
+
+	pg.BodyHTMLTree = md
+	pg.Warnings = warnings
+	return pg, nil
+}
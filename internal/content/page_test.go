@@ -0,0 +1,164 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestDecodePageFrontMatter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []byte
+		fm      PageFrontMatter
+		wantErr error
+	}{
+		{
+			name: "valid page front matter with defaults",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: /about",
+			}, "\n")),
+			fm: PageFrontMatter{
+				Title:      "About",
+				Path:       "/about/",
+				Layout:     LayoutPage,
+				Typography: true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all fields decode",
+			data: []byte(strings.Join([]string{
+				"title: Uses",
+				"description: What I use.",
+				"path: /about/uses/",
+				"layout: plain",
+				"menu: 2",
+				"typography: false",
+			}, "\n")),
+			fm: PageFrontMatter{
+				Title:       "Uses",
+				Description: "What I use.",
+				Path:        "/about/uses/",
+				Layout:      LayoutPlain,
+				Menu:        2,
+				Typography:  false,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "root path is allowed",
+			data: []byte(strings.Join([]string{
+				"title: Home",
+				"path: /",
+			}, "\n")),
+			fm: PageFrontMatter{
+				Title:      "Home",
+				Path:       "/",
+				Layout:     LayoutPage,
+				Typography: true,
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "missing path returns ErrMissingPath",
+			data:    []byte("title: About"),
+			wantErr: ErrMissingPath,
+		},
+		{
+			name: "relative path returns ErrInvalidPath",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: about/",
+			}, "\n")),
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name: "path with uppercase or dot segments returns ErrInvalidPath",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: /About/../x/",
+			}, "\n")),
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name: "unknown layout returns ErrUnknownLayout",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: /about/",
+				"layout: wide",
+			}, "\n")),
+			wantErr: ErrUnknownLayout,
+		},
+		{
+			name: "non-positive menu returns ErrInvalidMenu",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: /about/",
+				"menu: 0",
+			}, "\n")),
+			wantErr: ErrInvalidMenu,
+		},
+		{
+			name: "post fields are unknown to pages",
+			data: []byte(strings.Join([]string{
+				"title: About",
+				"path: /about/",
+				"slug: about",
+			}, "\n")),
+			wantErr: ErrInvalidFrontMatter,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fm, err := DecodePageFrontMatter(tc.data)
+
+			assert.Equal(t, fm, tc.fm)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCheckPagePaths(t *testing.T) {
+	files := map[string]string{
+		"about/index.md":   "---\ntitle: About\npath: /about/\n---\n",
+		"index.md":         "---\ntitle: Home\npath: /\n---\n",
+		"again/index.md":   "---\ntitle: Again\npath: /about/\n---\n",
+		"blogged/index.md": "---\ntitle: Blog\npath: /blog/extra/\n---\n",
+	}
+
+	dir := filepath.Join(t.TempDir(), "pages")
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+
+	paths, err := DiscoverPages(filepath.Dir(dir))
+	require.NoError(t, err)
+	assert.Equal(t, len(paths), 4)
+
+	pages, err := LoadPages(paths)
+	require.NoError(t, err)
+
+	err = CheckPagePaths(pages, []string{"/blog/"})
+
+	var list ErrorList
+	assert.ErrorAs(t, err, &list)
+	assert.Equal(t, len(list), 2)
+	assert.ErrorIs(t, err, ErrDuplicatePath)
+	assert.ErrorIs(t, err, ErrReservedPath)
+}
+
+func TestDiscoverPages_MissingDirectory(t *testing.T) {
+	paths, err := DiscoverPages(t.TempDir())
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(paths), 0)
+}
@@ -3,10 +3,16 @@ package content
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
 var fence = []byte("---")
@@ -16,59 +22,485 @@ var (
 	ErrMissingOpeningFence       = errors.New("missing frontmatter opening fence")
 	ErrMissingClosingFence       = errors.New("missing frontmatter closing fence")
 	ErrOpeningFenceNotTerminated = errors.New("opening fence missing terminating newline")
+	ErrDuplicateSlug             = errors.New("duplicate slug")
+	ErrReservedSlug              = errors.New("slug is reserved for generated pages")
 )
 
 type Post struct {
 	SourcePath   string
 	SourceDir    string
 	FrontMatter  FrontMatter
+	Body         string
 	BodyHTMLTree markdown.Document
-}
 
-type PostSummary struct{}
+	// Summary describes the post in listings. It is set when the body is
+	// compiled.
+	Summary PostSummary
+
+	// Status records whether the post is published, a draft, or scheduled.
+	// It is set by SelectPosts.
+	Status Status
+
+	// Tags holds the post's tags after normalization. It is set by
+	// ApplyTags.
+	Tags []Tag
+
+	// Nav links the post to its neighbours and its series. It is set by
+	// LinkPosts.
+	Nav PostNav
+
+	// Warnings holds the diagnostics raised while compiling the body that
+	// did not prevent it from compiling.
+	Warnings []SourceError
 
-func ParsePosts(paths []string) ([]Post, error) {
+	// raw holds the full source file and bodyOffset the position of Body
+	// within it, so compile diagnostics can be located in the file.
+	raw        string
+	bodyOffset int
+}
+
+// LoadPosts loads each post in paths without compiling their bodies. It
+// returns every post that loaded, along with an ErrorList of the errors
+// raised by the rest.
+func LoadPosts(paths []string) ([]Post, error) {
 	var posts []Post
+	var errs ErrorList
 	for _, s := range paths {
-		p, err := ParsePost(s)
+		p, err := LoadPost(s)
 		if err != nil {
-			return nil, err
+			errs = errs.Append(err)
+			continue
 		}
 		posts = append(posts, p)
 	}
 
-	return posts, nil
+	return posts, errs.Err()
 }
 
-func ParsePost(path string) (Post, error) {
-	data, err := os.ReadFile(path)
+// LoadPost reads the post at path and decodes its front matter, leaving the
+// Markdown body uncompiled.
+//
+// Errors in the file's structure or front matter are returned as
+// SourceErrors located within the file, gathered in an ErrorList when
+// there is more than one.
+func LoadPost(path string) (Post, error) {
+	path = filepath.Clean(path)
+
+	data, fmBytes, mdBytes, err := readSource(path)
 	if err != nil {
 		return Post{}, err
 	}
 
-	fmBytes, mdBytes, err := SplitPost(data)
+	fm, err := DecodeFrontMatter(fmBytes)
 	if err != nil {
-		return Post{}, err
+		return Post{}, frontMatterErrors(path, string(data), err)
 	}
 
-	fm, err := DecodeFrontMatter(fmBytes)
+	if !fm.Cover.IsZero() {
+		cover := filepath.Join(filepath.Dir(path), filepath.FromSlash(fm.Cover.Image))
+		if info, err := os.Stat(cover); err != nil || !info.Mode().IsRegular() {
+			err := fieldError(fmBytes, "cover", fmt.Errorf("%w: %s", ErrMissingCoverFile, cover))
+			return Post{}, frontMatterErrors(path, string(data), err)
+		}
+	}
+
+	post := Post{
+		SourcePath:  path,
+		SourceDir:   filepath.Dir(path),
+		FrontMatter: fm,
+		Body:        string(mdBytes),
+		raw:         string(data),
+		bodyOffset:  len(data) - len(mdBytes),
+	}
+
+	return post, nil
+}
+
+// readSource reads the content file at path and splits it into its front
+// matter and Markdown body. A file without the fenced front matter layout
+// is reported as a SourceError located within it.
+func readSource(path string) (data, fmBytes, mdBytes []byte, err error) {
+	data, err = os.ReadFile(path)
 	if err != nil {
-		return Post{}, err
+		return nil, nil, nil, err
 	}
 
-	md, err := markdown.Compile(string(mdBytes))
+	fmBytes, mdBytes, err = SplitPost(data)
+	if err != nil {
+		if errors.Is(err, ErrEmptyFile) {
+			return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return nil, nil, nil, fileError(path, string(data), err, 0)
+	}
+
+	return data, fmBytes, mdBytes, nil
+}
+
+// CompileOptions configures how post bodies are compiled.
+//
+// ResolveWikiLink resolves wiki links against the post catalog. RewriteURL,
+// when set, returns the rewriter applied to link and image destinations in
+// a given post, so destinations may be resolved relative to that post.
+// Srcset likewise returns the resolver for a post's responsive image
+// variants. CheckOutline records a warning on the post for each level-1
+// heading or skipped heading level in its body.
+//
+// CheckURL, when set, is called with every link and image destination in a
+// post as written. Each error it returns fails the post, located at the
+// link or image.
+type CompileOptions struct {
+	ResolveWikiLink markdown.WikiLinkResolver
+	RewriteURL      func(p Post) markdown.URLRewriter
+	Srcset          func(p Post) markdown.SrcsetResolver
+	CheckOutline    bool
+	CheckURL        func(p Post, ref markdown.URLRef) error
+}
+
+// CompilePosts compiles the body of every post in place using opts. A post
+// that fails to compile is left as it was apart from its Warnings, and the
+// errors of all such posts are returned as an ErrorList.
+func CompilePosts(posts []Post, opts CompileOptions) error {
+	var errs ErrorList
+	for i, p := range posts {
+		compiled, err := CompilePost(p, opts)
+		if err != nil {
+			errs = errs.Append(err)
+			posts[i].Warnings = compiled.Warnings
+			continue
+		}
+		posts[i] = compiled
+	}
+
+	return errs.Err()
+}
+
+// CheckSlugs reports every post whose slug is already used by an earlier
+// post, located at the slug in its front matter.
+func CheckSlugs(posts []Post) error {
+	var errs ErrorList
+	seen := make(map[string]Post, len(posts))
+	for _, p := range posts {
+		slug := strings.TrimSpace(p.FrontMatter.Slug)
+		if prev, ok := seen[slug]; ok {
+			fmBytes, _, _ := SplitPost([]byte(p.raw))
+			err := fieldError(fmBytes, "slug", fmt.Errorf("%w: %q is also used by %s", ErrDuplicateSlug, slug, prev.SourcePath))
+			errs = errs.Append(frontMatterErrors(p.SourcePath, p.raw, err))
+			continue
+		}
+		seen[slug] = p
+	}
+
+	return errs.Err()
+}
+
+// CheckReservedSlugs reports every post whose slug reserved reports as
+// taken by a generated page, located at the slug in its front matter.
+func CheckReservedSlugs(posts []Post, reserved func(slug string) bool) error {
+	var errs ErrorList
+	for _, p := range posts {
+		slug := strings.TrimSpace(p.FrontMatter.Slug)
+		if reserved(slug) {
+			fmBytes, _, _ := SplitPost([]byte(p.raw))
+			err := fieldError(fmBytes, "slug", fmt.Errorf("%w: %q", ErrReservedSlug, slug))
+			errs = errs.Append(frontMatterErrors(p.SourcePath, p.raw, err))
+		}
+	}
+
+	return errs.Err()
+}
+
+// CompilePost compiles the Markdown body of p using opts. A diagnostic
+// raised by the compiler is returned as a SourceError located within the
+// post's file. When the body fails to compile, the returned post holds
+// only the warnings raised.
+func CompilePost(p Post, opts CompileOptions) (Post, error) {
+	var checkURL func(markdown.URLRef) error
+	if opts.CheckURL != nil {
+		checkURL = func(ref markdown.URLRef) error {
+			return opts.CheckURL(p, ref)
+		}
+	}
+
+	md, warnings, err := compileBody(p.SourcePath, p.raw, p.bodyOffset, p.Body, compileOptions(p, opts), checkURL)
+	if err != nil {
+		return Post{Warnings: warnings}, err
+	}
+
+	p.BodyHTMLTree = md
+	p.Summary = summarize(p, md)
+	p.Warnings = warnings
+	return p, nil
+}
+
+// compileBody compiles a Markdown body that begins at byte offset within
+// the file at path, whose contents are raw, returning the compiled document
+// and its warnings. Diagnostics are located within the file. The warnings
+// are returned even when the body fails to compile.
+//
+// checkURL, when set, is called with every link and image destination as
+// written; each error it returns is reported, located at the link or
+// image, and the body fails to compile. Every unknown wiki link and
+// rejected destination is reported, in the order they appear.
+func compileBody(path, raw string, offset int, body string, mdOpts markdown.Options, checkURL func(markdown.URLRef) error) (markdown.Document, []SourceError, error) {
+	var warnings []SourceError
+	mdOpts.Warn = func(d markdown.Diagnostic) {
+		warnings = append(warnings, bodyError(path, raw, offset, d))
+	}
+
+	var errs ErrorList
+	if checkURL != nil {
+		rewrite := mdOpts.RewriteURL
+		mdOpts.RewriteURL = func(ref markdown.URLRef) markdown.URLRewrite {
+			if err := checkURL(ref); err != nil {
+				serr := bodyError(path, raw, offset, diagnostic.Diagnostic{
+					Message:  err.Error(),
+					Span:     ref.Span,
+					Severity: diagnostic.SeverityError,
+				})
+				serr.Err = err
+				errs = append(errs, serr)
+			}
+
+			if rewrite == nil {
+				return markdown.URLRewrite{URL: ref.URL}
+			}
+			return rewrite(ref)
+		}
+	}
+
+	md, err := markdown.CompileWith(body, mdOpts)
+	if err != nil {
+		for _, e := range leafErrors(err) {
+			var derr diagnostic.DiagnosticError
+			if !errors.As(e, &derr) {
+				return nil, warnings, fmt.Errorf("%s: %w", path, err)
+			}
+			errs = append(errs, bodyError(path, raw, offset, derr.Diagnostic))
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].(SourceError).Diagnostic.Span.Start < errs[j].(SourceError).Diagnostic.Span.Start
+	})
+
+	if len(errs) == 1 {
+		return nil, warnings, errs[0]
+	}
+	if len(errs) > 1 {
+		return nil, warnings, errs
+	}
+
+	return md, warnings, nil
+}
+
+// ParsePost loads and compiles the post at path. Because a single post has
+// no catalog to resolve against, any wiki link in its body is reported as
+// unknown.
+func ParsePost(path string) (Post, error) {
+	p, err := LoadPost(path)
 	if err != nil {
 		return Post{}, err
 	}
 
-	post := Post{
-		SourcePath:   filepath.Clean(path),
-		SourceDir:    filepath.Dir(filepath.Clean(path)),
-		FrontMatter:  fm,
-		BodyHTMLTree: md,
+	return CompilePost(p, CompileOptions{})
+}
+
+// SourceError reports a diagnostic raised while loading or compiling a
+// post, located within the post's source file. Err, when set, is the error
+// the diagnostic describes.
+type SourceError struct {
+	Path       string
+	Source     *source.Source
+	Diagnostic diagnostic.Diagnostic
+	Err        error
+}
+
+// bodyError locates a diagnostic raised in a Markdown body that begins at
+// byte offset within the file at path, whose contents are raw.
+func bodyError(path, raw string, offset int, d diagnostic.Diagnostic) SourceError {
+	d.Span = source.ByteSpan{
+		Start: d.Span.Start + source.BytePos(offset),
+		End:   d.Span.End + source.BytePos(offset),
 	}
 
-	return post, nil
+	return SourceError{
+		Path:       path,
+		Source:     source.NewSource(raw),
+		Diagnostic: d,
+	}
+}
+
+func (e SourceError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, strings.TrimSuffix(e.Diagnostic.Format(e.Source), "\n"))
+}
+
+// Position returns the 1-based line and column of the diagnostic.
+func (e SourceError) Position() (int, int) {
+	line, col := e.Source.LineColumn(e.Diagnostic.Span.Start)
+	return line + 1, col + 1
+}
+
+func (e SourceError) Unwrap() []error {
+	errs := []error{diagnostic.DiagnosticError{Diagnostic: e.Diagnostic}}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// fileError returns err as a SourceError located at byte offset pos of the
+// file at path, whose contents are raw.
+func fileError(path, raw string, err error, pos int) SourceError {
+	return SourceError{
+		Path:   path,
+		Source: source.NewSource(raw),
+		Diagnostic: diagnostic.Diagnostic{
+			Message:  err.Error(),
+			Span:     source.ByteSpan{Start: source.BytePos(pos), End: source.BytePos(pos)},
+			Severity: diagnostic.SeverityError,
+		},
+		Err: err,
+	}
+}
+
+// frontMatterErrors locates each FrontMatterError in err within the file at
+// path, whose contents are raw. An error belonging to the front matter as a
+// whole is located at the opening fence.
+func frontMatterErrors(path, raw string, err error) error {
+	// the front matter begins on the line after the opening fence
+	return locateYAMLErrors(path, raw, err, 1)
+}
+
+// locateYAMLErrors locates each FrontMatterError in err within the file at
+// path, whose contents are raw and whose YAML begins on the 0-based line
+// first. An error without a line is located at the start of the file.
+func locateYAMLErrors(path, raw string, err error, first int) error {
+	var errs ErrorList
+	for _, e := range leafErrors(err) {
+		var fe FrontMatterError
+		if !errors.As(e, &fe) {
+			errs = errs.Append(fmt.Errorf("%s: %w", path, e))
+			continue
+		}
+
+		pos := 0
+		if fe.Line > 0 {
+			src := source.NewSource(raw)
+			line := src.LineSpan(first + fe.Line - 1)
+			pos = int(min(line.Start+source.BytePos(max(fe.Column-1, 0)), line.End))
+		}
+
+		errs = append(errs, fileError(path, raw, fe.Err, pos))
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errs
+}
+
+// leafErrors flattens errors joined with errors.Join.
+func leafErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []error
+		for _, e := range joined.Unwrap() {
+			out = append(out, leafErrors(e)...)
+		}
+		return out
+	}
+
+	return []error{err}
+}
+
+// ErrorList collects the errors raised across a set of posts, in the order
+// they were found.
+type ErrorList []error
+
+// Append adds err to the list, flattening another ErrorList into it.
+func (l ErrorList) Append(err error) ErrorList {
+	var list ErrorList
+	if errors.As(err, &list) {
+		return append(l, list...)
+	}
+	return append(l, err)
+}
+
+// Err returns the list as an error, or nil when it is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, err := range l {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+// headingOffset shifts body headings below the post title, which is
+// rendered one level beneath the site heading, so a body's ## sections
+// render as <h3>.
+const headingOffset = 1
+
+// compileOptions returns the Markdown options used for the body of p.
+func compileOptions(p Post, opts CompileOptions) markdown.Options {
+	var rewrite markdown.URLRewriter
+	if opts.RewriteURL != nil {
+		rewrite = opts.RewriteURL(p)
+	}
+
+	var srcset markdown.SrcsetResolver
+	if opts.Srcset != nil {
+		srcset = opts.Srcset(p)
+	}
+
+	return markdown.Options{
+		Extensions:      bodyExtensions(p.FrontMatter.Typography, p.FrontMatter.Breaks),
+		ResolveWikiLink: opts.ResolveWikiLink,
+		RewriteURL:      rewrite,
+		HeadingOffset:   headingOffset,
+		CheckOutline:    opts.CheckOutline,
+		Figures:         true,
+		LazyImages:      true,
+		ImageSize:       imageSizer(p.SourceDir),
+		Srcset:          srcset,
+	}
+}
+
+// bodyExtensions returns the Markdown extensions enabled for content
+// bodies, with smart typography and hard line breaks as requested.
+func bodyExtensions(typography, breaks bool) extension.Set {
+	exts := extension.Math |
+		extension.Callouts |
+		extension.DefinitionLists |
+		extension.Attributes |
+		extension.Autolinks |
+		extension.Superscript |
+		extension.Subscript |
+		extension.Strikethrough |
+		extension.Highlight |
+		extension.Insert |
+		extension.Emoji |
+		extension.EmojiLabels |
+		extension.Abbreviations |
+		extension.WikiLinks |
+		extension.LineBlocks
+	if typography {
+		exts = exts.With(extension.Typography)
+	}
+	if breaks {
+		exts = exts.With(extension.HardBreaks)
+	}
+
+	return exts
 }
 
 func SplitPost(src []byte) (fmBytes, mdBytes []byte, err error) {
@@ -1,10 +1,16 @@
 package content
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
 	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
 )
 
 func TestSplitPost(t *testing.T) {
@@ -95,3 +101,315 @@ func TestSplitPost(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadPost_Cover(t *testing.T) {
+	post := strings.Join([]string{
+		"---",
+		"title: Hello",
+		"slug: hello",
+		"date: 2026-02-17",
+		"cover: {image: media/cover.jpg, alt: A cover}",
+		"---",
+		"Hello.",
+		"",
+	}, "\n")
+
+	testCases := []struct {
+		name    string
+		files   []string
+		wantErr error
+	}{
+		{
+			name:    "existing cover file loads",
+			files:   []string{"media/cover.jpg"},
+			wantErr: nil,
+		},
+		{
+			name:    "missing cover file returns ErrMissingCoverFile",
+			files:   nil,
+			wantErr: ErrMissingCoverFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tc.files {
+				path := filepath.Join(dir, filepath.FromSlash(f))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, nil, 0o644))
+			}
+
+			path := filepath.Join(dir, "index.md")
+			require.NoError(t, os.WriteFile(path, []byte(post), 0o644))
+
+			p, err := LoadPost(path)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, p.FrontMatter.Cover, Cover{Image: "media/cover.jpg", Alt: "A cover"})
+			}
+		})
+	}
+}
+
+func TestCompilePost(t *testing.T) {
+	post := strings.Join([]string{
+		"---",
+		"title: Hello",
+		"slug: hello",
+		"date: 2026-02-17",
+		"---",
+		"See [[other]] and [[missing]].",
+		"",
+	}, "\n")
+
+	resolve := func(slug string) (markdown.WikiLinkTarget, bool) {
+		if slug != "other" {
+			return markdown.WikiLinkTarget{}, false
+		}
+		return markdown.WikiLinkTarget{URL: "/blog/other/", Title: "Other"}, true
+	}
+
+	testCases := []struct {
+		name    string
+		resolve markdown.WikiLinkResolver
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "known wiki links resolve",
+			resolve: resolve,
+			body:    "See [[other]].",
+			wantErr: "",
+		},
+		{
+			name:    "unknown wiki link is located in the file",
+			resolve: resolve,
+			body:    "See [[other]] and [[missing]].",
+			wantErr: strings.Join([]string{
+				`hello.md: unknown wiki link target "missing" at 6:21`,
+				"  |",
+				"6 | See [[other]] and [[missing]].",
+				"  |                     ^",
+			}, "\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := strings.Replace(post, "See [[other]] and [[missing]].", tc.body, 1)
+			path := filepath.Join(t.TempDir(), "hello.md")
+			require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+			p, err := LoadPost(path)
+			require.NoError(t, err)
+
+			_, err = CompilePost(p, CompileOptions{ResolveWikiLink: tc.resolve})
+
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var serr SourceError
+			assert.ErrorAs(t, err, &serr)
+			assert.Equal(t, strings.TrimPrefix(err.Error(), filepath.Dir(path)+string(filepath.Separator)), tc.wantErr)
+		})
+	}
+}
+
+func TestCompilePost_OutlineWarnings(t *testing.T) {
+	post := strings.Join([]string{
+		"---",
+		"title: Hello",
+		"slug: hello",
+		"date: 2026-02-17",
+		"---",
+		"# Intro",
+		"",
+		"#### Detail",
+		"",
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "hello.md")
+	require.NoError(t, os.WriteFile(path, []byte(post), 0o644))
+
+	p, err := LoadPost(path)
+	require.NoError(t, err)
+
+	unchecked, err := CompilePost(p, CompileOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, len(unchecked.Warnings), 0)
+
+	checked, err := CompilePost(p, CompileOptions{CheckOutline: true})
+	require.NoError(t, err)
+
+	var got []string
+	for _, w := range checked.Warnings {
+		line, _, _ := strings.Cut(strings.TrimPrefix(w.Error(), filepath.Dir(path)+string(filepath.Separator)), "\n")
+		got = append(got, line)
+	}
+
+	assert.Equal(t, got, []string{
+		"hello.md: level-1 heading competes with the document title at 6:1",
+		"hello.md: heading level 4 skips level 2 at 8:1",
+	})
+}
+
+func TestLoadPosts_CollectsErrors(t *testing.T) {
+	files := map[string]string{
+		"a/index.md": "---\ntitle: A\nslug: a\ndate: 2026-02-17\n---\nA.\n",
+		"b/index.md": "---\nslug: b\ndate: 2026-02-30\n---\nB.\n",
+		"c/index.md": "no front matter\n",
+		"d/index.md": "---\ntitle: D\nslug: a\ndate: 2026-02-17\n---\nD.\n",
+	}
+
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a/index.md", "b/index.md", "c/index.md", "d/index.md"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(files[name]), 0o644))
+		paths = append(paths, path)
+	}
+
+	posts, err := LoadPosts(paths)
+
+	var list ErrorList
+	assert.ErrorAs(t, err, &list)
+	assert.Equal(t, len(posts), 2)
+	assert.ErrorIs(t, err, ErrMissingTitle)
+	assert.ErrorIs(t, err, ErrInvalidDate)
+	assert.ErrorIs(t, err, ErrMissingOpeningFence)
+
+	var got []string
+	for _, e := range list {
+		var serr SourceError
+		assert.ErrorAs(t, e, &serr)
+		first, _, _ := strings.Cut(e.Error(), "\n")
+		got = append(got, strings.TrimPrefix(first, dir+string(filepath.Separator)))
+	}
+
+	assert.Equal(t, got, []string{
+		filepath.FromSlash("b/index.md") + ": frontmatter is missing title at 1:1",
+		filepath.FromSlash("b/index.md") + `: frontmatter contains an invalid date format (expected YYYY-MM-DD): "2026-02-30" at 3:7`,
+		filepath.FromSlash("c/index.md") + ": missing frontmatter opening fence at 1:1",
+	})
+
+	err = CheckSlugs(posts)
+
+	assert.ErrorIs(t, err, ErrDuplicateSlug)
+	first, _, _ := strings.Cut(err.Error(), "\n")
+	assert.Equal(t, strings.TrimPrefix(first, dir+string(filepath.Separator)),
+		filepath.FromSlash("d/index.md")+`: duplicate slug: "a" is also used by `+paths[0]+" at 3:7")
+}
+
+func TestCheckReservedSlugs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.md")
+	data := "---\ntitle: Post\nslug: page\ndate: 2025-03-01\n---\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	p, err := LoadPost(path)
+	require.NoError(t, err)
+
+	err = CheckReservedSlugs([]Post{p}, func(slug string) bool { return slug == "page" })
+
+	var serr SourceError
+	require.ErrorAs(t, err, &serr)
+	assert.ErrorIs(t, err, ErrReservedSlug)
+
+	line, col := serr.Position()
+	assert.Equal(t, line, 3)
+	assert.Equal(t, col, 7)
+
+	assert.NoError(t, CheckReservedSlugs([]Post{p}, func(string) bool { return false }))
+}
+
+func TestCompilePost_CheckURL(t *testing.T) {
+	post := strings.Join([]string{
+		"---",
+		"title: Hello",
+		"slug: hello",
+		"date: 2026-02-17",
+		"---",
+		"See [ok](ok.txt) and ![bad](bad.png).",
+		"",
+	}, "\n")
+
+	errBad := errors.New("bad destination")
+	check := func(p Post, ref markdown.URLRef) error {
+		if ref.URL == "bad.png" {
+			return errBad
+		}
+		return nil
+	}
+
+	path := filepath.Join(t.TempDir(), "hello.md")
+	require.NoError(t, os.WriteFile(path, []byte(post), 0o644))
+
+	p, err := LoadPost(path)
+	require.NoError(t, err)
+
+	_, err = CompilePost(p, CompileOptions{CheckURL: check})
+
+	var serr SourceError
+	assert.ErrorAs(t, err, &serr)
+	assert.ErrorIs(t, err, errBad)
+
+	line, col := serr.Position()
+	assert.Equal(t, line, 6)
+	assert.Equal(t, col, 22)
+}
+
+func TestCompilePost_ReportsEveryError(t *testing.T) {
+	post := strings.Join([]string{
+		"---",
+		"title: Hello",
+		"slug: hello",
+		"date: 2026-02-17",
+		"---",
+		"#### Detail",
+		"",
+		"See [[missing]], ![bad](bad.png), and [[gone]].",
+		"",
+	}, "\n")
+
+	errBad := errors.New("bad destination")
+	check := func(p Post, ref markdown.URLRef) error {
+		if ref.URL == "bad.png" {
+			return errBad
+		}
+		return nil
+	}
+
+	path := filepath.Join(t.TempDir(), "hello.md")
+	require.NoError(t, os.WriteFile(path, []byte(post), 0o644))
+
+	p, err := LoadPost(path)
+	require.NoError(t, err)
+
+	posts := []Post{p}
+	err = CompilePosts(posts, CompileOptions{CheckOutline: true, CheckURL: check})
+
+	var list ErrorList
+	require.ErrorAs(t, err, &list)
+
+	var got []string
+	for _, e := range list {
+		var serr SourceError
+		require.ErrorAs(t, e, &serr)
+		line, col := serr.Position()
+		got = append(got, fmt.Sprintf("%d:%d %s", line, col, serr.Diagnostic.Message))
+	}
+
+	assert.Equal(t, got, []string{
+		`8:7 unknown wiki link target "missing"`,
+		"8:18 bad destination",
+		`8:41 unknown wiki link target "gone"`,
+	})
+	assert.ErrorIs(t, err, errBad)
+
+	require.Equal(t, len(posts[0].Warnings), 1)
+	assert.Equal(t, posts[0].Warnings[0].Diagnostic.Message, "heading level 4 skips level 2")
+}
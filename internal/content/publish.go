@@ -0,0 +1,82 @@
+package content
+
+import "time"
+
+// Status reports whether a post is published.
+type Status int
+
+const (
+	// Published posts are included in every build.
+	Published Status = iota
+
+	// Draft posts set draft: true in their front matter.
+	Draft
+
+	// Scheduled posts are dated after the day of the build.
+	Scheduled
+)
+
+func (s Status) String() string {
+	switch s {
+	case Published:
+		return "published"
+	case Draft:
+		return "draft"
+	case Scheduled:
+		return "scheduled"
+	default:
+		return "unknown"
+	}
+}
+
+// SelectOptions chooses which unpublished posts a build includes.
+//
+// Now is the moment the build is published at. A post is scheduled when
+// its date falls after the calendar day of Now in Now's location.
+type SelectOptions struct {
+	Drafts bool
+	Future bool
+	Now    time.Time
+}
+
+// StatusAt returns the status of p for a build published at now. A draft
+// dated in the future is reported as a draft.
+func (p Post) StatusAt(now time.Time) Status {
+	switch {
+	case p.FrontMatter.Draft:
+		return Draft
+	case datedAfter(p, now):
+		return Scheduled
+	default:
+		return Published
+	}
+}
+
+// SelectPosts returns the posts a build made with opts includes, recording
+// the status of each. Drafts are included only when opts.Drafts is set and
+// future-dated posts only when opts.Future is set, so a future-dated draft
+// requires both.
+func SelectPosts(posts []Post, opts SelectOptions) []Post {
+	var out []Post
+	for _, p := range posts {
+		if p.FrontMatter.Draft && !opts.Drafts {
+			continue
+		}
+		if datedAfter(p, opts.Now) && !opts.Future {
+			continue
+		}
+
+		p.Status = p.StatusAt(opts.Now)
+		out = append(out, p)
+	}
+
+	return out
+}
+
+// datedAfter reports whether p is dated after the calendar day of now.
+// Post dates carry no time zone, so the day is taken in now's location.
+func datedAfter(p Post, now time.Time) bool {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return p.FrontMatter.Date.After(today)
+}
@@ -0,0 +1,89 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+)
+
+func TestSelectPosts(t *testing.T) {
+	post := func(slug, date string, draft bool) Post {
+		d, _ := time.Parse("2006-01-02", date)
+		return Post{FrontMatter: FrontMatter{Slug: slug, Date: d, Draft: draft}}
+	}
+
+	posts := []Post{
+		post("past", "2026-10-18", false),
+		post("today", "2026-10-19", false),
+		post("future", "2026-10-20", false),
+		post("draft", "2026-10-18", true),
+		post("future-draft", "2026-10-20", true),
+	}
+
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		opts SelectOptions
+		want map[string]Status
+	}{
+		{
+			name: "default excludes drafts and scheduled posts",
+			opts: SelectOptions{Now: now},
+			want: map[string]Status{
+				"past":  Published,
+				"today": Published,
+			},
+		},
+		{
+			name: "drafts includes past-dated drafts",
+			opts: SelectOptions{Drafts: true, Now: now},
+			want: map[string]Status{
+				"past":  Published,
+				"today": Published,
+				"draft": Draft,
+			},
+		},
+		{
+			name: "future includes scheduled posts",
+			opts: SelectOptions{Future: true, Now: now},
+			want: map[string]Status{
+				"past":   Published,
+				"today":  Published,
+				"future": Scheduled,
+			},
+		},
+		{
+			name: "drafts and future include future-dated drafts",
+			opts: SelectOptions{Drafts: true, Future: true, Now: now},
+			want: map[string]Status{
+				"past":         Published,
+				"today":        Published,
+				"future":       Scheduled,
+				"draft":        Draft,
+				"future-draft": Draft,
+			},
+		},
+		{
+			name: "now is compared by calendar day in its own location",
+			opts: SelectOptions{Now: time.Date(2026, 10, 20, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))},
+			want: map[string]Status{
+				"past":   Published,
+				"today":  Published,
+				"future": Published,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := make(map[string]Status)
+			for _, p := range SelectPosts(posts, tc.opts) {
+				got[p.FrontMatter.Slug] = p.Status
+			}
+
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	ErrEmptySlug   = errors.New("title yields an empty slug")
+	ErrInvalidSlug = errors.New("slug must be lowercase letters and digits separated by single hyphens")
+	ErrSlugInUse   = errors.New("slug is already in use")
+	ErrInvalidPost = errors.New("scaffolded post is invalid")
+)
+
+// defaultArchetype is the post template used when the content directory
+// has no archetype for posts.
+const defaultArchetype = `---
+title: {{ quote .Title }}
+slug: {{ quote .Slug }}
+date: {{ quote .Date }}
+draft: true
+---
+
+`
+
+// NewPostOptions describes a post to scaffold. Slug defaults to the slug
+// derived from Title, and Date to the current day. A given Slug must
+// already be in the form Slugify produces.
+type NewPostOptions struct {
+	Title string
+	Slug  string
+	Date  time.Time
+}
+
+// NewPost scaffolds a draft post under contentRoot/posts/<slug>, writing
+// its index.md and an empty media directory, and returns the path of the
+// index.md.
+//
+// The file is rendered from contentRoot/archetypes/post.md when it exists,
+// or else from a default holding only the front matter. An archetype is a
+// text/template given the post's Title, Slug, and Date (as YYYY-MM-DD),
+// with a quote function that quotes a value as a YAML string. The rendered
+// file must be a valid post.
+//
+// NewPost refuses a slug already used by a discovered post, as its
+// directory name or its front matter slug, or a directory that already
+// exists.
+func NewPost(contentRoot string, opts NewPostOptions) (string, error) {
+	slug := opts.Slug
+	if slug == "" {
+		slug = Slugify(opts.Title)
+		if slug == "" {
+			return "", fmt.Errorf("%w: %q", ErrEmptySlug, opts.Title)
+		}
+	} else if Slugify(slug) != slug {
+		// the slug names the post's directory, so it must not escape it
+		return "", fmt.Errorf("%w: %q", ErrInvalidSlug, slug)
+	}
+
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	if err := checkSlugFree(contentRoot, slug); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(contentRoot, "posts", slug)
+	if _, err := os.Stat(dir); err == nil {
+		return "", fmt.Errorf("%w: %s already exists", ErrSlugInUse, dir)
+	}
+
+	data, err := renderArchetype(contentRoot, map[string]string{
+		"Title": opts.Title,
+		"Slug":  slug,
+		"Date":  date.Format("2006-01-02"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fmBytes, _, err := SplitPost(data)
+	if err == nil {
+		_, err = DecodeFrontMatter(fmBytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPost, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "media"), 0o755); err != nil {
+		return "", fmt.Errorf("mkdir: %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, "index.md")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("write: %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// checkSlugFree reports an error if a post discovered under contentRoot
+// already uses slug, either as its directory name or as the slug in its
+// front matter. A post whose front matter fails to load is still checked
+// by the slug it gives, if any.
+func checkSlugFree(contentRoot, slug string) error {
+	paths, err := DiscoverPosts(contentRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if filepath.Base(filepath.Dir(path)) == slug || rawSlug(path) == slug {
+			return fmt.Errorf("%w: %q by %s", ErrSlugInUse, slug, path)
+		}
+	}
+
+	return nil
+}
+
+// rawSlug returns the slug given in the front matter of the post at path
+// without validating the rest of it, or "" when none can be read.
+func rawSlug(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	fmBytes, _, err := SplitPost(data)
+	if err != nil {
+		return ""
+	}
+
+	var fm struct {
+		Slug string `yaml:"slug"`
+	}
+	if err := yaml.Unmarshal(fmBytes, &fm); err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(fm.Slug)
+}
+
+// renderArchetype renders the post archetype in contentRoot, or the
+// default archetype when there is none, with data.
+func renderArchetype(contentRoot string, data map[string]string) ([]byte, error) {
+	text := defaultArchetype
+	name := filepath.Join(contentRoot, "archetypes", "post.md")
+
+	b, err := os.ReadFile(name)
+	switch {
+	case err == nil:
+		text = string(b)
+	case errors.Is(err, os.ErrNotExist):
+		name = "default archetype"
+	default:
+		return nil, fmt.Errorf("read: %s: %w", name, err)
+	}
+
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"quote": quoteYAML,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("archetype: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("archetype: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// quoteYAML returns s as a double-quoted YAML scalar.
+func quoteYAML(s string) (string, error) {
+	node := yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: s}
+	b, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// Slugify derives an ASCII URL slug from title: letters are lowercased,
+// accented Latin letters lose their accents, ASCII letters and digits are
+// kept, apostrophes and combining marks are dropped, and every other run of
+// characters, including letters with no ASCII spelling, becomes a single
+// hyphen.
+func Slugify(title string) string {
+	var sb strings.Builder
+	pending := false
+
+	for _, r := range title {
+		r = unicode.ToLower(r)
+
+		var word string
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			word = string(r)
+		case r == '\'' || r == '’' || unicode.Is(unicode.Mn, r):
+			// contractions and possessives stay one word, and a
+			// combining accent belongs to the letter before it
+			continue
+		default:
+			word = latinFolds[r]
+		}
+
+		if word == "" {
+			pending = true
+			continue
+		}
+
+		if pending && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		pending = false
+		sb.WriteString(word)
+	}
+
+	return sb.String()
+}
+
+// latinFolds maps the lowercase accented and ligature letters of the Latin-1
+// Supplement and Latin Extended-A blocks to their ASCII spellings.
+var latinFolds = func() map[rune]string {
+	folds := map[rune]string{
+		'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d", 'ĳ': "ij", 'ĸ': "k",
+	}
+
+	for _, group := range []string{
+		"aàáâãäåāăą", "cçćĉċč", "dďđ", "eèéêëēĕėęě", "gĝğġģ", "hĥħ",
+		"iìíîïĩīĭįı", "jĵ", "kķ", "lĺļľŀł", "nñńņňŉŋ", "oòóôõöøōŏő",
+		"rŕŗř", "sśŝşšſ", "tţťŧ", "uùúûüũūŭůűų", "wŵ", "yýÿŷ", "zźżž",
+	} {
+		base := group[:1]
+		for _, r := range group[1:] {
+			folds[r] = base
+		}
+	}
+
+	return folds
+}()
@@ -0,0 +1,200 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{
+			name:  "words are lowercased and hyphenated",
+			title: "Hello, World",
+			want:  "hello-world",
+		},
+		{
+			name:  "apostrophes are dropped",
+			title: "Don't Panic: It’s Fine",
+			want:  "dont-panic-its-fine",
+		},
+		{
+			name:  "leading, trailing, and repeated separators collapse",
+			title: "  --Go 1.26 -- Notes!  ",
+			want:  "go-1-26-notes",
+		},
+		{
+			name:  "accented latin letters lose their accents",
+			title: "Café Society à Łódź",
+			want:  "cafe-society-a-lodz",
+		},
+		{
+			name:  "ligatures and sharp s are spelled out",
+			title: "Straße Ærø Œuvre",
+			want:  "strasse-aero-oeuvre",
+		},
+		{
+			name:  "combining accents are dropped",
+			title: "Cafe\u0301s",
+			want:  "cafes",
+		},
+		{
+			name:  "letters with no ascii spelling separate words",
+			title: "Go 日本語 Notes",
+			want:  "go-notes",
+		},
+		{
+			name:  "non-ascii digits separate words",
+			title: "Part ٣ Two",
+			want:  "part-two",
+		},
+		{
+			name:  "non-latin title yields an empty slug",
+			title: "Привет",
+			want:  "",
+		},
+		{
+			name:  "punctuation only yields an empty slug",
+			title: "?!",
+			want:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, Slugify(tc.title), tc.want)
+		})
+	}
+}
+
+func TestNewPost(t *testing.T) {
+	existing := map[string]string{
+		"elsewhere/index.md":          "---\ntitle: Taken\nslug: taken-slug\ndate: 2026-01-01\n---\nBody.\n",
+		"2025/archived-name/index.md": "---\ntitle: Archived\nslug: renamed\ndate: 2025-01-01\n---\nBody.\n",
+		"unreadable/index.md":         "---\ntitle: Broken\nslug: broken-slug\ndate: someday\n---\nBody.\n",
+	}
+	date := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		opts      NewPostOptions
+		archetype string
+		want      string
+		wantErr   error
+	}{
+		{
+			name: "default archetype writes a draft dated today",
+			opts: NewPostOptions{Title: `Say "Hi": A Post`, Date: date},
+			want: "---\ntitle: \"Say \\\"Hi\\\": A Post\"\nslug: \"say-hi-a-post\"\ndate: \"2026-10-19\"\ndraft: true\n---\n\n",
+		},
+		{
+			name:      "archetype in content directory is rendered",
+			opts:      NewPostOptions{Title: "Notes", Date: date},
+			archetype: "---\ntitle: {{ quote .Title }}\nslug: {{ .Slug }}\ndate: {{ .Date }}\ndraft: true\ntags: [notes]\n---\n## {{ .Title }}\n",
+			want:      "---\ntitle: \"Notes\"\nslug: notes\ndate: 2026-10-19\ndraft: true\ntags: [notes]\n---\n## Notes\n",
+		},
+		{
+			name:    "slug used by an existing post is refused",
+			opts:    NewPostOptions{Title: "Taken Slug", Date: date},
+			wantErr: ErrSlugInUse,
+		},
+		{
+			name:    "slug used by a post that fails to load is refused",
+			opts:    NewPostOptions{Title: "Broken Slug", Date: date},
+			wantErr: ErrSlugInUse,
+		},
+		{
+			name:    "slug naming the directory of an existing post is refused",
+			opts:    NewPostOptions{Title: "Archived Name", Date: date},
+			wantErr: ErrSlugInUse,
+		},
+		{
+			name: "accented title yields an ascii slug",
+			opts: NewPostOptions{Title: "Café", Date: date},
+			want: "---\ntitle: \"Café\"\nslug: \"cafe\"\ndate: \"2026-10-19\"\ndraft: true\n---\n\n",
+		},
+		{
+			name:    "title without letters or digits is refused",
+			opts:    NewPostOptions{Title: "!!!", Date: date},
+			wantErr: ErrEmptySlug,
+		},
+		{
+			name: "given slug is used as is",
+			opts: NewPostOptions{Title: "Notes", Slug: "my-notes", Date: date},
+			want: "---\ntitle: \"Notes\"\nslug: \"my-notes\"\ndate: \"2026-10-19\"\ndraft: true\n---\n\n",
+		},
+		{
+			name:    "slug leaving the posts directory is refused",
+			opts:    NewPostOptions{Title: "Escaped", Slug: "../../escaped", Date: date},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "slug with a path separator is refused",
+			opts:    NewPostOptions{Title: "Nested", Slug: "a/b", Date: date},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "slug with a backslash is refused",
+			opts:    NewPostOptions{Title: "Nested", Slug: `a\b`, Date: date},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:    "slug not in slug form is refused",
+			opts:    NewPostOptions{Title: "Notes", Slug: "My Notes", Date: date},
+			wantErr: ErrInvalidSlug,
+		},
+		{
+			name:      "archetype producing invalid front matter is refused",
+			opts:      NewPostOptions{Title: "Broken", Date: date},
+			archetype: "---\ntitle: {{ quote .Title }}\n---\n",
+			wantErr:   ErrInvalidPost,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+
+			for name, data := range existing {
+				path := filepath.Join(root, "posts", filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+			}
+
+			if tc.archetype != "" {
+				archetype := filepath.Join(root, "archetypes", "post.md")
+				require.NoError(t, os.MkdirAll(filepath.Dir(archetype), 0o755))
+				require.NoError(t, os.WriteFile(archetype, []byte(tc.archetype), 0o644))
+			}
+
+			path, err := NewPost(root, tc.opts)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr != nil {
+				entries, err := os.ReadDir(filepath.Join(root, "posts"))
+				require.NoError(t, err)
+				assert.Equal(t, len(entries), 3)
+				return
+			}
+
+			got, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(got), tc.want)
+
+			info, err := os.Stat(filepath.Join(filepath.Dir(path), "media"))
+			require.NoError(t, err)
+			assert.True(t, info.IsDir())
+
+			_, err = LoadPost(path)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package content
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+)
+
+// summaryWords is the length of a summary taken from the start of a body
+// that has no <!--more--> marker and no summary in its front matter.
+const summaryWords = 50
+
+// wordsPerMinute is the reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
+// PostSummary describes a post in listings.
+//
+// Text and HTML hold the same summary as plain text and as a well-formed
+// HTML tree. More reports whether the body continues past the summary.
+// WordCount and ReadingTime describe the whole body; ReadingTime is
+// rounded up to the minute and is zero only for an empty body.
+type PostSummary struct {
+	Text        string
+	HTML        html.Node
+	More        bool
+	WordCount   int
+	ReadingTime time.Duration
+}
+
+// summarize returns the summary of p, whose body compiled to doc.
+//
+// The summary is the body up to a <!--more--> marker, or else the summary
+// from the front matter, or else the first summaryWords words of the body.
+func summarize(p Post, doc markdown.Document) PostSummary {
+	tree, ok := doc.(html.Node)
+	if !ok {
+		tree = html.Fragment{}
+	}
+
+	words := html.CountWords(tree)
+	s := PostSummary{
+		WordCount:   words,
+		ReadingTime: readingTime(words),
+	}
+
+	if cut, found := html.CutBefore(tree, isMoreMarker); found {
+		s.HTML = cut
+		s.More = true
+	} else if fm := p.FrontMatter.Summary; fm != "" {
+		s.HTML = html.Element{
+			Tag:      "p",
+			Children: []html.Node{html.Text{Value: fm}},
+		}
+		s.More = words > 0
+	} else {
+		s.HTML, s.More = html.TruncateWords(tree, summaryWords)
+	}
+
+	s.Text = html.PlainText(s.HTML)
+	return s
+}
+
+// isMoreMarker reports whether n is a <!--more--> comment, in any case and
+// with any spacing inside the comment.
+func isMoreMarker(n html.Node) bool {
+	raw, ok := n.(html.Raw)
+	if !ok {
+		return false
+	}
+
+	s := strings.TrimSpace(raw.Value)
+	if !strings.HasPrefix(s, "<!--") || !strings.HasSuffix(s, "-->") {
+		return false
+	}
+
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "<!--"), "-->")
+	return strings.EqualFold(strings.TrimSpace(s), "more")
+}
+
+func readingTime(words int) time.Duration {
+	minutes := math.Ceil(float64(words) / wordsPerMinute)
+	return time.Duration(minutes) * time.Minute
+}
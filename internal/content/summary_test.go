@@ -0,0 +1,147 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestCompilePost_Summary(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("word ", 250))
+
+	testCases := []struct {
+		name        string
+		frontMatter []string
+		body        string
+		wantText    string
+		wantHTML    string
+		wantMore    bool
+		wantWords   int
+		wantReading time.Duration
+	}{
+		{
+			name:        "more marker ends the summary",
+			body:        "First *paragraph*.\n\n<!--more-->\n\nSecond paragraph.\n",
+			wantText:    "First paragraph.",
+			wantHTML:    "<p>First <em>paragraph</em>.</p>",
+			wantMore:    true,
+			wantWords:   4,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "spaced inline more marker ends the summary",
+			body:        "First part <!-- More --> second part.\n",
+			wantText:    "First part",
+			wantHTML:    "<p>First part </p>",
+			wantMore:    true,
+			wantWords:   4,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "front matter summary is used without a marker",
+			frontMatter: []string{"summary: A <short> summary."},
+			body:        "The body.\n",
+			wantText:    "A <short> summary.",
+			wantHTML:    "<p>A &lt;short&gt; summary.</p>",
+			wantMore:    true,
+			wantWords:   2,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "more marker takes precedence over front matter summary",
+			frontMatter: []string{"summary: Ignored."},
+			body:        "Kept.\n\n<!--more-->\n\nRest.\n",
+			wantText:    "Kept.",
+			wantHTML:    "<p>Kept.</p>",
+			wantMore:    true,
+			wantWords:   2,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "short body is its own summary",
+			body:        "Just this.\n",
+			wantText:    "Just this.",
+			wantHTML:    "<p>Just this.</p>",
+			wantMore:    false,
+			wantWords:   2,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "long body is truncated to the summary length",
+			body:        long + "\n",
+			wantText:    strings.TrimSpace(strings.Repeat("word ", summaryWords)) + "…",
+			wantHTML:    "<p>" + strings.TrimSpace(strings.Repeat("word ", summaryWords)) + "…</p>",
+			wantMore:    true,
+			wantWords:   250,
+			wantReading: 2 * time.Minute,
+		},
+		{
+			name:        "raw tag open across the word limit is dropped",
+			body:        "Start <span class=\"x\">" + long + "</span>\n",
+			wantText:    "Start " + strings.TrimSpace(strings.Repeat("word ", summaryWords-1)) + "…",
+			wantHTML:    "<p>Start " + strings.TrimSpace(strings.Repeat("word ", summaryWords-1)) + "…</p>",
+			wantMore:    true,
+			wantWords:   251,
+			wantReading: 2 * time.Minute,
+		},
+		{
+			name:        "raw tag closed before the word limit is kept",
+			body:        "Start <span class=\"x\">inside</span> " + long + "\n",
+			wantText:    "Start inside " + strings.TrimSpace(strings.Repeat("word ", summaryWords-2)) + "…",
+			wantHTML:    "<p>Start <span class=\"x\">inside</span> " + strings.TrimSpace(strings.Repeat("word ", summaryWords-2)) + "…</p>",
+			wantMore:    true,
+			wantWords:   252,
+			wantReading: 2 * time.Minute,
+		},
+		{
+			name:        "raw tag open across the more marker is dropped",
+			body:        "Start <em class=\"x\">kept <!--more--> rest</em>.\n",
+			wantText:    "Start kept",
+			wantHTML:    "<p>Start kept </p>",
+			wantMore:    true,
+			wantWords:   3,
+			wantReading: time.Minute,
+		},
+		{
+			name:        "empty body has no reading time",
+			body:        "",
+			wantText:    "",
+			wantHTML:    "",
+			wantMore:    false,
+			wantWords:   0,
+			wantReading: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []string{"---", "title: Hello", "slug: hello", "date: 2026-02-17"}
+			lines = append(lines, tc.frontMatter...)
+			lines = append(lines, "---", tc.body)
+
+			path := filepath.Join(t.TempDir(), "index.md")
+			require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644))
+
+			p, err := LoadPost(path)
+			require.NoError(t, err)
+
+			p, err = CompilePost(p, CompileOptions{})
+			require.NoError(t, err)
+
+			gotHTML, err := html.Render(p.Summary.HTML)
+			require.NoError(t, err)
+
+			assert.Equal(t, p.Summary.Text, tc.wantText)
+			assert.Equal(t, gotHTML, tc.wantHTML)
+			assert.Equal(t, p.Summary.More, tc.wantMore)
+			assert.Equal(t, p.Summary.WordCount, tc.wantWords)
+			assert.Equal(t, p.Summary.ReadingTime, tc.wantReading)
+		})
+	}
+}
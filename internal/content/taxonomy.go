@@ -0,0 +1,155 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidTag = errors.New("tag yields an empty slug")
+
+// Tag is a post tag after normalization. Slug identifies the tag and
+// names its page; Name is the tag as shown.
+type Tag struct {
+	Name string
+	Slug string
+}
+
+// TagListing is a tag with the posts given it, newest first.
+type TagListing struct {
+	Tag
+	Posts []Post
+}
+
+// ApplyTags normalizes the front matter tags of every post in place,
+// setting Tags. Each tag is slugified, which folds its case, and an alias
+// configured in cfg is replaced by the tag it names; a post given the same
+// tag twice lists it once.
+//
+// A tag is named as configured, or else as first spelled among posts
+// under its own slug rather than an alias. A tag that yields an empty slug
+// is reported, located at the tags in its post's front matter, and so are
+// two tags that differ by more than case and separators but share a slug
+// the config does not cover, such as "C" and "C++".
+func ApplyTags(posts []Post, cfg Config) error {
+	canonical := make(map[string]string)
+	names := make(map[string]string)
+	for slug, tc := range cfg.Tags {
+		for _, alias := range tc.Aliases {
+			canonical[alias] = slug
+		}
+		if tc.Name != "" {
+			names[slug] = tc.Name
+		}
+	}
+
+	normalize := func(name string) string {
+		slug := Slugify(name)
+		if c, ok := canonical[slug]; ok {
+			return c
+		}
+		return slug
+	}
+
+	var errs ErrorList
+	tagError := func(p Post, err error) {
+		fmBytes, _, _ := SplitPost([]byte(p.raw))
+		errs = errs.Append(frontMatterErrors(p.SourcePath, p.raw, fieldError(fmBytes, "tags", err)))
+	}
+
+	// spelled records the first spelling of each slug and its post, so a
+	// different tag sharing the slug can be reported against it.
+	type spelling struct {
+		name string
+		post Post
+	}
+	spelled := make(map[string]spelling)
+
+	for _, p := range posts {
+		for _, name := range p.FrontMatter.Tags {
+			slug := Slugify(name)
+			if slug == "" || normalize(name) != slug {
+				continue
+			}
+			if _, ok := names[slug]; !ok {
+				names[slug] = name
+			}
+
+			prev, ok := spelled[slug]
+			if !ok {
+				spelled[slug] = spelling{name: name, post: p}
+				continue
+			}
+			if _, ok := cfg.Tags[slug]; !ok && tagKey(prev.name) != tagKey(name) {
+				tagError(p, fmt.Errorf("%w: %q and %q in %s share the slug %q", ErrTagConflict, name, prev.name, prev.post.SourcePath, slug))
+			}
+		}
+	}
+
+	for i, p := range posts {
+		var tags []Tag
+		seen := make(map[string]bool, len(p.FrontMatter.Tags))
+
+		for _, name := range p.FrontMatter.Tags {
+			slug := normalize(name)
+			if slug == "" {
+				tagError(p, fmt.Errorf("%w: %q", ErrInvalidTag, name))
+				continue
+			}
+			if seen[slug] {
+				continue
+			}
+			seen[slug] = true
+
+			tag := Tag{Name: names[slug], Slug: slug}
+			if tag.Name == "" {
+				tag.Name = slug
+			}
+			tags = append(tags, tag)
+		}
+
+		posts[i].Tags = tags
+	}
+
+	return errs.Err()
+}
+
+// tagKey returns name folded to lower case with each run of spaces,
+// hyphens, and underscores made a single hyphen. Tags with the same key
+// differ only in how they are written.
+func tagKey(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	return strings.Join(fields, "-")
+}
+
+// GroupTags returns each tag given to posts by ApplyTags along with the
+// posts given it, newest first. Tags are ordered by slug.
+func GroupTags(posts []Post) []TagListing {
+	bySlug := make(map[string]*TagListing)
+	for _, p := range posts {
+		for _, tag := range p.Tags {
+			l, ok := bySlug[tag.Slug]
+			if !ok {
+				l = &TagListing{Tag: tag}
+				bySlug[tag.Slug] = l
+			}
+			l.Posts = append(l.Posts, p)
+		}
+	}
+
+	listings := make([]TagListing, 0, len(bySlug))
+	for _, l := range bySlug {
+		SortPosts(l.Posts)
+		listings = append(listings, *l)
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Slug < listings[j].Slug
+	})
+
+	return listings
+}
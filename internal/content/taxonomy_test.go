@@ -0,0 +1,204 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []byte
+		cfg     Config
+		wantErr error
+	}{
+		{
+			name: "empty config",
+			data: []byte(""),
+			cfg:  Config{PageSize: DefaultPageSize, Tags: map[string]TagConfig{}},
+		},
+		{
+			name: "tag slugs and aliases are normalized",
+			data: []byte(strings.Join([]string{
+				"tags:",
+				"  Go:",
+				"    name: Go",
+				"    aliases: [golang, Go Lang]",
+			}, "\n")),
+			cfg: Config{PageSize: DefaultPageSize, Tags: map[string]TagConfig{
+				"go": {Name: "Go", Aliases: []string{"golang", "go-lang"}},
+			}},
+		},
+		{
+			name: "page size is decoded",
+			data: []byte("page_size: 5"),
+			cfg:  Config{PageSize: 5, Tags: map[string]TagConfig{}},
+		},
+		{
+			name:    "non-positive page size returns ErrInvalidPageSize",
+			data:    []byte("page_size: 0"),
+			wantErr: ErrInvalidPageSize,
+		},
+		{
+			name: "base url loses its trailing slash",
+			data: []byte("base_url: https://example.com/"),
+			cfg:  Config{PageSize: DefaultPageSize, BaseURL: "https://example.com", Tags: map[string]TagConfig{}},
+		},
+		{
+			name: "base url may hold a path",
+			data: []byte("base_url: http://example.com/site"),
+			cfg:  Config{PageSize: DefaultPageSize, BaseURL: "http://example.com/site", Tags: map[string]TagConfig{}},
+		},
+		{
+			name:    "relative base url returns ErrInvalidBaseURL",
+			data:    []byte("base_url: /site"),
+			wantErr: ErrInvalidBaseURL,
+		},
+		{
+			name:    "base url without an http scheme returns ErrInvalidBaseURL",
+			data:    []byte("base_url: ftp://example.com"),
+			wantErr: ErrInvalidBaseURL,
+		},
+		{
+			name:    "base url with a query returns ErrInvalidBaseURL",
+			data:    []byte("base_url: https://example.com/?ref=og"),
+			wantErr: ErrInvalidBaseURL,
+		},
+		{
+			name: "alias of another tag returns ErrTagConflict",
+			data: []byte(strings.Join([]string{
+				"tags:",
+				"  go:",
+				"    aliases: [golang]",
+				"  golang:",
+				"    name: Golang",
+			}, "\n")),
+			wantErr: ErrTagConflict,
+		},
+		{
+			name: "alias with an empty slug returns ErrInvalidTag",
+			data: []byte(strings.Join([]string{
+				"tags:",
+				"  go:",
+				"    aliases: [\"!!\"]",
+			}, "\n")),
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name: "unknown field returns ErrInvalidConfig",
+			data: []byte(strings.Join([]string{
+				"tags:",
+				"  go:",
+				"    title: Go",
+			}, "\n")),
+			wantErr: ErrInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := DecodeConfig(tc.data)
+
+			assert.Equal(t, cfg, tc.cfg)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	cfg, err := LoadConfig(ConfigPath(t.TempDir()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, Config{PageSize: DefaultPageSize})
+}
+
+func TestApplyTags(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	posts := []Post{
+		{FrontMatter: FrontMatter{Slug: "first", Date: day(1), Tags: []string{"golang", "Static Sites"}}},
+		{FrontMatter: FrontMatter{Slug: "second", Date: day(2), Tags: []string{"static sites", "GoLang", "go"}}},
+		{FrontMatter: FrontMatter{Slug: "third", Date: day(3)}},
+	}
+	cfg := Config{Tags: map[string]TagConfig{
+		"go": {Aliases: []string{"golang"}},
+	}}
+
+	require.NoError(t, ApplyTags(posts, cfg))
+
+	assert.Equal(t, posts[0].Tags, []Tag{
+		{Name: "go", Slug: "go"},
+		{Name: "Static Sites", Slug: "static-sites"},
+	})
+	assert.Equal(t, posts[1].Tags, []Tag{
+		{Name: "Static Sites", Slug: "static-sites"},
+		{Name: "go", Slug: "go"},
+	})
+	assert.Equal(t, len(posts[2].Tags), 0)
+
+	listings := GroupTags(posts)
+	require.Equal(t, len(listings), 2)
+
+	assert.Equal(t, listings[0].Slug, "go")
+	assert.Equal(t, len(listings[0].Posts), 2)
+	assert.Equal(t, listings[0].Posts[0].FrontMatter.Slug, "second")
+	assert.Equal(t, listings[1].Slug, "static-sites")
+}
+
+func TestApplyTags_InvalidTag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.md")
+	data := "---\ntitle: Post\nslug: post\ndate: 2025-03-01\ntags: [\"!!\"]\n---\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	p, err := LoadPost(path)
+	require.NoError(t, err)
+
+	err = ApplyTags([]Post{p}, Config{})
+
+	var serr SourceError
+	require.ErrorAs(t, err, &serr)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	line, col := serr.Position()
+	assert.Equal(t, line, 5)
+	assert.Equal(t, col, 7)
+}
+
+func TestApplyTags_Conflict(t *testing.T) {
+	dir := t.TempDir()
+	load := func(slug, tags string) Post {
+		path := filepath.Join(dir, slug, "index.md")
+		data := "---\ntitle: Post\nslug: " + slug + "\ndate: 2025-03-01\ntags: " + tags + "\n---\n"
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+		p, err := LoadPost(path)
+		require.NoError(t, err)
+		return p
+	}
+
+	cpp := load("cpp", `["C++", "Static Sites"]`)
+	c := load("c", `["C", "static-sites"]`)
+
+	err := ApplyTags([]Post{cpp, c}, Config{})
+
+	var list ErrorList
+	require.ErrorAs(t, err, &list)
+	assert.Equal(t, len(list), 1)
+	assert.ErrorIs(t, err, ErrTagConflict)
+
+	var serr SourceError
+	require.ErrorAs(t, list[0], &serr)
+	assert.Equal(t, serr.Path, c.SourcePath)
+
+	cfg := Config{Tags: map[string]TagConfig{"c": {Name: "C"}}}
+	assert.NoError(t, ApplyTags([]Post{cpp, c}, cfg))
+}
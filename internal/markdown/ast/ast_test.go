@@ -3,22 +3,36 @@ package ast
 var (
 	_ Block = Paragraph{}
 	_ Block = BlockQuote{}
+	_ Block = Callout{}
 	_ Block = Header{}
 	_ Block = ThematicBreak{}
 	_ Block = OrderedList{}
 	_ Block = UnorderedList{}
 	_ Block = ListItem{}
+	_ Block = DefinitionList{}
 	_ Block = CodeBlock{}
 	_ Block = HTMLBlock{}
+	_ Block = MathBlock{}
 )
 
 var (
 	_ Inline = CodeSpan{}
 	_ Inline = Link{}
 	_ Inline = Image{}
+	_ Inline = BracketedSpan{}
 	_ Inline = Emph{}
 	_ Inline = Strong{}
+	_ Inline = Emoji{}
+	_ Inline = Abbreviation{}
+	_ Inline = WikiLink{}
+	_ Inline = Superscript{}
+	_ Inline = Subscript{}
+	_ Inline = Strikethrough{}
+	_ Inline = Highlight{}
+	_ Inline = Insert{}
 	_ Inline = Text{}
+	_ Inline = SmartPunct{}
+	_ Inline = Math{}
 	_ Inline = RawText{}
 	_ Inline = HardBreak{}
 	_ Inline = SoftBreak{}
@@ -0,0 +1,34 @@
+package ast
+
+// Attributes holds HTML attributes attached to a node with the attribute
+// list syntax, keyed by lowercase attribute name.
+//
+// Classes are stored as a single space-separated class attribute.
+type Attributes map[string]string
+
+// AddClass appends class to the class attribute.
+func (a Attributes) AddClass(class string) {
+	if class == "" {
+		return
+	}
+
+	if existing, ok := a["class"]; ok && existing != "" {
+		a["class"] = existing + " " + class
+		return
+	}
+
+	a["class"] = class
+}
+
+// Merge copies the attributes of other into a. Classes accumulate, and any
+// other attribute present in both takes its value from other.
+func (a Attributes) Merge(other Attributes) {
+	for key, value := range other {
+		if key == "class" {
+			a.AddClass(value)
+			continue
+		}
+
+		a[key] = value
+	}
+}
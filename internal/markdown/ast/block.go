@@ -12,8 +12,9 @@ type Block interface {
 }
 
 type BlockQuote struct {
-	Span     source.ByteSpan
-	Children []Block
+	Span       source.ByteSpan
+	Children   []Block
+	Attributes Attributes
 }
 
 func (BlockQuote) isBlock() {}
@@ -22,10 +23,47 @@ func (bq BlockQuote) String() string {
 	return fmt.Sprintf("BlockQuote(children=%s)", summarizeBlocks(bq.Children))
 }
 
+// CalloutKinds maps each standard callout kind to its default title.
+var CalloutKinds = map[string]string{
+	"note":      "Note",
+	"tip":       "Tip",
+	"important": "Important",
+	"warning":   "Warning",
+	"caution":   "Caution",
+}
+
+// Callout represents a block quote introduced by a [!KIND] marker.
+//
+// Kind holds the lowercased marker name. Title holds the inline content
+// following the marker on its line and is empty when the default title for
+// Kind applies. TitleLevel is the heading level of the title, one below the
+// section enclosing the callout.
+type Callout struct {
+	Span       source.ByteSpan
+	Kind       string
+	Title      []Inline
+	TitleLevel int
+	Children   []Block
+	Attributes Attributes
+}
+
+func (Callout) isBlock() {}
+
+func (c Callout) String() string {
+	return fmt.Sprintf(
+		"Callout(kind=%s,title=%s,level=%d,children=%s)",
+		c.Kind,
+		summarizeInlines(c.Title),
+		c.TitleLevel,
+		summarizeBlocks(c.Children),
+	)
+}
+
 type Header struct {
-	Span    source.ByteSpan
-	Level   int
-	Inlines []Inline
+	Span       source.ByteSpan
+	Level      int
+	Inlines    []Inline
+	Attributes Attributes
 }
 
 func (Header) isBlock() {}
@@ -35,7 +73,8 @@ func (h Header) String() string {
 }
 
 type ThematicBreak struct {
-	Span source.ByteSpan
+	Span       source.ByteSpan
+	Attributes Attributes
 }
 
 func (ThematicBreak) isBlock() {}
@@ -45,10 +84,11 @@ func (tb ThematicBreak) String() string {
 }
 
 type OrderedList struct {
-	Span  source.ByteSpan
-	Items []ListItem
-	Tight bool
-	Start int
+	Span       source.ByteSpan
+	Items      []ListItem
+	Tight      bool
+	Start      int
+	Attributes Attributes
 }
 
 func (OrderedList) isBlock() {}
@@ -63,9 +103,10 @@ func (ol OrderedList) String() string {
 }
 
 type UnorderedList struct {
-	Span  source.ByteSpan
-	Items []ListItem
-	Tight bool
+	Span       source.ByteSpan
+	Items      []ListItem
+	Tight      bool
+	Attributes Attributes
 }
 
 func (UnorderedList) isBlock() {}
@@ -89,6 +130,52 @@ func (li ListItem) String() string {
 	return fmt.Sprintf("ListItem(children=%s)", summarizeBlocks(li.Children))
 }
 
+// DefinitionList represents a definition list.
+//
+// Tight reports whether definition content is rendered without paragraph
+// wrappers, following the same convention as lists.
+type DefinitionList struct {
+	Span       source.ByteSpan
+	Items      []DefinitionItem
+	Tight      bool
+	Attributes Attributes
+}
+
+func (DefinitionList) isBlock() {}
+
+func (dl DefinitionList) String() string {
+	return fmt.Sprintf("DefinitionList(tight=%t,items=%d)", dl.Tight, len(dl.Items))
+}
+
+// DefinitionItem groups one or more terms with their definitions.
+type DefinitionItem struct {
+	Span        source.ByteSpan
+	Terms       []DefinitionTerm
+	Definitions []Definition
+}
+
+func (di DefinitionItem) String() string {
+	return fmt.Sprintf("DefinitionItem(terms=%d,definitions=%d)", len(di.Terms), len(di.Definitions))
+}
+
+type DefinitionTerm struct {
+	Span    source.ByteSpan
+	Inlines []Inline
+}
+
+func (dt DefinitionTerm) String() string {
+	return fmt.Sprintf("DefinitionTerm(inlines=%s)", summarizeInlines(dt.Inlines))
+}
+
+type Definition struct {
+	Span     source.ByteSpan
+	Children []Block
+}
+
+func (d Definition) String() string {
+	return fmt.Sprintf("Definition(children=%s)", summarizeBlocks(d.Children))
+}
+
 // CodeBlockKind distinguishes indented and fenced code blocks.
 type CodeBlockKind int
 
@@ -119,6 +206,7 @@ type CodeBlock struct {
 	Kind              CodeBlockKind
 	LanguageTokenSpan source.ByteSpan
 	Payload           []Inline
+	Attributes        Attributes
 }
 
 func (CodeBlock) isBlock() {}
@@ -138,11 +226,48 @@ func (hb HTMLBlock) String() string {
 	return fmt.Sprintf("HTMLBlock(payload=%s)", summarizeInlines(hb.Payload))
 }
 
-type Paragraph struct {
-	Span    source.ByteSpan
+// MathBlock represents a display math block.
+//
+// Lines holds the TeX source lines of the block, which are never parsed as
+// Markdown inline content.
+type MathBlock struct {
+	Span       source.ByteSpan
+	Lines      []source.ByteSpan
+	Attributes Attributes
+}
+
+func (MathBlock) isBlock() {}
+
+func (mb MathBlock) String() string {
+	return fmt.Sprintf("MathBlock(lines=%d)", len(mb.Lines))
+}
+
+// LineBlock represents a block whose line breaks and leading indentation
+// are preserved, such as verse or an address.
+type LineBlock struct {
+	Span       source.ByteSpan
+	Lines      []Line
+	Attributes Attributes
+}
+
+func (LineBlock) isBlock() {}
+
+func (lb LineBlock) String() string {
+	return fmt.Sprintf("LineBlock(lines=%d)", len(lb.Lines))
+}
+
+// Line is a single line of a line block, indented by Indent spaces.
+type Line struct {
+	Indent  int
 	Inlines []Inline
 }
 
+type Paragraph struct {
+	Span       source.ByteSpan
+	Inlines    []Inline
+	Attributes Attributes
+}
+
 func (Paragraph) isBlock() {}
 
 func (p Paragraph) String() string {
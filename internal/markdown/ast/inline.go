@@ -25,14 +25,20 @@ func (c CodeSpan) String() string {
 //
 // Label, Destination, and Title refer to source spans in the original input.
 // Children holds the parsed inline label content. MailTo reports whether the
-// rendered destination should be treated as a mailto link.
+// rendered destination should be treated as a mailto link, and WWW reports
+// whether it was written without a scheme and should be treated as an http
+// link. Autolink reports whether the link was written as an autolink, in
+// which case Children mirror the destination text.
 type Link struct {
 	Span        source.ByteSpan
 	Label       source.ByteSpan
 	Destination source.ByteSpan
 	Title       source.ByteSpan
 	MailTo      bool
+	WWW         bool
+	Autolink    bool
 	Children    []Inline
+	Attributes  Attributes
 }
 
 func (Link) isInline() {}
@@ -55,6 +61,7 @@ type Image struct {
 	Destination source.ByteSpan
 	Title       source.ByteSpan
 	Children    []Inline
+	Attributes  Attributes
 }
 
 func (i Image) isInline() {}
@@ -63,6 +70,20 @@ func (i Image) String() string {
 	return fmt.Sprintf("Image(children=%s)", summarizeInlines(i.Children))
 }
 
+// BracketedSpan represents bracketed inline content followed by an
+// attribute list, as in [text]{.class}, which renders as a span.
+type BracketedSpan struct {
+	Span       source.ByteSpan
+	Children   []Inline
+	Attributes Attributes
+}
+
+func (BracketedSpan) isInline() {}
+
+func (bs BracketedSpan) String() string {
+	return fmt.Sprintf("BracketedSpan(children=%s)", summarizeInlines(bs.Children))
+}
+
 type Emph struct {
 	Span     source.ByteSpan
 	Children []Inline
@@ -85,6 +106,66 @@ func (s Strong) String() string {
 	return fmt.Sprintf("Strong(children=%s)", summarizeInlines(s.Children))
 }
 
+// Superscript represents superscript text written as ^text^.
+type Superscript struct {
+	Span     source.ByteSpan
+	Children []Inline
+}
+
+func (Superscript) isInline() {}
+
+func (s Superscript) String() string {
+	return fmt.Sprintf("Superscript(children=%s)", summarizeInlines(s.Children))
+}
+
+// Subscript represents subscript text written as ~text~.
+type Subscript struct {
+	Span     source.ByteSpan
+	Children []Inline
+}
+
+func (Subscript) isInline() {}
+
+func (s Subscript) String() string {
+	return fmt.Sprintf("Subscript(children=%s)", summarizeInlines(s.Children))
+}
+
+// Strikethrough represents struck-through text written as ~~text~~.
+type Strikethrough struct {
+	Span     source.ByteSpan
+	Children []Inline
+}
+
+func (Strikethrough) isInline() {}
+
+func (s Strikethrough) String() string {
+	return fmt.Sprintf("Strikethrough(children=%s)", summarizeInlines(s.Children))
+}
+
+// Highlight represents highlighted text written as ==text==.
+type Highlight struct {
+	Span     source.ByteSpan
+	Children []Inline
+}
+
+func (Highlight) isInline() {}
+
+func (h Highlight) String() string {
+	return fmt.Sprintf("Highlight(children=%s)", summarizeInlines(h.Children))
+}
+
+// Insert represents inserted text written as ++text++.
+type Insert struct {
+	Span     source.ByteSpan
+	Children []Inline
+}
+
+func (Insert) isInline() {}
+
+func (i Insert) String() string {
+	return fmt.Sprintf("Insert(children=%s)", summarizeInlines(i.Children))
+}
+
 type Text struct {
 	Span source.ByteSpan
 }
@@ -95,6 +176,128 @@ func (Text) String() string {
 	return "Text"
 }
 
+// PunctKind identifies a typographic punctuation mark.
+type PunctKind int
+
+func (k PunctKind) String() string {
+	switch k {
+	case LeftDoubleQuote:
+		return "LeftDoubleQuote"
+	case RightDoubleQuote:
+		return "RightDoubleQuote"
+	case LeftSingleQuote:
+		return "LeftSingleQuote"
+	case RightSingleQuote:
+		return "RightSingleQuote"
+	case EnDash:
+		return "EnDash"
+	case EmDash:
+		return "EmDash"
+	case Ellipsis:
+		return "Ellipsis"
+	default:
+		return fmt.Sprintf("Unrecognized PunctKind %d", k)
+	}
+}
+
+const (
+	_ PunctKind = iota
+	LeftDoubleQuote
+	RightDoubleQuote
+	LeftSingleQuote
+	RightSingleQuote
+	EnDash
+	EmDash
+	Ellipsis
+)
+
+// SmartPunct represents straight punctuation in the source that renders as
+// its typographic equivalent.
+//
+// Span covers the source bytes being replaced, such as a '"' or a "---" run.
+type SmartPunct struct {
+	Span source.ByteSpan
+	Kind PunctKind
+}
+
+func (SmartPunct) isInline() {}
+
+func (sp SmartPunct) String() string {
+	return fmt.Sprintf("SmartPunct(%s)", sp.Kind)
+}
+
+// Emoji represents a resolved :shortcode: emoji.
+//
+// Span covers the shortcode including its colons. Value is the Unicode
+// emoji and Description its name in words. Labeled reports whether the
+// emoji should be rendered with an accessible label.
+type Emoji struct {
+	Span        source.ByteSpan
+	Value       string
+	Description string
+	Labeled     bool
+}
+
+func (Emoji) isInline() {}
+
+func (e Emoji) String() string {
+	return fmt.Sprintf("Emoji(value=%q, labeled=%t)", e.Value, e.Labeled)
+}
+
+// WikiLink represents a [[target#fragment|label]] internal link.
+//
+// Target, Fragment, and Label refer to source spans in the original input;
+// Fragment and Label are empty when omitted. Destination and Title are
+// filled in when the link is resolved: Destination is the URL of the
+// target, including any fragment, and Title is the target's title, which
+// serves as the link text when no label is given.
+type WikiLink struct {
+	Span        source.ByteSpan
+	Target      source.ByteSpan
+	Fragment    source.ByteSpan
+	Label       source.ByteSpan
+	Destination string
+	Title       string
+}
+
+func (WikiLink) isInline() {}
+
+func (w WikiLink) String() string {
+	return fmt.Sprintf("WikiLink(destination=%q)", w.Destination)
+}
+
+// Abbreviation represents an occurrence of a defined abbreviation in text.
+//
+// Span covers the abbreviation as it appears in text. Title identifies the
+// expansion given by its definition, and is empty when none was supplied.
+type Abbreviation struct {
+	Span  source.ByteSpan
+	Title source.ByteSpan
+}
+
+func (Abbreviation) isInline() {}
+
+func (Abbreviation) String() string {
+	return "Abbreviation"
+}
+
+// Math represents inline TeX math.
+//
+// Content identifies the TeX source between the delimiters, which is never
+// parsed as Markdown inline content. Display reports whether the math was
+// written with $$ delimiters and should be typeset in display style.
+type Math struct {
+	Span    source.ByteSpan
+	Content source.ByteSpan
+	Display bool
+}
+
+func (Math) isInline() {}
+
+func (m Math) String() string {
+	return fmt.Sprintf("Math(display=%t)", m.Display)
+}
+
 // RawText represents inline content that should be emitted without normal
 // text escaping rules applied to Text nodes.
 type RawText struct {
@@ -66,6 +66,8 @@ func summarizeBlock(b Block) string {
 	switch v := b.(type) {
 	case BlockQuote:
 		return v.String()
+	case Callout:
+		return v.String()
 	case Header:
 		return v.String()
 	case ThematicBreak:
@@ -76,10 +78,16 @@ func summarizeBlock(b Block) string {
 		return v.String()
 	case ListItem:
 		return v.String()
+	case DefinitionList:
+		return v.String()
 	case CodeBlock:
 		return v.String()
 	case HTMLBlock:
 		return v.String()
+	case MathBlock:
+		return v.String()
+	case LineBlock:
+		return v.String()
 	case Paragraph:
 		return v.String()
 	default:
@@ -95,12 +103,34 @@ func summarizeInline(in Inline) string {
 		return v.String()
 	case Image:
 		return v.String()
+	case BracketedSpan:
+		return v.String()
 	case Emph:
 		return v.String()
 	case Strong:
 		return v.String()
+	case Superscript:
+		return v.String()
+	case Subscript:
+		return v.String()
+	case Strikethrough:
+		return v.String()
+	case Highlight:
+		return v.String()
+	case Insert:
+		return v.String()
 	case Text:
 		return v.String()
+	case SmartPunct:
+		return v.String()
+	case Emoji:
+		return v.String()
+	case Abbreviation:
+		return v.String()
+	case WikiLink:
+		return v.String()
+	case Math:
+		return v.String()
 	case RawText:
 		return v.String()
 	case HardBreak:
@@ -0,0 +1,268 @@
+package attribute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// Scan reports whether s begins with an attribute list and returns its
+// length in bytes.
+//
+// An attribute list is a brace-delimited, whitespace-separated sequence of
+// one or more items, each of which is a .class, a #id, or a key=value pair
+// whose value is bare or enclosed in single or double quotes.
+func Scan(s string) (int, bool) {
+	if len(s) == 0 || s[0] != '{' {
+		return 0, false
+	}
+
+	pos := 1
+	items := 0
+
+	for {
+		pos = skipSpaces(s, pos)
+		if pos >= len(s) {
+			return 0, false
+		}
+
+		if s[pos] == '}' {
+			if items == 0 {
+				return 0, false
+			}
+
+			return pos + 1, true
+		}
+
+		if items > 0 && !isSpace(s[pos-1]) {
+			return 0, false
+		}
+
+		end, ok := scanItem(s, pos)
+		if !ok {
+			return 0, false
+		}
+
+		pos = end
+		items++
+	}
+}
+
+// Trailing reports whether s ends with an attribute list, ignoring trailing
+// spaces and tabs, and returns the offset at which the list begins.
+//
+// The list must be preceded by a space or tab or begin s, and its opening
+// brace must not be escaped.
+func Trailing(s string) (int, bool) {
+	end := len(strings.TrimRight(s, " \t"))
+	if end == 0 || s[end-1] != '}' {
+		return 0, false
+	}
+
+	for start := strings.LastIndexByte(s[:end], '{'); start >= 0; start = strings.LastIndexByte(s[:start], '{') {
+		n, ok := Scan(s[start:end])
+		if !ok || start+n != end {
+			continue
+		}
+
+		if start > 0 && !isSpace(s[start-1]) {
+			return 0, false
+		}
+
+		if isEscaped(s, start) {
+			return 0, false
+		}
+
+		return start, true
+	}
+
+	return 0, false
+}
+
+// Parse interprets the attribute list covered by span.
+//
+// Classes accumulate into a single space-separated class attribute, while
+// a repeated key keeps its last value. A second id, whether written as #id
+// or id=, and an attribute name that is not a valid HTML attribute name are
+// reported as a diagnostic.DiagnosticError located at the offending item.
+func Parse(src *source.Source, span source.ByteSpan) (ast.Attributes, error) {
+	s := src.Slice(span)
+
+	n, ok := Scan(s)
+	if !ok || n != len(s) {
+		return nil, fmt.Errorf("attribute: span %v is not an attribute list", span)
+	}
+
+	attrs := ast.Attributes{}
+	pos := 1
+
+	for {
+		pos = skipSpaces(s, pos)
+		if s[pos] == '}' {
+			break
+		}
+
+		end, _ := scanItem(s, pos)
+		item := s[pos:end]
+
+		fail := func(msg string, n int) error {
+			return diagnostic.DiagnosticError{
+				Diagnostic: diagnostic.Diagnostic{
+					Message: msg,
+					Span: source.ByteSpan{
+						Start: span.Start + source.BytePos(pos),
+						End:   span.Start + source.BytePos(pos+n),
+					},
+					Severity: diagnostic.SeverityError,
+				},
+			}
+		}
+
+		switch item[0] {
+		case '.':
+			attrs.AddClass(item[1:])
+
+		case '#':
+			if prev, ok := attrs["id"]; ok {
+				return nil, fail(fmt.Sprintf("duplicate id %q: already set to %q", item[1:], prev), len(item))
+			}
+			attrs["id"] = item[1:]
+
+		default:
+			key, value, _ := strings.Cut(item, "=")
+
+			if !validName(key) {
+				return nil, fail(fmt.Sprintf("invalid attribute name %q", key), len(key))
+			}
+
+			value = unquote(value)
+
+			key = strings.ToLower(key)
+			if key == "class" {
+				attrs.AddClass(value)
+				break
+			}
+
+			if prev, ok := attrs["id"]; ok && key == "id" {
+				return nil, fail(fmt.Sprintf("duplicate id %q: already set to %q", value, prev), len(item))
+			}
+
+			attrs[key] = value
+		}
+
+		pos = end
+	}
+
+	return attrs, nil
+}
+
+// scanItem returns the end of the attribute item beginning at pos.
+func scanItem(s string, pos int) (int, bool) {
+	switch s[pos] {
+	case '.', '#':
+		end := scanWord(s, pos+1)
+		if end == pos+1 {
+			return 0, false
+		}
+
+		return end, true
+	}
+
+	keyEnd := scanWord(s, pos)
+	if keyEnd == pos || keyEnd >= len(s) || s[keyEnd] != '=' {
+		return 0, false
+	}
+
+	valueStart := keyEnd + 1
+	if valueStart >= len(s) {
+		return 0, false
+	}
+
+	if q := s[valueStart]; q == '"' || q == '\'' {
+		closing := strings.IndexByte(s[valueStart+1:], q)
+		if closing < 0 {
+			return 0, false
+		}
+
+		return valueStart + closing + 2, true
+	}
+
+	valueEnd := scanWord(s, valueStart)
+	if valueEnd == valueStart {
+		return 0, false
+	}
+
+	return valueEnd, true
+}
+
+// scanWord returns the end of the run of word bytes beginning at pos.
+func scanWord(s string, pos int) int {
+	for pos < len(s) {
+		switch s[pos] {
+		case ' ', '\t', '{', '}', '"', '\'', '=':
+			return pos
+		}
+
+		pos++
+	}
+
+	return pos
+}
+
+// validName reports whether name is a valid attribute name: an ASCII
+// letter, underscore, or colon followed by letters, digits, underscores,
+// colons, periods, or hyphens.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b == '_', b == ':':
+			continue
+		case i > 0 && (b >= '0' && b <= '9' || b == '.' || b == '-'):
+			continue
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func skipSpaces(s string, pos int) int {
+	for pos < len(s) && isSpace(s[pos]) {
+		pos++
+	}
+
+	return pos
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// isEscaped reports whether s[i] is escaped by an odd-length run of
+// preceding backslashes.
+func isEscaped(s string, i int) bool {
+	slashes := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		slashes++
+	}
+
+	return slashes%2 == 1
+}
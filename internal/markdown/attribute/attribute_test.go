@@ -0,0 +1,243 @@
+package attribute_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/attribute"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestScan(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		want   int
+		wantOK bool
+	}{
+		{
+			name:   "class and id",
+			input:  "{.a #b}",
+			want:   7,
+			wantOK: true,
+		},
+		{
+			name:   "stops at the closing brace",
+			input:  "{.a} rest",
+			want:   4,
+			wantOK: true,
+		},
+		{
+			name:   "quoted values may contain spaces and braces",
+			input:  `{title="a }b" alt='c'}`,
+			want:   22,
+			wantOK: true,
+		},
+		{
+			name:   "surrounding whitespace is allowed",
+			input:  "{ .a\t}",
+			want:   6,
+			wantOK: true,
+		},
+		{
+			name:   "empty list",
+			input:  "{}",
+			wantOK: false,
+		},
+		{
+			name:   "empty class",
+			input:  "{.}",
+			wantOK: false,
+		},
+		{
+			name:   "items must be separated by whitespace",
+			input:  `{a="b".c}`,
+			wantOK: false,
+		},
+		{
+			name:   "key without value",
+			input:  "{key=}",
+			wantOK: false,
+		},
+		{
+			name:   "bare word",
+			input:  "{word}",
+			wantOK: false,
+		},
+		{
+			name:   "unterminated quote",
+			input:  `{a="b}`,
+			wantOK: false,
+		},
+		{
+			name:   "missing closing brace",
+			input:  "{.a",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := attribute.Scan(tc.input)
+
+			assert.Equal(t, ok, tc.wantOK)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestTrailing(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		want   int
+		wantOK bool
+	}{
+		{
+			name:   "after text",
+			input:  "Title {#t}",
+			want:   6,
+			wantOK: true,
+		},
+		{
+			name:   "trailing whitespace is ignored",
+			input:  "Title {#t}  ",
+			want:   6,
+			wantOK: true,
+		},
+		{
+			name:   "whole input",
+			input:  "{.a}",
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "quoted value containing a brace",
+			input:  `x {title="{y"}`,
+			want:   2,
+			wantOK: true,
+		},
+		{
+			name:   "must be preceded by whitespace",
+			input:  "Title{#t}",
+			wantOK: false,
+		},
+		{
+			name:   "escaped opening brace",
+			input:  `Title \{#t}`,
+			wantOK: false,
+		},
+		{
+			name:   "not at the end",
+			input:  "{#t} Title",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := attribute.Trailing(tc.input)
+
+			assert.Equal(t, ok, tc.wantOK)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  ast.Attributes
+	}{
+		{
+			name:  "classes accumulate",
+			input: "{.a .b class=c}",
+			want:  ast.Attributes{"class": "a b c"},
+		},
+		{
+			name:  "last value of a repeated key wins",
+			input: "{#a lang=en lang=fr}",
+			want:  ast.Attributes{"id": "a", "lang": "fr"},
+		},
+		{
+			name:  "keys are lowercased and values unquoted",
+			input: `{Data-X="one two" lang='en' width=64}`,
+			want:  ast.Attributes{"data-x": "one two", "lang": "en", "width": "64"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := source.NewSource(tc.input)
+			span := source.ByteSpan{Start: 0, End: src.EOF()}
+
+			got, err := attribute.Parse(src, span)
+			require.NoError(t, err)
+
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParse_InvalidName(t *testing.T) {
+	src := source.NewSource("# Title {.a on-click?=x}")
+	span := source.ByteSpan{Start: 8, End: src.EOF()}
+
+	_, err := attribute.Parse(src, span)
+
+	var diagErr diagnostic.DiagnosticError
+	require.True(t, errors.As(err, &diagErr))
+
+	assert.Equal(t, diagErr.Diagnostic.Message, `invalid attribute name "on-click?"`)
+	assert.Equal(t, diagErr.Diagnostic.Span, source.ByteSpan{Start: 12, End: 21})
+	assert.Equal(t, diagErr.Diagnostic.Severity, diagnostic.SeverityError)
+}
+
+func TestParse_DuplicateID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		wantMsg  string
+		wantSpan source.ByteSpan
+	}{
+		{
+			name:     "second hash id",
+			input:    "# Title {#a .b #c}",
+			wantMsg:  `duplicate id "c": already set to "a"`,
+			wantSpan: source.ByteSpan{Start: 15, End: 17},
+		},
+		{
+			name:     "id key after hash id",
+			input:    `# Title {#a id="c d"}`,
+			wantMsg:  `duplicate id "c d": already set to "a"`,
+			wantSpan: source.ByteSpan{Start: 12, End: 20},
+		},
+		{
+			name:     "hash id after id key",
+			input:    "# Title {ID=a #a}",
+			wantMsg:  `duplicate id "a": already set to "a"`,
+			wantSpan: source.ByteSpan{Start: 14, End: 16},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := source.NewSource(tc.input)
+			span := source.ByteSpan{Start: 8, End: src.EOF()}
+
+			_, err := attribute.Parse(src, span)
+
+			var diagErr diagnostic.DiagnosticError
+			require.True(t, errors.As(err, &diagErr))
+
+			assert.Equal(t, diagErr.Diagnostic.Message, tc.wantMsg)
+			assert.Equal(t, diagErr.Diagnostic.Span, tc.wantSpan)
+			assert.Equal(t, diagErr.Diagnostic.Severity, diagnostic.SeverityError)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package attribute recognizes and parses attribute lists of the form
+// {.class #id key=value}.
+//
+// Recognition is purely syntactic and is used by the block and inline
+// parsers to decide where an attribute list begins and ends. Parsing
+// interprets a recognized list into ast.Attributes and validates attribute
+// names, reporting invalid names as diagnostics located at the offending
+// item.
+package attribute
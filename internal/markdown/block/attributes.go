@@ -0,0 +1,151 @@
+package block
+
+import (
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/attribute"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// trailingAttributes reports whether s, which begins at base in the source,
+// ends with an attribute list. It returns the span of the list and the
+// portion of s preceding it.
+//
+// It always reports false when the Attributes extension is disabled.
+func trailingAttributes(c *Cursor, base source.BytePos, s string) (source.ByteSpan, string, bool) {
+	if !c.Metadata.Extensions.Has(extension.Attributes) {
+		return source.ByteSpan{}, "", false
+	}
+
+	start, ok := attribute.Trailing(s)
+	if !ok {
+		return source.ByteSpan{}, "", false
+	}
+
+	end := len(strings.TrimRight(s, " \t"))
+
+	span := source.ByteSpan{
+		Start: base + source.BytePos(start),
+		End:   base + source.BytePos(end),
+	}
+
+	return span, s[:start], true
+}
+
+// attributeLine reports whether line consists solely of an attribute list
+// and returns the span of the list.
+//
+// It always reports false when the Attributes extension is disabled.
+func attributeLine(c *Cursor, line Line) (source.ByteSpan, bool) {
+	if !c.Metadata.Extensions.Has(extension.Attributes) || line.IsBlankLine(c.Source) {
+		return source.ByteSpan{}, false
+	}
+
+	indentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || indentCols > MaxValidIndentation {
+		return source.ByteSpan{}, false
+	}
+
+	s := strings.TrimRight(c.Source.Slice(line.Span)[indentBytes:], " \t")
+
+	n, ok := attribute.Scan(s)
+	if !ok || n != len(s) {
+		return source.ByteSpan{}, false
+	}
+
+	span := source.ByteSpan{
+		Start: line.Span.Start + source.BytePos(indentBytes),
+		End:   line.Span.Start + source.BytePos(indentBytes+n),
+	}
+
+	return span, true
+}
+
+// consumeAttributeLines consumes the attribute lines following block and
+// attaches them to it. Blank lines may separate the lines from the block, so
+// that lists and other containers can be annotated after their last item.
+//
+// Lines are left in place when block does not accept attributes, so that
+// they are parsed as ordinary content.
+func consumeAttributeLines(c *Cursor, block ir.Block) ir.Block {
+	for {
+		m := c.Mark()
+		c.SkipBlankLines()
+
+		line, ok := c.Peek()
+		if !ok {
+			c.Reset(m)
+			return block
+		}
+
+		span, ok := attributeLine(c, line)
+		if !ok {
+			c.Reset(m)
+			return block
+		}
+
+		attached, ok := withAttributes(block, span)
+		if !ok {
+			c.Reset(m)
+			return block
+		}
+
+		c.MustNext()
+		block = attached
+	}
+}
+
+// withAttributes returns block with span appended to its attribute lists,
+// reporting false when block does not accept attributes.
+func withAttributes(block ir.Block, span source.ByteSpan) (ir.Block, bool) {
+	switch b := block.(type) {
+	case ir.Paragraph:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.Header:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.BlockQuote:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.OrderedList:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.UnorderedList:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.DefinitionList:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.IndentedCodeBlock:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.FencedCodeBlock:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.MathBlock:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.LineBlock:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	case ir.ThematicBreak:
+		b.Attributes = append(b.Attributes, span)
+		return b, true
+
+	default:
+		return block, false
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
@@ -20,26 +21,32 @@ var ErrNoLineConsumed = errors.New("build rule accepted but did not advance curs
 // current cursor position.
 var ErrNoRuleMatched = errors.New("no build rule could be applied")
 
-// BuildMetadata carries auxiliary state accumulated during block building.
+// BuildMetadata carries the configuration and auxiliary state shared by
+// rules during block building.
 type BuildMetadata struct {
-	Definitions map[string]ir.ReferenceDefinition
+	Extensions    extension.Set
+	Definitions   map[string]ir.ReferenceDefinition
+	Abbreviations map[string]ir.AbbreviationDefinition
 }
 
 // Build constructs the block-level IR document for src.
-func Build(src *source.Source, lines []Line) (ir.Document, error) {
+func Build(src *source.Source, lines []Line, exts extension.Set) (ir.Document, error) {
 	metadata := &BuildMetadata{
-		Definitions: map[string]ir.ReferenceDefinition{},
+		Extensions:    exts,
+		Definitions:   map[string]ir.ReferenceDefinition{},
+		Abbreviations: map[string]ir.AbbreviationDefinition{},
 	}
 
-	blocks, err := buildBlocks(src, defaultRules(), lines, 0, metadata)
+	blocks, err := buildBlocks(src, defaultRules(exts), lines, 0, metadata)
 	if err != nil {
 		return ir.Document{}, err
 	}
 
 	irDoc := ir.Document{
-		Source:      src,
-		Blocks:      blocks,
-		Definitions: metadata.Definitions,
+		Source:        src,
+		Blocks:        blocks,
+		Definitions:   metadata.Definitions,
+		Abbreviations: metadata.Abbreviations,
 	}
 
 	return irDoc, nil
@@ -77,7 +84,7 @@ func buildBlocks(src *source.Source, rules []BuildRule, lines []Line, baselineCo
 			matched = true
 
 			if applied != nil {
-				blocks = append(blocks, applied)
+				blocks = append(blocks, consumeAttributeLines(c, applied))
 			}
 
 			break
@@ -91,18 +98,39 @@ func buildBlocks(src *source.Source, rules []BuildRule, lines []Line, baselineCo
 	return blocks, nil
 }
 
-// defaultRules returns the block build rules in precedence order.
-func defaultRules() []BuildRule {
-	return []BuildRule{
+// defaultRules returns the block build rules in precedence order, including
+// the rules contributed by extensions enabled in exts.
+func defaultRules(exts extension.Set) []BuildRule {
+	rules := []BuildRule{
 		BlockQuoteRule{},
 		HeaderRule{},
 		ThematicBreakRule{},
 		OrderedListRule{},
 		UnorderedListRule{},
 		FencedCodeBlockRule{},
+	}
+
+	if exts.Has(extension.Math) {
+		rules = append(rules, MathBlockRule{})
+	}
+
+	if exts.Has(extension.LineBlocks) {
+		rules = append(rules, LineBlockRule{})
+	}
+
+	rules = append(rules,
 		IndentedCodeBlockRule{},
 		HTMLBlockRule{},
 		ReferenceDefinitionRule{},
-		ParagraphRule{},
+	)
+
+	if exts.Has(extension.Abbreviations) {
+		rules = append(rules, AbbreviationRule{})
 	}
+
+	if exts.Has(extension.DefinitionLists) {
+		rules = append(rules, DefinitionListRule{})
+	}
+
+	return append(rules, ParagraphRule{})
 }
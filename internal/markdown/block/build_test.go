@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 	tk "github.com/spcameron/seanpatrickcameron.com/internal/markdown/testkit"
@@ -15,6 +16,7 @@ func TestBuild(t *testing.T) {
 	testCases := []struct {
 		name    string
 		input   string
+		exts    extension.Set
 		want    ir.Document
 		wantErr error
 	}{
@@ -2759,6 +2761,549 @@ func TestBuild(t *testing.T) {
 			),
 			wantErr: nil,
 		},
+
+		// Math blocks
+
+		{
+			name: "math block: ignored without the extension",
+			input: strings.Join([]string{
+				"$$",
+				"x",
+				"$$",
+			}, "\n"),
+			want: tk.IRDoc(
+				tk.IRPara("$$", "x", "$$"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "math block: fenced",
+			input: strings.Join([]string{
+				"$$",
+				`\frac{a}{b}`,
+				"+ c",
+				"$$",
+			}, "\n"),
+			exts: extension.Math,
+			want: tk.IRDoc(
+				tk.IRMathBlock(`\frac{a}{b}`, "+ c"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "math block: single line",
+			input: "$$ x^2 $$",
+			exts:  extension.Math,
+			want: tk.IRDoc(
+				tk.IRMathBlock(" x^2 "),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "math block: interrupts a paragraph",
+			input: strings.Join([]string{
+				"a",
+				"$$",
+				"x",
+				"$$",
+			}, "\n"),
+			exts: extension.Math,
+			want: tk.IRDoc(
+				tk.IRPara("a"),
+				tk.IRMathBlock("x"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "math block: unterminated fence is a paragraph",
+			input: strings.Join([]string{
+				"$$",
+				"x",
+			}, "\n"),
+			exts: extension.Math,
+			want: tk.IRDoc(
+				tk.IRPara("$$", "x"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "math block: trailing text after closing fence is a paragraph",
+			input: "$$x$$ is nice",
+			exts:  extension.Math,
+			want: tk.IRDoc(
+				tk.IRPara("$$x$$ is nice"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "math block: inside block quote",
+			input: strings.Join([]string{
+				"> $$",
+				"> x",
+				"> $$",
+			}, "\n"),
+			exts: extension.Math,
+			want: tk.IRDoc(
+				tk.IRBlockQuote(
+					tk.IRMathBlock("x"),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Definition lists
+
+		{
+			name: "definition list: term and definition",
+			input: strings.Join([]string{
+				"Apple",
+				": A fruit.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					true,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: multiple terms and definitions",
+			input: strings.Join([]string{
+				"Apple",
+				"Pomme",
+				": A fruit.",
+				": A company.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					true,
+					tk.IRDefinitionItem(2,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+						tk.IRDefinition(
+							tk.IRPara("A company."),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: items separated by a blank line stay tight",
+			input: strings.Join([]string{
+				"Apple",
+				": A fruit.",
+				"",
+				"Orange",
+				": A citrus.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					true,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+					),
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A citrus."),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: blank line before a definition makes it loose",
+			input: strings.Join([]string{
+				"Apple",
+				"",
+				": A fruit.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					false,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: indented block content",
+			input: strings.Join([]string{
+				"Apple",
+				":   A fruit",
+				"    that grows on trees.",
+				"",
+				"        code",
+				"",
+				"    - red",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					false,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit", "that grows on trees."),
+							tk.IRIndentedCodeBlock("code"),
+							tk.IRUnorderedList(
+								true,
+								tk.IRListItem(
+									tk.IRPara("red"),
+								),
+							),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: unindented line ends the definition",
+			input: strings.Join([]string{
+				"Apple",
+				": A fruit.",
+				"Not a term.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRDefinitionList(
+					true,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+					),
+				),
+				tk.IRPara("Not a term."),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: setext underline wins over terms",
+			input: strings.Join([]string{
+				"Heading",
+				"=======",
+				": A definition.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRHeader(1, "Heading"),
+				tk.IRPara(": A definition."),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: block before terms is not a term",
+			input: strings.Join([]string{
+				"# Heading",
+				"Apple",
+				": A fruit.",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRHeader(1, "Heading"),
+				tk.IRDefinitionList(
+					true,
+					tk.IRDefinitionItem(1,
+						tk.IRDefinition(
+							tk.IRPara("A fruit."),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: colon without a space is not a marker",
+			input: strings.Join([]string{
+				"Apple",
+				":fruit",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRPara("Apple", ":fruit"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: paragraph without a definition",
+			input: strings.Join([]string{
+				"Apple",
+				"Orange",
+				"",
+				"Pear",
+			}, "\n"),
+			exts: extension.DefinitionLists,
+			want: tk.IRDoc(
+				tk.IRPara("Apple", "Orange"),
+				tk.IRPara("Pear"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "definition list: markers are paragraph text when disabled",
+			input: strings.Join([]string{
+				"Apple",
+				": A fruit.",
+			}, "\n"),
+			want: tk.IRDoc(
+				tk.IRPara("Apple", ": A fruit."),
+			),
+			wantErr: nil,
+		},
+
+		// Attributes
+
+		{
+			name:  "attributes: atx heading with trailing list",
+			input: "# Title {#t}",
+			exts:  extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRWithAttributes(tk.IRHeader(1, "Title"), 1),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: fenced code info string with trailing list",
+			input: strings.Join([]string{
+				"```go {.x}",
+				"a",
+				"```",
+			}, "\n"),
+			exts: extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRWithAttributes(tk.IRFencedCodeBlock(0, "a"), 1),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: attribute line ends a paragraph and attaches to it",
+			input: strings.Join([]string{
+				"a",
+				"b",
+				"{.x}",
+				"c",
+			}, "\n"),
+			exts: extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRWithAttributes(tk.IRPara("a", "b"), 1),
+				tk.IRPara("c"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: consecutive attribute lines attach to a block quote",
+			input: strings.Join([]string{
+				"> a",
+				"",
+				"{.x}",
+				"{#y}",
+			}, "\n"),
+			exts: extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRWithAttributes(tk.IRBlockQuote(
+					tk.IRPara("a"),
+				), 2),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: attribute line without a preceding block is a paragraph",
+			input: strings.Join([]string{
+				"{.x}",
+			}, "\n"),
+			exts: extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRPara("{.x}"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: attribute line does not attach to an html block",
+			input: strings.Join([]string{
+				"<div>",
+				"</div>",
+				"",
+				"{.x}",
+			}, "\n"),
+			exts: extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRHTMLBlock("<div>", "</div>"),
+				tk.IRPara("{.x}"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "attributes: attribute line is paragraph text when disabled",
+			input: strings.Join([]string{
+				"a",
+				"{.x}",
+			}, "\n"),
+			want: tk.IRDoc(
+				tk.IRPara("a", "{.x}"),
+			),
+			wantErr: nil,
+		},
+
+		// Abbreviations
+
+		{
+			name:  "abbreviation: definition is recorded without a block",
+			input: "*[HTML]: HyperText Markup Language",
+			exts:  extension.Abbreviations,
+			want: ir.Document{
+				Abbreviations: map[string]ir.AbbreviationDefinition{
+					"HTML": {Term: "HTML"},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "abbreviation: empty expansion and multiword term",
+			input: "*[W3C]:\n*[Web API]: Web application programming interface",
+			exts:  extension.Abbreviations,
+			want: ir.Document{
+				Abbreviations: map[string]ir.AbbreviationDefinition{
+					"W3C":     {Term: "W3C"},
+					"Web API": {Term: "Web API"},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "abbreviation: does not interrupt a paragraph",
+			input: "text\n*[HTML]: HyperText Markup Language",
+			exts:  extension.Abbreviations,
+			want: tk.IRDoc(
+				tk.IRPara("text", "*[HTML]: HyperText Markup Language"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "abbreviation: padded term is a paragraph",
+			input: "*[ HTML ]: HyperText Markup Language",
+			exts:  extension.Abbreviations,
+			want: tk.IRDoc(
+				tk.IRPara("*[ HTML ]: HyperText Markup Language"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "abbreviation: paragraph when disabled",
+			input: "*[HTML]: HyperText Markup Language",
+			want: tk.IRDoc(
+				tk.IRPara("*[HTML]: HyperText Markup Language"),
+			),
+			wantErr: nil,
+		},
+
+		// line blocks
+
+		{
+			name: "line block: marker lines keep their indentation",
+			input: strings.Join([]string{
+				"| The limerick packs laughs anatomical",
+				"|    In space that is quite economical.",
+				"|",
+				"| But the good ones I've seen",
+			}, "\n"),
+			exts: extension.LineBlocks,
+			want: tk.IRDoc(
+				tk.IRLineBlock(
+					tk.IRLineBlockLine(0, 1),
+					tk.IRLineBlockLine(3, 1),
+					tk.IRLineBlockLine(0, 0),
+					tk.IRLineBlockLine(0, 1),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "line block: space-led line continues the previous line",
+			input: strings.Join([]string{
+				"| The Right Honorable Most Venerable and Righteous Samuel L.",
+				"  Constable, Jr.",
+				"| 200 Main St.",
+			}, "\n"),
+			exts: extension.LineBlocks,
+			want: tk.IRDoc(
+				tk.IRLineBlock(
+					tk.IRLineBlockLine(0, 2),
+					tk.IRLineBlockLine(0, 1),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "line block: ends at a line without a marker",
+			input: strings.Join([]string{
+				"| a",
+				"b",
+			}, "\n"),
+			exts: extension.LineBlocks,
+			want: tk.IRDoc(
+				tk.IRLineBlock(
+					tk.IRLineBlockLine(0, 1),
+				),
+				tk.IRPara("b"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "line block: does not interrupt a paragraph",
+			input: strings.Join([]string{
+				"a",
+				"| b",
+			}, "\n"),
+			exts: extension.LineBlocks,
+			want: tk.IRDoc(
+				tk.IRPara("a", "| b"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:    "line block: marker must be followed by a space",
+			input:   "|a|b|",
+			exts:    extension.LineBlocks,
+			want:    tk.IRDoc(tk.IRPara("|a|b|")),
+			wantErr: nil,
+		},
+		{
+			name:    "line block: marker is text when disabled",
+			input:   "| a",
+			exts:    0,
+			want:    tk.IRDoc(tk.IRPara("| a")),
+			wantErr: nil,
+		},
+		{
+			name: "line block: accepts attributes",
+			input: strings.Join([]string{
+				"| a",
+				"{.poem}",
+			}, "\n"),
+			exts: extension.LineBlocks | extension.Attributes,
+			want: tk.IRDoc(
+				tk.IRWithAttributes(tk.IRLineBlock(tk.IRLineBlockLine(0, 1)), 1),
+			),
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2768,7 +3313,7 @@ func TestBuild(t *testing.T) {
 			lines, err := Scan(src)
 			require.NoError(t, err)
 
-			got, err := Build(src, lines)
+			got, err := Build(src, lines, tc.exts)
 
 			got = tk.NormalizeIR(got)
 			want := tk.NormalizeIR(tc.want)
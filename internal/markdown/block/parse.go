@@ -1,18 +1,20 @@
 package block
 
 import (
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
-// Parse scans source lines and builds the block-level IR document.
-func Parse(src *source.Source) (ir.Document, error) {
+// Parse scans source lines and builds the block-level IR document, using
+// the block rules of any enabled extensions.
+func Parse(src *source.Source, exts extension.Set) (ir.Document, error) {
 	lines, err := Scan(src)
 	if err != nil {
 		return ir.Document{}, err
 	}
 
-	out, err := Build(src, lines)
+	out, err := Build(src, lines, exts)
 	if err != nil {
 		return ir.Document{}, err
 	}
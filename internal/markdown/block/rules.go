@@ -200,6 +200,80 @@ func (r ReferenceDefinitionRule) Apply(c *Cursor) (ir.Block, bool, error) {
 	return nil, false, nil
 }
 
+// AbbreviationRule parses *[TERM]: expansion abbreviation definitions and
+// records them in the build metadata without producing a block node.
+//
+// Like reference definitions, an abbreviation definition cannot interrupt
+// a paragraph. The first definition of a term wins.
+type AbbreviationRule struct{}
+
+func (r AbbreviationRule) isParagraphTransparent() {}
+
+func (r AbbreviationRule) Apply(c *Cursor) (ir.Block, bool, error) {
+	line, ok := c.Peek()
+	if !ok || line.IsBlankLine(c.Source) {
+		return nil, false, nil
+	}
+
+	indentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || indentCols > MaxValidIndentation {
+		return nil, false, nil
+	}
+
+	s := c.Source.Slice(line.Span)
+	pos := indentBytes
+	lineBase := line.Span.Start
+
+	if !strings.HasPrefix(s[pos:], "*[") {
+		return nil, false, nil
+	}
+	pos += 2
+
+	termStart := pos
+	for pos < len(s) && s[pos] != ']' {
+		pos++
+	}
+
+	term := s[termStart:pos]
+	if pos >= len(s) || strings.TrimSpace(term) != term || term == "" {
+		return nil, false, nil
+	}
+	termEnd := pos
+	pos++
+
+	if pos >= len(s) || s[pos] != ':' {
+		return nil, false, nil
+	}
+	pos++
+
+	pos = consumeSpacesTabs(s, pos)
+	titleStart := pos
+	title := strings.TrimRight(s[pos:], " \t")
+
+	def := ir.AbbreviationDefinition{
+		FullSpan: source.ByteSpan{
+			Start: lineBase + source.BytePos(indentBytes),
+			End:   lineBase + source.BytePos(titleStart+len(title)),
+		},
+		TermSpan: source.ByteSpan{
+			Start: lineBase + source.BytePos(termStart),
+			End:   lineBase + source.BytePos(termEnd),
+		},
+		TitleSpan: source.ByteSpan{
+			Start: lineBase + source.BytePos(titleStart),
+			End:   lineBase + source.BytePos(titleStart+len(title)),
+		},
+		Term: term,
+	}
+
+	if _, exists := c.Metadata.Abbreviations[term]; !exists {
+		c.Metadata.Abbreviations[term] = def
+	}
+
+	c.MustNext()
+	return nil, true, nil
+}
+
 func tryLinkDestination(s string, pos int) (source.ByteSpan, int, bool) {
 	if pos >= len(s) {
 		return source.ByteSpan{}, 0, false
@@ -1103,11 +1177,364 @@ func (r UnorderedListRule) tryParseMarkerLine(c *Cursor, line Line, listIndentCo
 	return result, true
 }
 
+// DLMarkerLineResult captures the parsed structure of a definition marker
+// line and the derived positions used to parse the definition body.
+type DLMarkerLineResult struct {
+	MarkerLine      Line
+	ContentLine     Line
+	ItemContentCols int
+}
+
+// DefinitionListRule parses definition lists in the PHP Markdown Extra
+// style: one or more term lines, each group followed by one or more
+// definitions introduced by a ":" marker.
+//
+// Term lines are ordinary paragraph text until a definition marker follows
+// them, so the rule is paragraph-transparent and must be ordered
+// immediately before ParagraphRule. A blank line between a term and its
+// definition, between definitions, or within a definition makes the list
+// loose.
+type DefinitionListRule struct{}
+
+func (DefinitionListRule) isParagraphTransparent() {}
+
+func (r DefinitionListRule) Apply(c *Cursor) (ir.Block, bool, error) {
+	terms, result, sepBlanks, ok, err := r.tryConsumeItemHead(c, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	items := make([]ir.DefinitionItem, 0, 4)
+	tight := true
+
+	for {
+		if sepBlanks {
+			tight = false
+		}
+
+		definitions := make([]ir.Definition, 0, 1)
+
+		for {
+			lines, spans, keptBlank := r.consumeDefinitionBody(c, result)
+			if keptBlank {
+				tight = false
+			}
+
+			children, err := buildBlocks(c.Source, c.Rules, lines, 0, c.Metadata)
+			if err != nil {
+				return nil, false, err
+			}
+
+			// defensive panic
+			if len(spans) == 0 {
+				panic("definition list invariant violated: consumed marker line but produced no definition spans")
+			}
+
+			definition := ir.Definition{
+				Span: source.ByteSpan{
+					Start: result.MarkerLine.Span.Start,
+					End:   spans[len(spans)-1].End,
+				},
+				Children: children,
+			}
+
+			definitions = append(definitions, definition)
+
+			result, sepBlanks, ok = r.tryConsumeSiblingDefinition(c)
+			if !ok {
+				break
+			}
+			if sepBlanks {
+				tight = false
+			}
+		}
+
+		item := ir.DefinitionItem{
+			Span: source.ByteSpan{
+				Start: terms[0].Start,
+				End:   definitions[len(definitions)-1].Span.End,
+			},
+			Terms:       terms,
+			Definitions: definitions,
+		}
+
+		items = append(items, item)
+
+		m := c.Mark()
+		c.SkipBlankLines()
+
+		terms, result, sepBlanks, ok, err = r.tryConsumeItemHead(c, false)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			c.Reset(m)
+			break
+		}
+	}
+
+	listSpan := source.ByteSpan{
+		Start: items[0].Span.Start,
+		End:   items[len(items)-1].Span.End,
+	}
+
+	applied := ir.DefinitionList{
+		Span:  listSpan,
+		Items: items,
+		Tight: tight,
+	}
+
+	return applied, true, nil
+}
+
+// tryConsumeItemHead consumes a run of term lines and the first definition
+// marker line that follows them, rolling back on failure.
+//
+// The term run ends at a blank line, a definition marker, or a block that
+// would interrupt a paragraph. A term run followed by a setext underline is
+// a heading and is declined. When first is false, the opening term line
+// must not itself begin another block.
+func (r DefinitionListRule) tryConsumeItemHead(c *Cursor, first bool) ([]source.ByteSpan, DLMarkerLineResult, bool, bool, error) {
+	m := c.Mark()
+	terms := []source.ByteSpan{}
+
+	for {
+		line, ok := c.Peek()
+		if !ok || line.IsBlankLine(c.Source) {
+			break
+		}
+
+		relIndentCols, indentBytes, ok := c.RelBlockIndent(line)
+		if !ok || relIndentCols > MaxValidIndentation {
+			break
+		}
+
+		if _, _, ok := r.parseMarker(c, line); ok {
+			break
+		}
+
+		if _, isSetext := (ParagraphRule{}).tryParseSetextHeadingLine(c, line); isSetext && len(terms) > 0 {
+			c.Reset(m)
+			return nil, DLMarkerLineResult{}, false, false, nil
+		}
+
+		if len(terms) > 0 || !first {
+			startsBlock, err := c.StartsParagraphInterruptingBlock()
+			if err != nil {
+				c.Reset(m)
+				return nil, DLMarkerLineResult{}, false, false, err
+			}
+			if startsBlock {
+				break
+			}
+		}
+
+		line = c.MustNext()
+		terms = append(terms, trimTermSpan(c.Source, line.Span, indentBytes))
+	}
+
+	if len(terms) == 0 {
+		c.Reset(m)
+		return nil, DLMarkerLineResult{}, false, false, nil
+	}
+
+	result, sepBlanks, ok := r.tryConsumeSiblingDefinition(c)
+	if !ok {
+		c.Reset(m)
+		return nil, DLMarkerLineResult{}, false, false, nil
+	}
+
+	return terms, result, sepBlanks, true, nil
+}
+
+// tryConsumeSiblingDefinition attempts to consume a definition marker line
+// after any blank lines, rolling back on failure.
+func (r DefinitionListRule) tryConsumeSiblingDefinition(c *Cursor) (DLMarkerLineResult, bool, bool) {
+	m := c.Mark()
+	consumedBlanks := false
+
+	line, ok := c.Peek()
+	if !ok {
+		return DLMarkerLineResult{}, false, false
+	}
+
+	for line.IsBlankLine(c.Source) {
+		c.MustNext()
+		consumedBlanks = true
+
+		line, ok = c.Peek()
+		if !ok {
+			c.Reset(m)
+			return DLMarkerLineResult{}, false, false
+		}
+	}
+
+	contentOffsetBytes, itemContentCols, ok := r.parseMarker(c, line)
+	if !ok {
+		c.Reset(m)
+		return DLMarkerLineResult{}, false, false
+	}
+
+	markerLine := c.MustNext()
+
+	contentLine := Line{
+		Span: source.ByteSpan{
+			Start: markerLine.Span.Start + source.BytePos(contentOffsetBytes),
+			End:   markerLine.Span.End,
+		},
+	}
+
+	result := DLMarkerLineResult{
+		MarkerLine:      markerLine,
+		ContentLine:     contentLine,
+		ItemContentCols: itemContentCols,
+	}
+
+	return result, consumedBlanks, true
+}
+
+// consumeDefinitionBody collects the lines belonging to a definition,
+// rebasing content lines to the definition baseline and handling trailing
+// blank runs.
+func (r DefinitionListRule) consumeDefinitionBody(c *Cursor, start DLMarkerLineResult) ([]Line, []source.ByteSpan, bool) {
+	itemSpans := []source.ByteSpan{start.MarkerLine.Span}
+	itemLines := []Line{start.ContentLine}
+
+	blankRun := struct {
+		active     bool
+		cursorMark int
+		spanMark   int
+		lineMark   int
+	}{
+		active: false,
+	}
+
+	keptBlank := false
+
+	for {
+		nextLine, ok := c.Peek()
+		if !ok {
+			break
+		}
+
+		if nextLine.IsBlankLine(c.Source) {
+			if !blankRun.active {
+				blankRun.active = true
+				blankRun.cursorMark = c.Mark()
+				blankRun.spanMark = len(itemSpans)
+				blankRun.lineMark = len(itemLines)
+			}
+
+			line := c.MustNext()
+			itemSpans = append(itemSpans, line.Span)
+			itemLines = append(itemLines, line)
+
+			continue
+		}
+
+		absIndentCols, _ := c.AbsBlockIndent(nextLine)
+
+		if absIndentCols >= start.ItemContentCols {
+			if blankRun.active {
+				keptBlank = true
+			}
+
+			blankRun.active = false
+
+			line := c.MustNext()
+			itemSpans = append(itemSpans, line.Span)
+
+			trimmed := line.TrimIndentToCols(c.Source, start.ItemContentCols)
+			itemLines = append(itemLines, trimmed)
+
+			continue
+		}
+
+		if blankRun.active {
+			blankRun.active = false
+
+			c.Reset(blankRun.cursorMark)
+			itemSpans = itemSpans[:blankRun.spanMark]
+			itemLines = itemLines[:blankRun.lineMark]
+		}
+
+		break
+	}
+
+	return itemLines, itemSpans, keptBlank
+}
+
+// parseMarker reports whether line begins with a definition marker: up to
+// three columns of indentation, a colon, and at least one space or tab. It
+// returns the byte offset and column at which the definition content
+// begins, without consuming the line.
+func (DefinitionListRule) parseMarker(c *Cursor, line Line) (int, int, bool) {
+	relIndentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || relIndentCols > MaxValidIndentation {
+		return 0, 0, false
+	}
+
+	col, _ := c.AbsBlockIndent(line)
+
+	s := c.Source.Slice(line.Span)
+	pos := indentBytes
+
+	if pos >= len(s) || s[pos] != ':' {
+		return 0, 0, false
+	}
+
+	pos++
+	col++
+
+	if pos >= len(s) || (s[pos] != ' ' && s[pos] != '\t') {
+		return 0, 0, false
+	}
+
+	for pos < len(s) {
+		b := s[pos]
+
+		if b == ' ' {
+			col++
+			pos++
+			continue
+		}
+
+		if b == '\t' {
+			col += source.TabWidth - (col % source.TabWidth)
+			pos++
+			continue
+		}
+
+		break
+	}
+
+	return pos, col, true
+}
+
+// trimTermSpan returns span without its leading indentation and trailing
+// spaces and tabs.
+func trimTermSpan(src *source.Source, span source.ByteSpan, indentBytes int) source.ByteSpan {
+	s := src.Slice(span)
+	end := len(s)
+
+	for end > indentBytes && (s[end-1] == ' ' || s[end-1] == '\t') {
+		end--
+	}
+
+	return source.ByteSpan{
+		Start: span.Start + source.BytePos(indentBytes),
+		End:   span.Start + source.BytePos(end),
+	}
+}
+
 // HeaderRule parses ATX headings.
 type HeaderRule struct{}
 
 func (r HeaderRule) Apply(c *Cursor) (ir.Block, bool, error) {
-	level, contentSpan, ok := r.tryParseHeaderLine(c)
+	level, contentSpan, attrSpans, ok := r.tryParseHeaderLine(c)
 	if !ok {
 		return nil, false, nil
 	}
@@ -1119,20 +1546,24 @@ func (r HeaderRule) Apply(c *Cursor) (ir.Block, bool, error) {
 		ContentSpan:  contentSpan,
 		ContentLines: []source.ByteSpan{contentSpan},
 		Level:        level,
+		Attributes:   attrSpans,
 	}
 
 	return applied, true, nil
 }
 
-func (HeaderRule) tryParseHeaderLine(c *Cursor) (int, source.ByteSpan, bool) {
+// tryParseHeaderLine parses an ATX heading line and returns its level, its
+// content span, and, when the Attributes extension is enabled, the span of
+// a trailing attribute list.
+func (HeaderRule) tryParseHeaderLine(c *Cursor) (int, source.ByteSpan, []source.ByteSpan, bool) {
 	line, ok := c.Peek()
 	if !ok || line.IsBlankLine(c.Source) {
-		return 0, source.ByteSpan{}, false
+		return 0, source.ByteSpan{}, nil, false
 	}
 
 	indentCols, indentBytes, ok := c.RelBlockIndent(line)
 	if !ok || indentCols > MaxValidIndentation {
-		return 0, source.ByteSpan{}, false
+		return 0, source.ByteSpan{}, nil, false
 	}
 
 	s := c.Source.Slice(line.Span)
@@ -1140,24 +1571,30 @@ func (HeaderRule) tryParseHeaderLine(c *Cursor) (int, source.ByteSpan, bool) {
 	level := 0
 
 	if pos >= len(s) || s[pos] != '#' {
-		return 0, source.ByteSpan{}, false
+		return 0, source.ByteSpan{}, nil, false
 	}
 
 	for pos < len(s) && s[pos] == '#' {
 		pos++
 		level++
 		if level == 7 {
-			return 0, source.ByteSpan{}, false
+			return 0, source.ByteSpan{}, nil, false
 		}
 	}
 
 	if pos < len(s) && s[pos] != ' ' && s[pos] != '\t' {
-		return 0, source.ByteSpan{}, false
+		return 0, source.ByteSpan{}, nil, false
 	}
 
 	fieldStart := pos
 	field := s[fieldStart:]
 
+	var attrSpans []source.ByteSpan
+	if attrSpan, rest, ok := trailingAttributes(c, line.Span.Start+source.BytePos(fieldStart), field); ok {
+		attrSpans = []source.ByteSpan{attrSpan}
+		field = rest
+	}
+
 	fieldEnd := len(strings.TrimRight(field, " \t"))
 	if end, ok := atxContentEnd(field); ok {
 		fieldEnd = end
@@ -1176,7 +1613,7 @@ func (HeaderRule) tryParseHeaderLine(c *Cursor) (int, source.ByteSpan, bool) {
 		End:   contentEnd,
 	}
 
-	return level, contentSpan, true
+	return level, contentSpan, attrSpans, true
 }
 
 // atxContentEnd reports the end offset of the heading content field after
@@ -1411,6 +1848,7 @@ type FCBMarkerLineResult struct {
 	MarkerCount    int
 	OpenIndentCols int
 	InfoString     source.ByteSpan
+	Attributes     []source.ByteSpan
 }
 
 // FencedCodeBlockRule parses fenced code blocks and collects their payload
@@ -1430,6 +1868,7 @@ func (r FencedCodeBlockRule) Apply(c *Cursor) (ir.Block, bool, error) {
 		OpenIndentCols: result.OpenIndentCols,
 		InfoStringSpan: result.InfoString,
 		Lines:          payload,
+		Attributes:     result.Attributes,
 	}
 
 	return applied, true, nil
@@ -1505,11 +1944,18 @@ func (FencedCodeBlockRule) tryParseOpeningFenceLine(c *Cursor) (FCBMarkerLineRes
 		End:   line.Span.End,
 	}
 
+	var attrSpans []source.ByteSpan
+	if attrSpan, rest, ok := trailingAttributes(c, infoStringSpan.Start, s[pos:]); ok {
+		attrSpans = []source.ByteSpan{attrSpan}
+		infoStringSpan.End = infoStringSpan.Start + source.BytePos(len(strings.TrimRight(rest, " \t")))
+	}
+
 	result := FCBMarkerLineResult{
 		Marker:         marker,
 		MarkerCount:    markerCount,
 		OpenIndentCols: indentCols,
 		InfoString:     infoStringSpan,
+		Attributes:     attrSpans,
 	}
 
 	return result, true
@@ -1597,6 +2043,224 @@ func (FencedCodeBlockRule) tryParseClosingFenceLine(c *Cursor, marker byte, mark
 	return true
 }
 
+// MathBlockRule parses display math blocks delimited by $$ fences.
+//
+// A math block either opens and closes on a single line, as in $$ x^2 $$,
+// or opens with a line holding only $$ and runs until a matching closing
+// line. An opening fence without a closing fence does not form a block.
+type MathBlockRule struct{}
+
+func (r MathBlockRule) Apply(c *Cursor) (ir.Block, bool, error) {
+	line, ok := c.Peek()
+	if !ok || line.IsBlankLine(c.Source) {
+		return nil, false, nil
+	}
+
+	indentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || indentCols > MaxValidIndentation {
+		return nil, false, nil
+	}
+
+	s := c.Source.Slice(line.Span)
+	rest := strings.TrimRight(s[indentBytes:], " \t")
+
+	if !strings.HasPrefix(rest, "$$") {
+		return nil, false, nil
+	}
+
+	if rest == "$$" {
+		return r.consumeMathBlock(c)
+	}
+
+	// single-line form: $$ content $$
+	if len(rest) <= 4 || !strings.HasSuffix(rest, "$$") {
+		return nil, false, nil
+	}
+
+	content := rest[2 : len(rest)-2]
+	if strings.Contains(content, "$$") || strings.TrimSpace(content) == "" {
+		return nil, false, nil
+	}
+
+	contentStart := line.Span.Start + source.BytePos(indentBytes+2)
+	contentSpan := source.ByteSpan{
+		Start: contentStart,
+		End:   contentStart + source.BytePos(len(content)),
+	}
+
+	c.MustNext()
+
+	applied := ir.MathBlock{
+		Span:  line.Span,
+		Lines: []source.ByteSpan{contentSpan},
+	}
+
+	return applied, true, nil
+}
+
+// consumeMathBlock consumes an opening $$ fence, its payload lines, and the
+// closing fence. It declines without advancing when no closing fence
+// follows.
+func (r MathBlockRule) consumeMathBlock(c *Cursor) (ir.Block, bool, error) {
+	m := c.Mark()
+
+	opener := c.MustNext()
+	lineSpans := []source.ByteSpan{}
+
+	for {
+		line, ok := c.Peek()
+		if !ok {
+			c.Reset(m)
+			return nil, false, nil
+		}
+
+		if r.isClosingFenceLine(c, line) {
+			closer := c.MustNext()
+
+			applied := ir.MathBlock{
+				Span: source.ByteSpan{
+					Start: opener.Span.Start,
+					End:   closer.Span.End,
+				},
+				Lines: lineSpans,
+			}
+
+			return applied, true, nil
+		}
+
+		line = c.MustNext()
+		lineSpans = append(lineSpans, line.Span)
+	}
+}
+
+// isClosingFenceLine reports whether line holds only a $$ fence.
+func (MathBlockRule) isClosingFenceLine(c *Cursor, line Line) bool {
+	indentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || indentCols > MaxValidIndentation {
+		return false
+	}
+
+	s := c.Source.Slice(line.Span)
+	return strings.TrimRight(s[indentBytes:], " \t") == "$$"
+}
+
+// LineBlockRule parses line blocks: runs of lines that open with a "|"
+// marker followed by a space, or consisting of the marker alone.
+//
+// A line beginning with a space continues the line above it rather than
+// starting a new one. Like reference definitions, a line block cannot
+// interrupt a paragraph.
+type LineBlockRule struct{}
+
+func (r LineBlockRule) isParagraphTransparent() {}
+
+func (r LineBlockRule) Apply(c *Cursor) (ir.Block, bool, error) {
+	first, ok := c.Peek()
+	if !ok {
+		return nil, false, nil
+	}
+
+	if _, ok := r.tryParseMarkerLine(c, first); !ok {
+		return nil, false, nil
+	}
+
+	var lines []ir.LineBlockLine
+	last := first
+
+	for {
+		line, ok := c.Peek()
+		if !ok || line.IsBlankLine(c.Source) {
+			break
+		}
+
+		if l, ok := r.tryParseMarkerLine(c, line); ok {
+			lines = append(lines, l)
+		} else if span, ok := r.tryParseContinuationLine(c, line); ok {
+			cur := &lines[len(lines)-1]
+			cur.Spans = append(cur.Spans, span)
+		} else {
+			break
+		}
+
+		last = c.MustNext()
+	}
+
+	applied := ir.LineBlock{
+		Span: source.ByteSpan{
+			Start: first.Span.Start,
+			End:   last.Span.End,
+		},
+		Lines: lines,
+	}
+
+	return applied, true, nil
+}
+
+// tryParseMarkerLine reports whether line opens a new line of a line block
+// and returns its indentation and content.
+func (LineBlockRule) tryParseMarkerLine(c *Cursor, line Line) (ir.LineBlockLine, bool) {
+	if line.IsBlankLine(c.Source) {
+		return ir.LineBlockLine{}, false
+	}
+
+	indentCols, indentBytes, ok := c.RelBlockIndent(line)
+	if !ok || indentCols > MaxValidIndentation {
+		return ir.LineBlockLine{}, false
+	}
+
+	s := strings.TrimRight(c.Source.Slice(line.Span), " \t")
+	pos := indentBytes
+
+	if pos >= len(s) || s[pos] != '|' {
+		return ir.LineBlockLine{}, false
+	}
+	pos++
+
+	if pos == len(s) {
+		return ir.LineBlockLine{Spans: []source.ByteSpan{}}, true
+	}
+	if s[pos] != ' ' {
+		return ir.LineBlockLine{}, false
+	}
+	pos++
+
+	indent := 0
+	for pos < len(s) && s[pos] == ' ' {
+		indent++
+		pos++
+	}
+
+	if pos == len(s) {
+		return ir.LineBlockLine{Spans: []source.ByteSpan{}}, true
+	}
+
+	span := source.ByteSpan{
+		Start: line.Span.Start + source.BytePos(pos),
+		End:   line.Span.Start + source.BytePos(len(s)),
+	}
+
+	return ir.LineBlockLine{Indent: indent, Spans: []source.ByteSpan{span}}, true
+}
+
+// tryParseContinuationLine reports whether line continues the previous
+// line of a line block and returns its trimmed content.
+func (LineBlockRule) tryParseContinuationLine(c *Cursor, line Line) (source.ByteSpan, bool) {
+	s := c.Source.Slice(line.Span)
+	if !strings.HasPrefix(s, " ") {
+		return source.ByteSpan{}, false
+	}
+
+	trimmed := strings.TrimRight(s, " \t")
+	start := len(trimmed) - len(strings.TrimLeft(trimmed, " \t"))
+
+	span := source.ByteSpan{
+		Start: line.Span.Start + source.BytePos(start),
+		End:   line.Span.Start + source.BytePos(len(trimmed)),
+	}
+
+	return span, true
+}
+
 // HTMLBlockRule parses block-level HTML constructs and consumes their
 // lines according to the recognized block terminator.
 type HTMLBlockRule struct{}
@@ -1822,11 +2486,21 @@ func (r ParagraphRule) Apply(c *Cursor) (ir.Block, bool, error) {
 				End:   underline.Span.End,
 			}
 
+			var attrSpans []source.ByteSpan
+			last := lineSpans[len(lineSpans)-1]
+			if attrSpan, rest, ok := trailingAttributes(c, last.Start, c.Source.Slice(last)); ok {
+				attrSpans = []source.ByteSpan{attrSpan}
+				last.End = last.Start + source.BytePos(len(strings.TrimRight(rest, " \t")))
+				lineSpans[len(lineSpans)-1] = last
+				contentSpan.End = last.End
+			}
+
 			applied := ir.Header{
 				Span:         headerSpan,
 				ContentSpan:  contentSpan,
 				ContentLines: lineSpans,
 				Level:        level,
+				Attributes:   attrSpans,
 			}
 
 			return applied, true, nil
@@ -1863,6 +2537,10 @@ func (r ParagraphRule) consumeParagraphRun(c *Cursor) ([]source.ByteSpan, bool,
 			break
 		}
 
+		if _, ok := attributeLine(c, line); ok {
+			break
+		}
+
 		startsBlock, err := c.StartsParagraphInterruptingBlock()
 		if err != nil {
 			return nil, false, err
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/block"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/codegen"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/lower"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
@@ -18,6 +19,8 @@ func TestGenerateHTML(t *testing.T) {
 	testCases := []struct {
 		name    string
 		input   string
+		exts    extension.Set
+		opts    codegen.Options
 		want    html.Node
 		wantErr error
 	}{
@@ -884,19 +887,391 @@ func TestGenerateHTML(t *testing.T) {
 			),
 			wantErr: nil,
 		},
+
+		// Math
+
+		{
+			name:  "inline math renders MathML",
+			input: "$x^2$",
+			exts:  extension.Math,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode(
+						"math",
+						nil,
+						tk.HTMLElementNode(
+							"msup",
+							nil,
+							tk.HTMLElementNode("mi", nil, tk.HTMLTextNode("x")),
+							tk.HTMLElementNode("mn", nil, tk.HTMLTextNode("2")),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "math block renders display MathML",
+			input: strings.Join([]string{
+				"$$",
+				`\alpha`,
+				"$$",
+			}, "\n"),
+			exts: extension.Math,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"math",
+					html.Attributes{"display": "block"},
+					tk.HTMLElementNode("mi", nil, tk.HTMLTextNode("α")),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Callouts
+
+		{
+			name:  "callout renders an aside with its default title",
+			input: "> [!CAUTION]\n> hot",
+			exts:  extension.Callouts,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"aside",
+					html.Attributes{"class": "callout callout-caution"},
+					tk.HTMLElementNode(
+						"h2",
+						html.Attributes{"class": "callout-title"},
+						tk.HTMLTextNode("Caution"),
+					),
+					tk.HTMLElementNode(
+						"p",
+						nil,
+						tk.HTMLTextNode("hot"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "callout renders its own title",
+			input: "> [!IMPORTANT] Read `this`",
+			exts:  extension.Callouts,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"aside",
+					html.Attributes{"class": "callout callout-important"},
+					tk.HTMLElementNode(
+						"h2",
+						html.Attributes{"class": "callout-title"},
+						tk.HTMLTextNode("Read "),
+						tk.HTMLElementNode(
+							"code",
+							nil,
+							tk.HTMLTextNode("this"),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Definition lists
+
+		{
+			name:  "definition list renders dt and dd elements",
+			input: "a\n: b\n: c",
+			exts:  extension.DefinitionLists,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"dl",
+					nil,
+					tk.HTMLElementNode(
+						"dt",
+						nil,
+						tk.HTMLTextNode("a"),
+					),
+					tk.HTMLElementNode(
+						"dd",
+						nil,
+						tk.HTMLTextNode("b"),
+					),
+					tk.HTMLElementNode(
+						"dd",
+						nil,
+						tk.HTMLTextNode("c"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Attributes
+
+		{
+			name:  "attributes merge into derived element attributes",
+			input: "```go {.numbered #listing}\nx\n```",
+			exts:  extension.Attributes,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"pre",
+					html.Attributes{"class": "numbered", "id": "listing"},
+					tk.HTMLElementNode(
+						"code",
+						html.Attributes{"class": "language-go"},
+						tk.HTMLTextNode("x"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "attributes append classes and keep derived values",
+			input: `> [!NOTE]` + "\n> a\n\n{.wide id=n}\n\n[b](/c){href=/d .ext}",
+			exts:  extension.Attributes | extension.Callouts,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"aside",
+					html.Attributes{"class": "callout callout-note wide", "id": "n"},
+					tk.HTMLElementNode(
+						"h2",
+						html.Attributes{"class": "callout-title"},
+						tk.HTMLTextNode("Note"),
+					),
+					tk.HTMLElementNode(
+						"p",
+						nil,
+						tk.HTMLTextNode("a"),
+					),
+				),
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode(
+						"a",
+						html.Attributes{"class": "ext", "href": "/c"},
+						tk.HTMLTextNode("b"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Superscript, subscript, strikethrough, highlight, and insert
+
+		{
+			name:  "styled inlines render phrasing elements",
+			input: "^a^ ~b~ ~~c~~ ==d== ++e++",
+			exts:  extension.Superscript | extension.Subscript | extension.Strikethrough | extension.Highlight | extension.Insert,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode("sup", nil, tk.HTMLTextNode("a")),
+					tk.HTMLTextNode(" "),
+					tk.HTMLElementNode("sub", nil, tk.HTMLTextNode("b")),
+					tk.HTMLTextNode(" "),
+					tk.HTMLElementNode("del", nil, tk.HTMLTextNode("c")),
+					tk.HTMLTextNode(" "),
+					tk.HTMLElementNode("mark", nil, tk.HTMLTextNode("d")),
+					tk.HTMLTextNode(" "),
+					tk.HTMLElementNode("ins", nil, tk.HTMLTextNode("e")),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Emoji
+
+		{
+			name:  "emoji renders its characters",
+			input: ":tada:",
+			exts:  extension.Emoji,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLTextNode("🎉"),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "labeled emoji renders an image span",
+			input: ":tada:",
+			exts:  extension.Emoji | extension.EmojiLabels,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode(
+						"span",
+						html.Attributes{"role": "img", "aria-label": "party popper"},
+						tk.HTMLTextNode("🎉"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Abbreviations
+
+		{
+			name:  "abbreviation renders abbr with its expansion",
+			input: "*[CSS]: Cascading Style Sheets\n*[W3C]:\n\nCSS W3C",
+			exts:  extension.Abbreviations,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode(
+						"abbr",
+						html.Attributes{"title": "Cascading Style Sheets"},
+						tk.HTMLTextNode("CSS"),
+					),
+					tk.HTMLTextNode(" "),
+					tk.HTMLElementNode(
+						"abbr",
+						nil,
+						tk.HTMLTextNode("W3C"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Line blocks
+
+		{
+			name:  "line block renders lines separated by br",
+			input: "| a\n|  b",
+			exts:  extension.LineBlocks,
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"div",
+					html.Attributes{"class": "line-block"},
+					tk.HTMLTextNode("a"),
+					tk.HTMLVoidNode("br", nil),
+					tk.HTMLTextNode("\u00a0b"),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Images
+
+		{
+			name:  "lone image renders as figure with caption",
+			input: `![a cat](cat.png "A cat")`,
+			opts:  codegen.Options{Figures: true},
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"figure",
+					nil,
+					tk.HTMLVoidNode(
+						"img",
+						html.Attributes{"src": "cat.png", "alt": "a cat"},
+					),
+					tk.HTMLElementNode(
+						"figcaption",
+						nil,
+						tk.HTMLTextNode("A cat"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "image with text stays inline when figures are enabled",
+			input: `see ![a cat](cat.png "A cat")`,
+			opts:  codegen.Options{Figures: true},
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLTextNode("see "),
+					tk.HTMLVoidNode(
+						"img",
+						html.Attributes{"src": "cat.png", "alt": "a cat", "title": "A cat"},
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "lazy sized image",
+			input: "![a cat](cat.png)",
+			opts: codegen.Options{
+				LazyImages: true,
+				ImageSize: func(ref codegen.URLRef) (codegen.ImageSize, bool) {
+					return codegen.ImageSize{Width: 640, Height: 480}, ref.URL == "cat.png"
+				},
+			},
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLVoidNode(
+						"img",
+						html.Attributes{
+							"src":      "cat.png",
+							"alt":      "a cat",
+							"loading":  "lazy",
+							"decoding": "async",
+							"width":    "640",
+							"height":   "480",
+						},
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// URL rewriting
+
+		{
+			name:  "rewriter receives link and image destinations",
+			input: "[a](x.html) ![b](y.png)",
+			opts: codegen.Options{
+				RewriteURL: func(ref codegen.URLRef) codegen.URLRewrite {
+					return codegen.URLRewrite{
+						URL:        "/" + ref.Kind.String() + "/" + ref.URL,
+						Attributes: html.Attributes{"data-kind": ref.Kind.String()},
+					}
+				},
+			},
+			want: tk.HTMLFragmentNode(
+				tk.HTMLElementNode(
+					"p",
+					nil,
+					tk.HTMLElementNode(
+						"a",
+						html.Attributes{"href": "/link/x.html", "data-kind": "link"},
+						tk.HTMLTextNode("a"),
+					),
+					tk.HTMLTextNode(" "),
+					tk.HTMLVoidNode(
+						"img",
+						html.Attributes{"src": "/image/y.png", "alt": "b", "data-kind": "image"},
+					),
+				),
+			),
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			src := source.NewSource(tc.input)
 
-			irDoc, err := block.Parse(src)
+			irDoc, err := block.Parse(src, tc.exts)
 			require.NoError(t, err)
 
-			astDoc, err := lower.Document(irDoc)
+			astDoc, err := lower.Document(irDoc, tc.exts)
 			require.NoError(t, err)
 
-			got, err := codegen.HTML(astDoc)
+			got, err := codegen.HTMLWith(astDoc, tc.opts)
 
 			assert.Equal(t, got, tc.want)
 			assert.ErrorIs(t, err, tc.wantErr)
@@ -3,4 +3,6 @@
 // Rendering operates solely on ast types and does not depend on parsing
 // details or raw Markdown source. It walks the AST and produces HTML
 // nodes or strings according to the selected dialect and rendering rules.
+// Options supplied to HTMLWith may rewrite link and image destinations as
+// they are rendered.
 package codegen
@@ -2,22 +2,34 @@ package codegen
 
 import (
 	"fmt"
+	stdhtml "html"
 	"strconv"
 	"strings"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/mathml"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
 // HTML renders an AST document into an HTML node tree.
 func HTML(doc ast.Document) (html.Node, error) {
+	return HTMLWith(doc, Options{})
+}
+
+// HTMLWith renders an AST document into an HTML node tree using opts.
+func HTMLWith(doc ast.Document, opts Options) (html.Node, error) {
+	ctx := &Context{
+		Source:  doc.Source,
+		Options: opts,
+	}
+
 	rootNode := html.Fragment{
 		Children: make([]html.Node, 0, len(doc.Blocks)),
 	}
 
 	for _, v := range doc.Blocks {
-		node, err := renderBlock(doc.Source, v)
+		node, err := renderBlock(ctx, v)
 		if err != nil {
 			return nil, err
 		}
@@ -28,31 +40,43 @@ func HTML(doc ast.Document) (html.Node, error) {
 	return rootNode, nil
 }
 
-func renderBlock(src *source.Source, block ast.Block) (html.Node, error) {
+func renderBlock(ctx *Context, block ast.Block) (html.Node, error) {
 	switch v := block.(type) {
 	case ast.BlockQuote:
-		return renderBlockQuote(src, v)
+		return renderBlockQuote(ctx, v)
+
+	case ast.Callout:
+		return renderCallout(ctx, v)
 
 	case ast.Header:
-		return renderHeader(src, v)
+		return renderHeader(ctx, v)
 
 	case ast.ThematicBreak:
-		return renderThematicBreak()
+		return renderThematicBreak(v)
 
 	case ast.OrderedList:
-		return renderOrderedList(src, v)
+		return renderOrderedList(ctx, v)
 
 	case ast.UnorderedList:
-		return renderUnorderedList(src, v)
+		return renderUnorderedList(ctx, v)
+
+	case ast.DefinitionList:
+		return renderDefinitionList(ctx, v)
 
 	case ast.CodeBlock:
-		return renderCodeBlock(src, v)
+		return renderCodeBlock(ctx, v)
 
 	case ast.HTMLBlock:
-		return renderHTMLBlock(src, v)
+		return renderHTMLBlock(ctx, v)
+
+	case ast.MathBlock:
+		return renderMathBlock(ctx, v)
+
+	case ast.LineBlock:
+		return renderLineBlock(ctx, v)
 
 	case ast.Paragraph:
-		return renderParagraph(src, v)
+		return renderParagraph(ctx, v)
 
 	default:
 		return nil, fmt.Errorf("unrecognized block type: %T", block)
@@ -87,15 +111,67 @@ func appendChildren(dst []html.Node, src []html.Node) []html.Node {
 	return dst
 }
 
-func renderBlockQuote(src *source.Source, block ast.BlockQuote) (html.Node, error) {
+func renderBlockQuote(ctx *Context, block ast.BlockQuote) (html.Node, error) {
 	node := html.Element{
 		Tag:      "blockquote",
-		Attr:     html.Attributes{},
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
 		Children: make([]html.Node, 0, len(block.Children)),
 	}
 
 	for _, bqChild := range block.Children {
-		htmlChild, err := renderBlock(src, bqChild)
+		htmlChild, err := renderBlock(ctx, bqChild)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = appendChild(node.Children, htmlChild)
+	}
+
+	return node, nil
+}
+
+// renderCallout renders a callout as an <aside> whose first child is the
+// callout title.
+//
+// When the callout carries no title of its own, the default title for its
+// kind is used, falling back to the kind itself. The title is a heading at
+// the callout's TitleLevel, shifted by HeadingOffset like any other heading.
+func renderCallout(ctx *Context, block ast.Callout) (html.Node, error) {
+	var title []html.Node
+	if len(block.Title) > 0 {
+		inlines, err := renderInlines(ctx, block.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		title = inlines
+	} else {
+		text, ok := ast.CalloutKinds[block.Kind]
+		if !ok {
+			text = block.Kind
+		}
+
+		title = []html.Node{html.Text{Value: text}}
+	}
+
+	level := min(max(block.TitleLevel+ctx.Options.HeadingOffset, 1), 6)
+
+	heading := html.Element{
+		Tag:      fmt.Sprintf("h%d", level),
+		Attr:     html.Attributes{"class": "callout-title"},
+		Children: title,
+	}
+
+	node := html.Element{
+		Tag:      "aside",
+		Attr:     mergeAttributes(html.Attributes{"class": "callout callout-" + block.Kind}, block.Attributes),
+		Children: make([]html.Node, 0, len(block.Children)+1),
+	}
+
+	node.Children = append(node.Children, heading)
+
+	for _, child := range block.Children {
+		htmlChild, err := renderBlock(ctx, child)
 		if err != nil {
 			return nil, err
 		}
@@ -106,31 +182,33 @@ func renderBlockQuote(src *source.Source, block ast.BlockQuote) (html.Node, erro
 	return node, nil
 }
 
-func renderHeader(src *source.Source, block ast.Header) (html.Node, error) {
-	children, err := renderInlines(src, block.Inlines)
+func renderHeader(ctx *Context, block ast.Header) (html.Node, error) {
+	children, err := renderInlines(ctx, block.Inlines)
 	if err != nil {
 		return nil, err
 	}
 
+	level := min(max(block.Level+ctx.Options.HeadingOffset, 1), 6)
+
 	node := html.Element{
-		Tag:      fmt.Sprintf("h%d", block.Level),
-		Attr:     html.Attributes{},
+		Tag:      fmt.Sprintf("h%d", level),
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
 		Children: children,
 	}
 
 	return node, nil
 }
 
-func renderThematicBreak() (html.Node, error) {
+func renderThematicBreak(block ast.ThematicBreak) (html.Node, error) {
 	node := html.VoidElement{
 		Tag:  "hr",
-		Attr: html.Attributes{},
+		Attr: mergeAttributes(html.Attributes{}, block.Attributes),
 	}
 
 	return node, nil
 }
 
-func renderOrderedList(src *source.Source, block ast.OrderedList) (html.Node, error) {
+func renderOrderedList(ctx *Context, block ast.OrderedList) (html.Node, error) {
 	attr := html.Attributes{}
 	if block.Start != 1 {
 		attr["start"] = strconv.Itoa(block.Start)
@@ -138,12 +216,12 @@ func renderOrderedList(src *source.Source, block ast.OrderedList) (html.Node, er
 
 	node := html.Element{
 		Tag:      "ol",
-		Attr:     attr,
+		Attr:     mergeAttributes(attr, block.Attributes),
 		Children: make([]html.Node, 0, len(block.Items)),
 	}
 
 	for _, olItem := range block.Items {
-		liNode, err := renderListItem(src, olItem, block.Tight)
+		liNode, err := renderListItem(ctx, olItem, block.Tight)
 		if err != nil {
 			return nil, err
 		}
@@ -154,15 +232,15 @@ func renderOrderedList(src *source.Source, block ast.OrderedList) (html.Node, er
 	return node, nil
 }
 
-func renderUnorderedList(src *source.Source, block ast.UnorderedList) (html.Node, error) {
+func renderUnorderedList(ctx *Context, block ast.UnorderedList) (html.Node, error) {
 	node := html.Element{
 		Tag:      "ul",
-		Attr:     html.Attributes{},
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
 		Children: make([]html.Node, 0, len(block.Items)),
 	}
 
 	for _, ulItem := range block.Items {
-		liNode, err := renderListItem(src, ulItem, block.Tight)
+		liNode, err := renderListItem(ctx, ulItem, block.Tight)
 		if err != nil {
 			return nil, err
 		}
@@ -177,7 +255,7 @@ func renderUnorderedList(src *source.Source, block ast.UnorderedList) (html.Node
 //
 // For tight lists, a paragraph child is unwrapped so that its inline content
 // is emitted directly inside the <li> rather than nested in <p>.
-func renderListItem(src *source.Source, block ast.ListItem, tight bool) (html.Node, error) {
+func renderListItem(ctx *Context, block ast.ListItem, tight bool) (html.Node, error) {
 	node := html.Element{
 		Tag:  "li",
 		Attr: html.Attributes{},
@@ -186,13 +264,13 @@ func renderListItem(src *source.Source, block ast.ListItem, tight bool) (html.No
 	if tight {
 		for _, liChild := range block.Children {
 			if p, ok := liChild.(ast.Paragraph); ok {
-				inlines, err := renderInlines(src, p.Inlines)
+				inlines, err := renderInlines(ctx, p.Inlines)
 				if err != nil {
 					return nil, err
 				}
 				node.Children = appendChildren(node.Children, inlines)
 			} else {
-				htmlChild, err := renderBlock(src, liChild)
+				htmlChild, err := renderBlock(ctx, liChild)
 				if err != nil {
 					return nil, err
 				}
@@ -204,7 +282,77 @@ func renderListItem(src *source.Source, block ast.ListItem, tight bool) (html.No
 	}
 
 	for _, liChild := range block.Children {
-		htmlChild, err := renderBlock(src, liChild)
+		htmlChild, err := renderBlock(ctx, liChild)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = appendChild(node.Children, htmlChild)
+	}
+
+	return node, nil
+}
+
+// renderDefinitionList renders a definition list as a <dl> holding a <dt>
+// for each term and a <dd> for each definition.
+func renderDefinitionList(ctx *Context, block ast.DefinitionList) (html.Node, error) {
+	node := html.Element{
+		Tag:      "dl",
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
+		Children: make([]html.Node, 0, len(block.Items)*2),
+	}
+
+	for _, dlItem := range block.Items {
+		for _, term := range dlItem.Terms {
+			children, err := renderInlines(ctx, term.Inlines)
+			if err != nil {
+				return nil, err
+			}
+
+			dtNode := html.Element{
+				Tag:      "dt",
+				Attr:     html.Attributes{},
+				Children: children,
+			}
+
+			node.Children = appendChild(node.Children, dtNode)
+		}
+
+		for _, def := range dlItem.Definitions {
+			ddNode, err := renderDefinition(ctx, def, block.Tight)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Children = appendChild(node.Children, ddNode)
+		}
+	}
+
+	return node, nil
+}
+
+// renderDefinition renders a definition.
+//
+// For tight lists, a paragraph child is unwrapped so that its inline content
+// is emitted directly inside the <dd> rather than nested in <p>.
+func renderDefinition(ctx *Context, block ast.Definition, tight bool) (html.Node, error) {
+	node := html.Element{
+		Tag:  "dd",
+		Attr: html.Attributes{},
+	}
+
+	for _, ddChild := range block.Children {
+		if p, ok := ddChild.(ast.Paragraph); ok && tight {
+			inlines, err := renderInlines(ctx, p.Inlines)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Children = appendChildren(node.Children, inlines)
+			continue
+		}
+
+		htmlChild, err := renderBlock(ctx, ddChild)
 		if err != nil {
 			return nil, err
 		}
@@ -215,21 +363,21 @@ func renderListItem(src *source.Source, block ast.ListItem, tight bool) (html.No
 	return node, nil
 }
 
-func renderCodeBlock(src *source.Source, block ast.CodeBlock) (html.Node, error) {
+func renderCodeBlock(ctx *Context, block ast.CodeBlock) (html.Node, error) {
 	attr := html.Attributes{}
 
-	languageString := src.Slice(block.LanguageTokenSpan)
+	languageString := ctx.Source.Slice(block.LanguageTokenSpan)
 	if languageString != "" {
 		attr["class"] = fmt.Sprintf("language-%s", languageString)
 	}
-	payload, err := renderInlines(src, block.Payload)
+	payload, err := renderInlines(ctx, block.Payload)
 	if err != nil {
 		return nil, err
 	}
 
 	node := html.Element{
 		Tag:  "pre",
-		Attr: html.Attributes{},
+		Attr: mergeAttributes(html.Attributes{}, block.Attributes),
 		Children: []html.Node{
 			html.Element{
 				Tag:      "code",
@@ -242,8 +390,8 @@ func renderCodeBlock(src *source.Source, block ast.CodeBlock) (html.Node, error)
 	return node, nil
 }
 
-func renderHTMLBlock(src *source.Source, block ast.HTMLBlock) (html.Node, error) {
-	children, err := renderInlines(src, block.Payload)
+func renderHTMLBlock(ctx *Context, block ast.HTMLBlock) (html.Node, error) {
+	children, err := renderInlines(ctx, block.Payload)
 	if err != nil {
 		return nil, err
 	}
@@ -255,26 +403,80 @@ func renderHTMLBlock(src *source.Source, block ast.HTMLBlock) (html.Node, error)
 	return node, nil
 }
 
-func renderParagraph(src *source.Source, block ast.Paragraph) (html.Node, error) {
-	children, err := renderInlines(src, block.Inlines)
+func renderMathBlock(ctx *Context, block ast.MathBlock) (html.Node, error) {
+	node, err := mathml.Translate(ctx.Source, block.Lines, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if el, ok := node.(html.Element); ok {
+		el.Attr = mergeAttributes(el.Attr, block.Attributes)
+		return el, nil
+	}
+
+	return node, nil
+}
+
+// renderLineBlock renders a line block as a <div class="line-block"> whose
+// lines are separated by <br>. Leading indentation is kept as non-breaking
+// spaces.
+func renderLineBlock(ctx *Context, block ast.LineBlock) (html.Node, error) {
+	node := html.Element{
+		Tag:      "div",
+		Attr:     mergeAttributes(html.Attributes{"class": "line-block"}, block.Attributes),
+		Children: []html.Node{},
+	}
+
+	for i, line := range block.Lines {
+		if i > 0 {
+			br, err := renderHardBreak()
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, br)
+		}
+
+		if line.Indent > 0 {
+			node.Children = appendChild(node.Children, html.Text{Value: strings.Repeat("\u00a0", line.Indent)})
+		}
+
+		children, err := renderInlines(ctx, line.Inlines)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = appendChildren(node.Children, children)
+	}
+
+	return node, nil
+}
+
+func renderParagraph(ctx *Context, block ast.Paragraph) (html.Node, error) {
+	if ctx.Options.Figures && len(block.Inlines) == 1 {
+		if img, ok := block.Inlines[0].(ast.Image); ok {
+			return renderFigure(ctx, block, img)
+		}
+	}
+
+	children, err := renderInlines(ctx, block.Inlines)
 	if err != nil {
 		return nil, err
 	}
 
 	node := html.Element{
 		Tag:      "p",
-		Attr:     html.Attributes{},
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
 		Children: children,
 	}
 
 	return node, nil
 }
 
-func renderInlines(src *source.Source, inlines []ast.Inline) ([]html.Node, error) {
+func renderInlines(ctx *Context, inlines []ast.Inline) ([]html.Node, error) {
 	children := make([]html.Node, 0, len(inlines))
 
 	for _, inl := range inlines {
-		child, err := renderInline(src, inl)
+		child, err := renderInline(ctx, inl)
 		if err != nil {
 			return nil, err
 		}
@@ -285,28 +487,61 @@ func renderInlines(src *source.Source, inlines []ast.Inline) ([]html.Node, error
 	return children, nil
 }
 
-func renderInline(src *source.Source, inl ast.Inline) (html.Node, error) {
+func renderInline(ctx *Context, inl ast.Inline) (html.Node, error) {
 	switch v := inl.(type) {
 	case ast.CodeSpan:
-		return renderCodeSpan(src, v)
+		return renderCodeSpan(ctx, v)
 
 	case ast.Image:
-		return renderImage(src, v)
+		return renderImage(ctx, v)
 
 	case ast.Link:
-		return renderLink(src, v)
+		return renderLink(ctx, v)
+
+	case ast.BracketedSpan:
+		return renderBracketedSpan(ctx, v)
 
 	case ast.Emph:
-		return renderEmphasis(src, v)
+		return renderEmphasis(ctx, v)
 
 	case ast.Strong:
-		return renderStrong(src, v)
+		return renderStrong(ctx, v)
+
+	case ast.Superscript:
+		return renderStyled(ctx, "sup", v.Children)
+
+	case ast.Subscript:
+		return renderStyled(ctx, "sub", v.Children)
+
+	case ast.Strikethrough:
+		return renderStyled(ctx, "del", v.Children)
+
+	case ast.Highlight:
+		return renderStyled(ctx, "mark", v.Children)
+
+	case ast.Insert:
+		return renderStyled(ctx, "ins", v.Children)
 
 	case ast.Text:
-		return renderText(src, v)
+		return renderText(ctx, v)
 
 	case ast.RawText:
-		return renderRawText(src, v)
+		return renderRawText(ctx, v)
+
+	case ast.SmartPunct:
+		return renderSmartPunct(v)
+
+	case ast.Emoji:
+		return renderEmoji(v)
+
+	case ast.Abbreviation:
+		return renderAbbreviation(ctx, v)
+
+	case ast.WikiLink:
+		return renderWikiLink(ctx, v)
+
+	case ast.Math:
+		return renderMath(ctx, v)
 
 	case ast.SoftBreak:
 		return renderSoftBreak()
@@ -322,9 +557,9 @@ func renderInline(src *source.Source, inl ast.Inline) (html.Node, error) {
 	}
 }
 
-func renderCodeSpan(src *source.Source, inl ast.CodeSpan) (html.Node, error) {
+func renderCodeSpan(ctx *Context, inl ast.CodeSpan) (html.Node, error) {
 	contentNode := html.Text{
-		Value: src.Slice(inl.Span),
+		Value: ctx.Source.Slice(inl.Span),
 	}
 
 	node := html.Element{
@@ -336,19 +571,91 @@ func renderCodeSpan(src *source.Source, inl ast.CodeSpan) (html.Node, error) {
 	return node, nil
 }
 
-func renderImage(src *source.Source, inl ast.Image) (html.Node, error) {
-	alt, err := inlineText(src, inl.Children)
+func renderImage(ctx *Context, inl ast.Image) (html.Node, error) {
+	return imageElement(ctx, inl, true)
+}
+
+// renderFigure renders a paragraph holding only an image as a <figure>,
+// moving the image title into a <figcaption>.
+func renderFigure(ctx *Context, block ast.Paragraph, inl ast.Image) (html.Node, error) {
+	img, err := imageElement(ctx, inl, false)
 	if err != nil {
 		return nil, err
 	}
 
+	node := html.Element{
+		Tag:      "figure",
+		Attr:     mergeAttributes(html.Attributes{}, block.Attributes),
+		Children: []html.Node{img},
+	}
+
+	if inl.Title != (source.ByteSpan{}) {
+		node.Children = append(node.Children, html.Element{
+			Tag:  "figcaption",
+			Attr: html.Attributes{},
+			Children: []html.Node{
+				html.Text{Value: ctx.Source.UnescapedSlice(inl.Title)},
+			},
+		})
+	}
+
+	return node, nil
+}
+
+// imageElement renders inl as an <img>, carrying its title as an attribute
+// when withTitle is set.
+//
+// Loading hints, intrinsic dimensions, and srcset candidates are added only
+// where the image's own attributes do not already name them.
+func imageElement(ctx *Context, inl ast.Image, withTitle bool) (html.VoidElement, error) {
+	alt, err := inlineText(ctx, inl.Children)
+	if err != nil {
+		return html.VoidElement{}, err
+	}
+
 	attr := html.Attributes{
-		"src": src.UnescapedSlice(inl.Destination),
 		"alt": alt,
 	}
 
-	if inl.Title != (source.ByteSpan{}) {
-		attr["title"] = src.UnescapedSlice(inl.Title)
+	if withTitle && inl.Title != (source.ByteSpan{}) {
+		attr["title"] = ctx.Source.UnescapedSlice(inl.Title)
+	}
+
+	ref := URLRef{
+		Kind: URLImage,
+		URL:  ctx.Source.UnescapedSlice(inl.Destination),
+		Span: inl.Span,
+	}
+
+	ctx.setURL(attr, "src", ref)
+	attr = mergeAttributes(attr, inl.Attributes)
+
+	if ctx.Options.LazyImages {
+		setDefault(attr, "loading", "lazy")
+		setDefault(attr, "decoding", "async")
+	}
+
+	ref.Source = ctx.Source
+
+	_, hasWidth := attr["width"]
+	_, hasHeight := attr["height"]
+	if ctx.Options.ImageSize != nil && !hasWidth && !hasHeight {
+		if size, ok := ctx.Options.ImageSize(ref); ok {
+			attr["width"] = strconv.Itoa(size.Width)
+			attr["height"] = strconv.Itoa(size.Height)
+		}
+	}
+
+	if _, ok := attr["srcset"]; ctx.Options.Srcset != nil && !ok {
+		if candidates, ok := ctx.Options.Srcset(ref); ok && len(candidates) > 0 {
+			attr["srcset"] = srcset(candidates)
+
+			sizes := ctx.Options.Sizes
+			if sizes == "" {
+				sizes = "100vw"
+			}
+			setDefault(attr, "sizes", sizes)
+		}
 	}
 
 	node := html.VoidElement{
@@ -359,36 +666,108 @@ func renderImage(src *source.Source, inl ast.Image) (html.Node, error) {
 	return node, nil
 }
 
-func renderLink(src *source.Source, inl ast.Link) (html.Node, error) {
-	inlines, err := renderInlines(src, inl.Children)
+func renderLink(ctx *Context, inl ast.Link) (html.Node, error) {
+	inlines, err := renderInlines(ctx, inl.Children)
 	if err != nil {
 		return nil, err
 	}
 
-	href := src.UnescapedSlice(inl.Destination)
-	if inl.MailTo {
-		href = "mailto:" + href
+	href := ctx.Source.UnescapedSlice(inl.Destination)
+	if inl.Autolink {
+		// the destination is the text as written, so its entity
+		// references must be decoded before the attribute escapes it
+		href = decodeEntities(href)
 	}
 
-	attr := html.Attributes{
-		"href": href,
+	switch {
+	case inl.MailTo:
+		href = "mailto:" + href
+	case inl.WWW:
+		href = "http://" + href
 	}
 
+	attr := html.Attributes{}
+
 	if inl.Title != (source.ByteSpan{}) {
-		attr["title"] = src.UnescapedSlice(inl.Title)
+		attr["title"] = ctx.Source.UnescapedSlice(inl.Title)
 	}
 
+	ctx.setURL(attr, "href", URLRef{
+		Kind:     URLLink,
+		URL:      href,
+		Autolink: inl.Autolink,
+		Span:     inl.Span,
+	})
+
 	node := html.Element{
 		Tag:      "a",
-		Attr:     attr,
+		Attr:     mergeAttributes(attr, inl.Attributes),
 		Children: inlines,
 	}
 
 	return node, nil
 }
 
-func renderEmphasis(src *source.Source, inl ast.Emph) (html.Node, error) {
-	inlines, err := renderInlines(src, inl.Children)
+// decodeEntities replaces each entity or numeric character reference in s
+// that ends in a semicolon with the character it names. Anything else is
+// kept as written, including a legacy name without its semicolon, so a
+// query such as ?a=1&copy=2 survives.
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	for {
+		amp := strings.IndexByte(s, '&')
+		if amp < 0 {
+			break
+		}
+		semi := strings.IndexByte(s[amp:], ';')
+		if semi < 0 {
+			break
+		}
+
+		sb.WriteString(s[:amp])
+
+		ref := s[amp : amp+semi+1]
+		decoded := stdhtml.UnescapeString(ref)
+
+		// an unknown name, or one only a prefix of which is a legacy
+		// entity, leaves the semicolon undecoded
+		if decoded == stdhtml.UnescapeString(ref[:len(ref)-1])+";" {
+			sb.WriteByte('&')
+			s = s[amp+1:]
+			continue
+		}
+
+		sb.WriteString(decoded)
+		s = s[amp+len(ref):]
+	}
+
+	sb.WriteString(s)
+	return sb.String()
+}
+
+func renderBracketedSpan(ctx *Context, inl ast.BracketedSpan) (html.Node, error) {
+	inlines, err := renderInlines(ctx, inl.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	node := html.Element{
+		Tag:      "span",
+		Attr:     mergeAttributes(html.Attributes{}, inl.Attributes),
+		Children: inlines,
+	}
+
+	return node, nil
+}
+
+func renderEmphasis(ctx *Context, inl ast.Emph) (html.Node, error) {
+	inlines, err := renderInlines(ctx, inl.Children)
 	if err != nil {
 		return nil, err
 	}
@@ -402,8 +781,8 @@ func renderEmphasis(src *source.Source, inl ast.Emph) (html.Node, error) {
 	return node, nil
 }
 
-func renderStrong(src *source.Source, inl ast.Strong) (html.Node, error) {
-	inlines, err := renderInlines(src, inl.Children)
+func renderStrong(ctx *Context, inl ast.Strong) (html.Node, error) {
+	inlines, err := renderInlines(ctx, inl.Children)
 	if err != nil {
 		return nil, err
 	}
@@ -417,23 +796,152 @@ func renderStrong(src *source.Source, inl ast.Strong) (html.Node, error) {
 	return node, nil
 }
 
-func renderText(src *source.Source, inl ast.Text) (html.Node, error) {
+// renderStyled renders inline children wrapped in a phrasing element such
+// as <sup> or <mark>.
+func renderStyled(ctx *Context, tag string, children []ast.Inline) (html.Node, error) {
+	inlines, err := renderInlines(ctx, children)
+	if err != nil {
+		return nil, err
+	}
+
+	node := html.Element{
+		Tag:      tag,
+		Attr:     html.Attributes{},
+		Children: inlines,
+	}
+
+	return node, nil
+}
+
+func renderText(ctx *Context, inl ast.Text) (html.Node, error) {
 	node := html.Text{
-		Value: src.Slice(inl.Span),
+		Value: ctx.Source.Slice(inl.Span),
 	}
 
 	return node, nil
 }
 
 // renderRawText emits inline content without HTML escaping.
-func renderRawText(src *source.Source, inl ast.RawText) (html.Node, error) {
+func renderRawText(ctx *Context, inl ast.RawText) (html.Node, error) {
 	node := html.Raw{
-		Value: src.Slice(inl.Span),
+		Value: ctx.Source.Slice(inl.Span),
 	}
 
 	return node, nil
 }
 
+func renderSmartPunct(inl ast.SmartPunct) (html.Node, error) {
+	value, err := smartPunctText(inl.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	node := html.Text{
+		Value: value,
+	}
+
+	return node, nil
+}
+
+// renderEmoji emits the emoji's characters, wrapped in an image-role span
+// carrying its description when the emoji is labeled.
+func renderEmoji(inl ast.Emoji) (html.Node, error) {
+	text := html.Text{
+		Value: inl.Value,
+	}
+
+	if !inl.Labeled {
+		return text, nil
+	}
+
+	node := html.Element{
+		Tag: "span",
+		Attr: html.Attributes{
+			"role":       "img",
+			"aria-label": inl.Description,
+		},
+		Children: []html.Node{text},
+	}
+
+	return node, nil
+}
+
+// renderWikiLink renders a resolved wiki link as an anchor. The link text
+// is the label when one was given, and otherwise the target's title or,
+// failing that, the target as written.
+func renderWikiLink(ctx *Context, inl ast.WikiLink) (html.Node, error) {
+	attr := html.Attributes{}
+	ctx.setURL(attr, "href", URLRef{
+		Kind: URLLink,
+		URL:  inl.Destination,
+		Span: inl.Span,
+	})
+
+	node := html.Element{
+		Tag:  "a",
+		Attr: attr,
+		Children: []html.Node{
+			html.Text{Value: wikiLinkText(ctx, inl)},
+		},
+	}
+
+	return node, nil
+}
+
+func wikiLinkText(ctx *Context, inl ast.WikiLink) string {
+	switch {
+	case inl.Label != (source.ByteSpan{}):
+		return ctx.Source.UnescapedSlice(inl.Label)
+	case inl.Title != "":
+		return inl.Title
+	default:
+		return ctx.Source.Slice(inl.Target)
+	}
+}
+
+func renderAbbreviation(ctx *Context, inl ast.Abbreviation) (html.Node, error) {
+	attr := html.Attributes{}
+	if inl.Title.End > inl.Title.Start {
+		attr["title"] = ctx.Source.UnescapedSlice(inl.Title)
+	}
+
+	node := html.Element{
+		Tag:  "abbr",
+		Attr: attr,
+		Children: []html.Node{
+			html.Text{Value: ctx.Source.Slice(inl.Span)},
+		},
+	}
+
+	return node, nil
+}
+
+// smartPunctText returns the typographic character for kind.
+func smartPunctText(kind ast.PunctKind) (string, error) {
+	switch kind {
+	case ast.LeftDoubleQuote:
+		return "\u201c", nil
+	case ast.RightDoubleQuote:
+		return "\u201d", nil
+	case ast.LeftSingleQuote:
+		return "\u2018", nil
+	case ast.RightSingleQuote:
+		return "\u2019", nil
+	case ast.EnDash:
+		return "\u2013", nil
+	case ast.EmDash:
+		return "\u2014", nil
+	case ast.Ellipsis:
+		return "\u2026", nil
+	default:
+		return "", fmt.Errorf("unrecognized punctuation kind: %s", kind)
+	}
+}
+
+func renderMath(ctx *Context, inl ast.Math) (html.Node, error) {
+	return mathml.Translate(ctx.Source, []source.ByteSpan{inl.Content}, inl.Display)
+}
+
 func renderSoftBreak() (html.Node, error) {
 	node := html.Text{
 		Value: " ",
@@ -461,7 +969,7 @@ func renderNewline() (html.Node, error) {
 
 // inlineText extracts the textual content of inline nodes for contexts such
 // as image alt text.
-func inlineText(src *source.Source, inlines []ast.Inline) (string, error) {
+func inlineText(ctx *Context, inlines []ast.Inline) (string, error) {
 	if len(inlines) == 0 {
 		return "", nil
 	}
@@ -469,7 +977,7 @@ func inlineText(src *source.Source, inlines []ast.Inline) (string, error) {
 	var b strings.Builder
 
 	for _, inl := range inlines {
-		s, err := inlineNodeText(src, inl)
+		s, err := inlineNodeText(ctx, inl)
 		if err != nil {
 			return "", err
 		}
@@ -480,33 +988,111 @@ func inlineText(src *source.Source, inlines []ast.Inline) (string, error) {
 }
 
 // inlineNodeText extracts the text contribution of a single inline node.
-func inlineNodeText(src *source.Source, inl ast.Inline) (string, error) {
+func inlineNodeText(ctx *Context, inl ast.Inline) (string, error) {
 	switch n := inl.(type) {
 
 	case ast.Text:
-		return src.Slice(n.Span), nil
+		return ctx.Source.Slice(n.Span), nil
 
 	case ast.CodeSpan:
-		return src.Slice(n.Span), nil
+		return ctx.Source.Slice(n.Span), nil
 
 	case ast.RawText:
-		return src.Slice(n.Span), nil
+		return ctx.Source.Slice(n.Span), nil
+
+	case ast.SmartPunct:
+		return smartPunctText(n.Kind)
+
+	case ast.Emoji:
+		return n.Value, nil
+
+	case ast.Abbreviation:
+		return ctx.Source.Slice(n.Span), nil
+
+	case ast.WikiLink:
+		return wikiLinkText(ctx, n), nil
+
+	case ast.Math:
+		// alt text carries the TeX source
+		return ctx.Source.Slice(n.Content), nil
 
 	case ast.Emph:
-		return inlineText(src, n.Children)
+		return inlineText(ctx, n.Children)
+
+	case ast.BracketedSpan:
+		return inlineText(ctx, n.Children)
 
 	case ast.Strong:
-		return inlineText(src, n.Children)
+		return inlineText(ctx, n.Children)
+
+	case ast.Superscript:
+		return inlineText(ctx, n.Children)
+
+	case ast.Subscript:
+		return inlineText(ctx, n.Children)
+
+	case ast.Strikethrough:
+		return inlineText(ctx, n.Children)
+
+	case ast.Highlight:
+		return inlineText(ctx, n.Children)
+
+	case ast.Insert:
+		return inlineText(ctx, n.Children)
 
 	case ast.Link:
 		// alt text ignores the destination; use label text
-		return inlineText(src, n.Children)
+		return inlineText(ctx, n.Children)
 
 	case ast.Image:
 		// nested images are rare, but spec allows recursion
-		return inlineText(src, n.Children)
+		return inlineText(ctx, n.Children)
 
 	default:
 		return "", fmt.Errorf("inlineNodeText: unsupported inline type %T", inl)
 	}
 }
+
+// mergeAttributes adds the attributes written in the source to attr, which
+// holds the attributes the renderer derives for the element.
+//
+// Classes from the source are appended to any derived class, while derived
+// attributes such as href, src, and alt take precedence over source
+// attributes of the same name.
+func mergeAttributes(attr html.Attributes, attrs ast.Attributes) html.Attributes {
+	for key, value := range attrs {
+		if key == "class" {
+			if existing := attr["class"]; existing != "" {
+				value = existing + " " + value
+			}
+
+			attr["class"] = value
+			continue
+		}
+
+		if _, ok := attr[key]; ok {
+			continue
+		}
+
+		attr[key] = value
+	}
+
+	return attr
+}
+
+// srcset formats candidates as the value of a srcset attribute.
+func srcset(candidates []ImageCandidate) string {
+	parts := make([]string, len(candidates))
+	for i, c := range candidates {
+		parts[i] = c.URL + " " + strconv.Itoa(c.Width) + "w"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// setDefault sets attr[key] to value unless key is already present.
+func setDefault(attr html.Attributes, key, value string) {
+	if _, ok := attr[key]; !ok {
+		attr[key] = value
+	}
+}
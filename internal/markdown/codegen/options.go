@@ -0,0 +1,133 @@
+package codegen
+
+import (
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// Options configures HTML generation.
+//
+// The zero value renders every destination as written.
+type Options struct {
+	// RewriteURL, when set, is consulted for the destination of every
+	// link and image.
+	RewriteURL URLRewriter
+
+	// HeadingOffset is added to the level of every heading, clamping the
+	// result to the range 1 through 6, so a document can be nested beneath
+	// headings rendered around it.
+	HeadingOffset int
+
+	// Figures renders an image that is alone in a paragraph as a <figure>,
+	// with the image title as its <figcaption>.
+	Figures bool
+
+	// LazyImages adds loading="lazy" and decoding="async" to every image.
+	LazyImages bool
+
+	// ImageSize, when set, is consulted for the intrinsic width and height
+	// of every image.
+	ImageSize ImageSizer
+
+	// Srcset, when set, is consulted for the responsive variants of every
+	// image. Sizes is the sizes attribute emitted alongside a srcset, and
+	// defaults to "100vw".
+	Srcset SrcsetResolver
+	Sizes  string
+}
+
+// Context carries shared state used while rendering a document.
+type Context struct {
+	Source  *source.Source
+	Options Options
+}
+
+// URLKind identifies the kind of node a destination belongs to.
+type URLKind int
+
+const (
+	_ URLKind = iota
+	URLLink
+	URLImage
+)
+
+func (k URLKind) String() string {
+	switch k {
+	case URLLink:
+		return "link"
+	case URLImage:
+		return "image"
+	default:
+		return "unknown"
+	}
+}
+
+// URLRef describes a link or image destination presented to a URLRewriter.
+//
+// URL is the destination as it would otherwise be rendered, with escapes
+// resolved and any mailto: or http:// prefix applied. Span locates the
+// node within Source. Autolink reports whether the link was written as an
+// autolink.
+type URLRef struct {
+	Kind     URLKind
+	URL      string
+	Autolink bool
+	Span     source.ByteSpan
+	Source   *source.Source
+}
+
+// URLRewrite is the result of rewriting a destination.
+//
+// URL replaces the destination, and Attributes are added to the rendered
+// element. The rewritten URL always takes precedence over an href or src
+// entry in Attributes, and attributes derived by the renderer, such as alt
+// and title, are replaced only when Attributes names them explicitly.
+type URLRewrite struct {
+	URL        string
+	Attributes html.Attributes
+}
+
+// URLRewriter rewrites a link or image destination. Returning ref.URL
+// unchanged with no attributes leaves the destination as written.
+type URLRewriter func(ref URLRef) URLRewrite
+
+// ImageSize is the intrinsic size of an image in pixels.
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// ImageSizer reports the intrinsic size of the image described by ref,
+// returning false when the size cannot be determined. ref.URL holds the
+// destination as written, before any URL rewriting.
+type ImageSizer func(ref URLRef) (ImageSize, bool)
+
+// ImageCandidate is one entry of an image's srcset: a variant URL and its
+// width in pixels.
+type ImageCandidate struct {
+	URL   string
+	Width int
+}
+
+// SrcsetResolver returns the srcset candidates for the image described by
+// ref, returning false when the image has none. ref.URL holds the
+// destination as written, before any URL rewriting, and candidate URLs are
+// emitted as returned.
+type SrcsetResolver func(ref URLRef) ([]ImageCandidate, bool)
+
+// setURL stores the destination described by ref in attr under key,
+// applying the configured rewriter, if any.
+func (ctx *Context) setURL(attr html.Attributes, key string, ref URLRef) {
+	if ctx.Options.RewriteURL == nil {
+		attr[key] = ref.URL
+		return
+	}
+
+	ref.Source = ctx.Source
+	rw := ctx.Options.RewriteURL(ref)
+
+	for k, v := range rw.Attributes {
+		attr[k] = v
+	}
+	attr[key] = rw.URL
+}
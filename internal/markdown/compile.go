@@ -5,6 +5,8 @@ import (
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/block"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/codegen"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/lower"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
@@ -14,25 +16,126 @@ type Document interface {
 	Write(io.Writer) error
 }
 
+// WikiLinkTarget is the resolved destination of a wiki link.
+type WikiLinkTarget = lower.WikiLinkTarget
+
+// WikiLinkResolver resolves the target named by a [[target]] wiki link,
+// reporting false when no such target exists.
+type WikiLinkResolver = lower.WikiLinkResolver
+
+// URLKind identifies whether a rewritten destination belongs to a link or
+// an image.
+type URLKind = codegen.URLKind
+
+const (
+	URLLink  = codegen.URLLink
+	URLImage = codegen.URLImage
+)
+
+// URLRef describes a link or image destination presented to a URLRewriter.
+type URLRef = codegen.URLRef
+
+// URLRewrite is the rewritten destination and any extra attributes for the
+// rendered element.
+type URLRewrite = codegen.URLRewrite
+
+// URLRewriter rewrites the destination of a link or image.
+type URLRewriter = codegen.URLRewriter
+
+// ImageSize is the intrinsic size of an image in pixels.
+type ImageSize = codegen.ImageSize
+
+// ImageSizer reports the intrinsic size of an image, returning false when
+// it cannot be determined.
+type ImageSizer = codegen.ImageSizer
+
+// ImageCandidate is one entry of an image's srcset.
+type ImageCandidate = codegen.ImageCandidate
+
+// SrcsetResolver returns the responsive variants of an image, returning
+// false when it has none.
+type SrcsetResolver = codegen.SrcsetResolver
+
+// Diagnostic is a source-located warning or error.
+type Diagnostic = diagnostic.Diagnostic
+
+// Options configures a single compilation.
+//
+// The zero value compiles the baseline dialect with no extensions.
+// ResolveWikiLink is consulted only when the WikiLinks extension is
+// enabled; when it is nil, every wiki link is reported as unknown.
+// RewriteURL, when set, is applied to the destination of every link, wiki
+// link, and image as it is rendered. HeadingOffset shifts every heading
+// level, and Figures, LazyImages, ImageSize, Srcset, and Sizes control how
+// images are rendered; see codegen.Options.
+//
+// CheckOutline reports the document's heading outline problems, treating
+// it as nested beneath a title of its own, to Warn. Warn receives warnings
+// only; errors are returned by CompileWith.
+type Options struct {
+	Extensions      extension.Set
+	ResolveWikiLink WikiLinkResolver
+	RewriteURL      URLRewriter
+	HeadingOffset   int
+	Figures         bool
+	LazyImages      bool
+	ImageSize       ImageSizer
+	Srcset          SrcsetResolver
+	Sizes           string
+	CheckOutline    bool
+	Warn            func(Diagnostic)
+}
+
 // Compile parses Markdown and returns a renderable document.
 func Compile(md string) (Document, error) {
+	return CompileWith(md, Options{})
+}
+
+// CompileWith parses Markdown using opts and returns a renderable document.
+func CompileWith(md string, opts Options) (Document, error) {
 	src := source.NewSource(md)
 
-	irDoc, err := block.Parse(src)
+	irDoc, err := block.Parse(src, opts.Extensions)
 	if err != nil {
 		return nil, err
 	}
 
-	astDoc, err := lower.Document(irDoc)
+	astDoc, err := lower.Document(irDoc, opts.Extensions)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, err := codegen.HTML(astDoc)
+	// unresolved wiki links fail the compilation, but only once the rest of
+	// the document has been rendered, so its warnings and rewritten URLs
+	// are all seen
+	var wikiErr error
+	if opts.Extensions.Has(extension.WikiLinks) {
+		astDoc, wikiErr = lower.ResolveWikiLinks(astDoc, opts.ResolveWikiLink)
+	}
+
+	if opts.CheckOutline && opts.Warn != nil {
+		for _, d := range lower.CheckOutline(astDoc) {
+			opts.Warn(d)
+		}
+	}
+
+	tree, err := codegen.HTMLWith(astDoc, codegen.Options{
+		RewriteURL:    opts.RewriteURL,
+		HeadingOffset: opts.HeadingOffset,
+		Figures:       opts.Figures,
+		LazyImages:    opts.LazyImages,
+		ImageSize:     opts.ImageSize,
+		Srcset:        opts.Srcset,
+		Sizes:         opts.Sizes,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if wikiErr != nil {
+		return nil, wikiErr
+	}
+
 	return tree, nil
 }
 
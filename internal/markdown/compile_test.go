@@ -4,6 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
 )
 
@@ -4476,6 +4480,1052 @@ func TestCompile_EndToEnd(t *testing.T) {
 	}
 }
 
+func TestCompileWith_Extensions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		opts     Options
+		wantHTML string
+		wantErr  error
+	}{
+		// typography
+
+		{
+			name:     "typography: quotes, dashes, and ellipses",
+			markdown: `"Hello," she said -- 'it's late'... --- goodbye`,
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: "<p>\u201cHello,\u201d she said \u2013 \u2018it\u2019s late\u2019\u2026 \u2014 goodbye</p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: elided year uses an apostrophe",
+			markdown: "the '90s",
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: "<p>the \u201990s</p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: link labels convert but destinations do not",
+			markdown: `["quoted"](/a--b "it's")`,
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: "<p><a href=\"/a--b\" title=\"it&#39;s\">\u201cquoted\u201d</a></p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: autolinks are untouched",
+			markdown: "<https://example.com/a--b...>",
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: `<p><a href="https://example.com/a--b...">https://example.com/a--b...</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: raw html is untouched",
+			markdown: `<span title="x">"a"</span>`,
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: "<p><span title=\"x\">\u201ca\u201d</span></p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: image alt text converts",
+			markdown: `!["a"](/img.png)`,
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: "<p><img alt=\"\u201ca\u201d\" src=\"/img.png\"></p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "typography: longer dash and dot runs stay literal",
+			markdown: "a ---- b ....",
+			opts:     Options{Extensions: extension.Typography},
+			wantHTML: `<p>a ---- b ....</p>`,
+			wantErr:  nil,
+		},
+
+		// math
+
+		{
+			name:     "math: inline math renders as mathml",
+			markdown: `Euler: $e^{i\pi} + 1 = 0$.`,
+			opts:     Options{Extensions: extension.Math},
+			wantHTML: "<p>Euler: <math><msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup><mo>+</mo><mn>1</mn><mo>=</mo><mn>0</mn></math>.</p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "math: display block renders as block mathml",
+			markdown: md("$$", `\frac{1}{2}`, "$$"),
+			opts:     Options{Extensions: extension.Math},
+			wantHTML: `<math display="block"><mfrac><mn>1</mn><mn>2</mn></mfrac></math>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "math: currency amounts stay literal",
+			markdown: "costs $5 and $10",
+			opts:     Options{Extensions: extension.Math},
+			wantHTML: "<p>costs $5 and $10</p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "math: payload is not inline parsed",
+			markdown: "$a*b*c$",
+			opts:     Options{Extensions: extension.Math},
+			wantHTML: "<p><math><mi>a</mi><mo>∗</mo><mi>b</mi><mo>∗</mo><mi>c</mi></math></p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "math: dollars are literal when disabled",
+			markdown: "$x$",
+			opts:     Options{},
+			wantHTML: "<p>$x$</p>",
+			wantErr:  nil,
+		},
+		{
+			name:     "math: unsupported command is a diagnostic",
+			markdown: `$\foo$`,
+			opts:     Options{Extensions: extension.Math},
+			wantHTML: "",
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `unsupported math command \foo`,
+				Span:     source.ByteSpan{Start: 1, End: 5},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+
+		// callouts
+
+		{
+			name:     "callouts: standard kind uses the default title",
+			markdown: md("> [!NOTE]", "> Useful information."),
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: `<aside class="callout callout-note"><h2 class="callout-title">Note</h2><p>Useful information.</p></aside>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: kind is case-insensitive and takes a custom title",
+			markdown: md("> [!warning] Mind *the* gap", ">", "> Stand clear."),
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: `<aside class="callout callout-warning"><h2 class="callout-title">Mind <em>the</em> gap</h2><p>Stand clear.</p></aside>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: custom kind with a title",
+			markdown: md("> [!RECIPE] Weeknight pasta", "> - boil water"),
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: `<aside class="callout callout-recipe"><h2 class="callout-title">Weeknight pasta</h2><ul><li>boil water</li></ul></aside>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: custom kind without a title stays a block quote",
+			markdown: md("> [!RECIPE]", "> boil water"),
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: "<blockquote><p>[!RECIPE] boil water</p></blockquote>",
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: marker must stand alone",
+			markdown: "> [!NOTE]: not a callout",
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: "<blockquote><p>[!NOTE]: not a callout</p></blockquote>",
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: nested in a list item",
+			markdown: md("- > [!TIP]", "  > Try it."),
+			opts:     Options{Extensions: extension.Callouts},
+			wantHTML: `<ul><li><aside class="callout callout-tip"><h2 class="callout-title">Tip</h2><p>Try it.</p></aside></li></ul>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "callouts: markers are literal when disabled",
+			markdown: "> [!NOTE]",
+			opts:     Options{},
+			wantHTML: "<blockquote><p>[!NOTE]</p></blockquote>",
+			wantErr:  nil,
+		},
+
+		// definition lists
+
+		{
+			name:     "definition lists: tight list renders inline definitions",
+			markdown: md("*Apple*", "Pomme", ": A fruit.", ": A company."),
+			opts:     Options{Extensions: extension.DefinitionLists},
+			wantHTML: "<dl><dt><em>Apple</em></dt><dt>Pomme</dt><dd>A fruit.</dd><dd>A company.</dd></dl>",
+			wantErr:  nil,
+		},
+		{
+			name:     "definition lists: loose list wraps definitions in paragraphs",
+			markdown: md("Apple", "", ": A fruit.", "", "  Crunchy.", "", "Orange", ": A citrus."),
+			opts:     Options{Extensions: extension.DefinitionLists},
+			wantHTML: "<dl><dt>Apple</dt><dd><p>A fruit.</p><p>Crunchy.</p></dd><dt>Orange</dt><dd><p>A citrus.</p></dd></dl>",
+			wantErr:  nil,
+		},
+		{
+			name:     "definition lists: typography applies to terms",
+			markdown: md("Don't", ": Stop."),
+			opts:     Options{Extensions: extension.DefinitionLists | extension.Typography},
+			wantHTML: "<dl><dt>Don’t</dt><dd>Stop.</dd></dl>",
+			wantErr:  nil,
+		},
+
+		// attributes
+
+		{
+			name:     "attributes: atx heading takes a trailing list",
+			markdown: "## Setup {#setup .wide}",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<h2 class="wide" id="setup">Setup</h2>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: setext heading takes a trailing list",
+			markdown: md("Setup {#setup}", "====="),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<h1 id="setup">Setup</h1>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: closing heading sequence precedes the list",
+			markdown: "# Title # {.x}",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<h1 class="x">Title</h1>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: fenced code info string takes a trailing list",
+			markdown: md("```go {.numbered data-start=3}", "x := 1", "```"),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<pre class="numbered" data-start="3"><code class="language-go">x := 1</code></pre>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: line following a paragraph attaches to it",
+			markdown: md("Some text", "{.lead}", "", "More text"),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p class="lead">Some text</p><p>More text</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: line following a list attaches to it",
+			markdown: md("- a", "- b", "", `{.plain title="Two items"}`),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<ul class="plain" title="Two items"><li>a</li><li>b</li></ul>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: repeated lists merge",
+			markdown: md("---", "{.a #one}", "{.b #two}"),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<hr class="a b" id="two">`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: link and image",
+			markdown: `[site](/ "Home"){rel=me} ![logo](/logo.png){width=64 .icon}`,
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p><a href="/" rel="me" title="Home">site</a> <img alt="logo" class="icon" src="/logo.png" width="64"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: link attributes do not override the destination",
+			markdown: "[a](/x){href=/y}",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p><a href="/x">a</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: bracketed span",
+			markdown: "Some [*marked* text]{.highlight} here",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p>Some <span class="highlight"><em>marked</em> text</span> here</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: malformed lists stay literal",
+			markdown: md("[a]{.}", "", "# Title{.x}"),
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p>[a]{.}</p><h1>Title{.x}</h1>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: escaped brace stays literal",
+			markdown: `[a]\{.x}`,
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: `<p>[a]{.x}</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: braces are literal when disabled",
+			markdown: md("# Title {#t}", "", "[a]{.x}"),
+			opts:     Options{},
+			wantHTML: `<h1>Title {#t}</h1><p>[a]{.x}</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "attributes: invalid attribute name is a diagnostic",
+			markdown: "# Title {1x=y}",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: "",
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `invalid attribute name "1x"`,
+				Span:     source.ByteSpan{Start: 9, End: 11},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+		{
+			name:     "attributes: a second id is a diagnostic",
+			markdown: "# Title {#intro #summary}",
+			opts:     Options{Extensions: extension.Attributes},
+			wantHTML: "",
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `duplicate id "summary": already set to "intro"`,
+				Span:     source.ByteSpan{Start: 16, End: 24},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+
+		// autolinks
+
+		{
+			name:     "autolinks: bare url",
+			markdown: "See https://example.com/docs for details.",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>See <a href="https://example.com/docs">https://example.com/docs</a> for details.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: www address gains a scheme",
+			markdown: "Visit www.commonmark.org/help.",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>Visit <a href="http://www.commonmark.org/help">www.commonmark.org/help</a>.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: email address",
+			markdown: "Write to foo.bar+baz@example.co.uk.",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>Write to <a href="mailto:foo.bar+baz@example.co.uk">foo.bar+baz@example.co.uk</a>.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: balanced parentheses are kept",
+			markdown: "(see https://en.wikipedia.org/wiki/Go_(language))",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>(see <a href="https://en.wikipedia.org/wiki/Go_(language)">https://en.wikipedia.org/wiki/Go_(language)</a>)</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: trailing punctuation and entities are excluded",
+			markdown: "www.google.com/search?q=commonmark&hl; and https://x.org/a_b_, ok?",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><a href="http://www.google.com/search?q=commonmark">www.google.com/search?q=commonmark</a>&amp;hl; and <a href="https://x.org/a_b">https://x.org/a_b</a>_, ok?</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: url inside emphasis",
+			markdown: "*https://example.com*",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><em><a href="https://example.com">https://example.com</a></em></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: emphasis delimiters inside a url are part of it",
+			markdown: "https://x.com/a*b*c and https://x.com/a_b_c",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><a href="https://x.com/a*b*c">https://x.com/a*b*c</a> and <a href="https://x.com/a_b_c">https://x.com/a_b_c</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: emphasis around a url with delimiters inside",
+			markdown: "*https://x.com/a*b*c*",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><em><a href="https://x.com/a*b*c">https://x.com/a*b*c</a></em></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: tildes inside a url are part of it",
+			markdown: "https://x.com/a~b~c",
+			opts:     Options{Extensions: extension.Autolinks | extension.Subscript | extension.Strikethrough},
+			wantHTML: `<p><a href="https://x.com/a~b~c">https://x.com/a~b~c</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: equals signs inside a url are part of it",
+			markdown: "https://x.com/a==b==c",
+			opts:     Options{Extensions: extension.Autolinks | extension.Highlight},
+			wantHTML: `<p><a href="https://x.com/a==b==c">https://x.com/a==b==c</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: plus signs inside a url are part of it",
+			markdown: "https://x.com/a++b++",
+			opts:     Options{Extensions: extension.Autolinks | extension.Insert},
+			wantHTML: `<p><a href="https://x.com/a++b++">https://x.com/a++b++</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: carets inside a url are part of it",
+			markdown: "https://x.com/^2^",
+			opts:     Options{Extensions: extension.Autolinks | extension.Superscript},
+			wantHTML: `<p><a href="https://x.com/^2^">https://x.com/^2^</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: dollar signs inside a url are part of it",
+			markdown: "https://x.com/$a$",
+			opts:     Options{Extensions: extension.Autolinks | extension.Math},
+			wantHTML: `<p><a href="https://x.com/$a$">https://x.com/$a$</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: wiki link brackets inside a url are part of it",
+			markdown: "https://x.com/[[a]]",
+			opts:     Options{Extensions: extension.Autolinks | extension.WikiLinks},
+			wantHTML: `<p><a href="https://x.com/[[a]]">https://x.com/[[a]]</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: code spans and existing links are untouched",
+			markdown: "`https://a.com` [https://b.com](/c) <https://d.com>",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><code>https://a.com</code> <a href="/c">https://b.com</a> <a href="https://d.com">https://d.com</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: invalid domains and mid-word prefixes are text",
+			markdown: "http://localhost awww.example.com www.a_b.c_d a@b",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>http://localhost awww.example.com www.a_b.c_d a@b</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: typography skips link text",
+			markdown: "Don't break https://example.com/a--b",
+			opts:     Options{Extensions: extension.Autolinks | extension.Typography},
+			wantHTML: `<p>Don’t break <a href="https://example.com/a--b">https://example.com/a--b</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: entity references in a bare url are decoded in the href",
+			markdown: "See https://example.com/?q=1&amp;x=&#50;&#x33; now",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p>See <a href="https://example.com/?q=1&amp;x=23">https://example.com/?q=1&amp;amp;x=&amp;#50;&amp;#x33;</a> now</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: entity references in an angle autolink are decoded in the href",
+			markdown: "<https://example.com/?q=1&amp;x=2>",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><a href="https://example.com/?q=1&amp;x=2">https://example.com/?q=1&amp;amp;x=2</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: ampersands that do not end a reference are kept",
+			markdown: "https://example.com/?b=&nope;&a=1&copy=2",
+			opts:     Options{Extensions: extension.Autolinks},
+			wantHTML: `<p><a href="https://example.com/?b=&amp;nope;&amp;a=1&amp;copy=2">https://example.com/?b=&amp;nope;&amp;a=1&amp;copy=2</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "autolinks: bare urls are text when disabled",
+			markdown: "https://example.com",
+			opts:     Options{},
+			wantHTML: `<p>https://example.com</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: superscript and subscript",
+			markdown: "E = mc^2^ and H~2~O",
+			opts:     Options{Extensions: extension.Superscript | extension.Subscript},
+			wantHTML: `<p>E = mc<sup>2</sup> and H<sub>2</sub>O</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: strikethrough coexists with subscript",
+			markdown: "~~old~~ and ~new~",
+			opts:     Options{Extensions: extension.Subscript | extension.Strikethrough},
+			wantHTML: `<p><del>old</del> and <sub>new</sub></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: highlight and insert nest",
+			markdown: "==a ++b++ *c*==",
+			opts:     Options{Extensions: extension.Highlight | extension.Insert},
+			wantHTML: `<p><mark>a <ins>b</ins> <em>c</em></mark></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: unmatched and odd runs are text",
+			markdown: "C++ and C++, a = b, x^y, ~~~no~~~",
+			opts:     Options{Extensions: extension.Superscript | extension.Subscript | extension.Strikethrough | extension.Highlight | extension.Insert},
+			wantHTML: `<p>C++ and C++, a = b, x^y, ~~~no~~~</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: code spans are untouched",
+			markdown: "`==a==` ==b==",
+			opts:     Options{Extensions: extension.Highlight},
+			wantHTML: `<p><code>==a==</code> <mark>b</mark></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "styled inlines: delimiters are text when disabled",
+			markdown: "^a^ ~b~ ~~c~~ ==d== ++e++",
+			opts:     Options{},
+			wantHTML: `<p>^a^ ~b~ ~~c~~ ==d== ++e++</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: shortcodes resolve to unicode",
+			markdown: "Shipped :rocket: :tada::tada:",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p>Shipped 🚀 🎉🎉</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: underscores and punctuation in names",
+			markdown: "*:white_check_mark:* (:+1:)",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p><em>✅</em> (👍)</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: labels wrap in an image span",
+			markdown: ":tada:",
+			opts:     Options{Extensions: extension.Emoji | extension.EmojiLabels},
+			wantHTML: `<p><span aria-label="party popper" role="img">🎉</span></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: unknown shortcodes stay literal",
+			markdown: ":not_an_emoji: :Rocket:",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p>:not_an_emoji: :Rocket:</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: never fires inside words or times",
+			markdown: "At 10:30:00 and a:100: or :100:s",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p>At 10:30:00 and a:100: or :100:s</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: code spans and urls are untouched",
+			markdown: "`:tada:` https://example.com/:tada: www.example.com/a?b=:tada: <https://example.com/:tada:> [x](/:tada:)",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p><code>:tada:</code> https://example.com/:tada: www.example.com/a?b=:tada: <a href="https://example.com/:tada:">https://example.com/:tada:</a> <a href="/:tada:">x</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: autolinked urls are untouched",
+			markdown: "https://example.com/:tada:/a :tada:",
+			opts:     Options{Extensions: extension.Emoji | extension.Autolinks},
+			wantHTML: `<p><a href="https://example.com/:tada:/a">https://example.com/:tada:/a</a> 🎉</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: image alt text uses the emoji",
+			markdown: "![:rocket: launch](/a.png)",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p><img alt="🚀 launch" src="/a.png"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: escaped colon stays literal",
+			markdown: "\\:tada:",
+			opts:     Options{Extensions: extension.Emoji},
+			wantHTML: `<p>:tada:</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "emoji: shortcodes are text when disabled",
+			markdown: ":tada:",
+			opts:     Options{},
+			wantHTML: `<p>:tada:</p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: whole-word occurrences are wrapped",
+			markdown: md(
+				"The HTML spec, HTML5, and XHTML differ from HTML.",
+				"",
+				"*[HTML]: HyperText Markup Language",
+			),
+			opts:     Options{Extensions: extension.Abbreviations},
+			wantHTML: `<p>The <abbr title="HyperText Markup Language">HTML</abbr> spec, HTML5, and XHTML differ from <abbr title="HyperText Markup Language">HTML</abbr>.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: longest term wins and matching is case-sensitive",
+			markdown: md(
+				"*[Web API]: Web application programming interface",
+				"*[API]: Application programming interface",
+				"",
+				"A Web API is an API, not an api.",
+			),
+			opts:     Options{Extensions: extension.Abbreviations},
+			wantHTML: `<p>A <abbr title="Web application programming interface">Web API</abbr> is an <abbr title="Application programming interface">API</abbr>, not an api.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: code, links, and raw html are skipped",
+			markdown: md(
+				"*[GC]: garbage collector",
+				"",
+				"`GC` [GC](/gc) <b title=\"GC\">GC</b> **GC**",
+			),
+			opts:     Options{Extensions: extension.Abbreviations},
+			wantHTML: `<p><code>GC</code> <a href="/gc">GC</a> <b title="GC"><abbr title="garbage collector">GC</abbr></b> <strong><abbr title="garbage collector">GC</abbr></strong></p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: terms inside underscore emphasis are wrapped",
+			markdown: md(
+				"*[HTML]: HyperText Markup Language",
+				"",
+				"_HTML_ and __HTML__ and *HTML*",
+			),
+			opts:     Options{Extensions: extension.Abbreviations},
+			wantHTML: `<p><em><abbr title="HyperText Markup Language">HTML</abbr></em> and <strong><abbr title="HyperText Markup Language">HTML</abbr></strong> and <em><abbr title="HyperText Markup Language">HTML</abbr></em></p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: first definition wins",
+			markdown: md(
+				"*[GC]: garbage collector",
+				"*[GC]: Google Chrome",
+				"",
+				"GC",
+			),
+			opts:     Options{Extensions: extension.Abbreviations},
+			wantHTML: `<p><abbr title="garbage collector">GC</abbr></p>`,
+			wantErr:  nil,
+		},
+		{
+			name: "abbreviations: definitions are text when disabled",
+			markdown: md(
+				"*[GC]: garbage collector",
+				"",
+				"GC",
+			),
+			opts:     Options{},
+			wantHTML: `<p>*[GC]: garbage collector</p><p>GC</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "wiki links: label defaults to the target title",
+			markdown: "See [[hello-world]].",
+			opts:     Options{Extensions: extension.WikiLinks, ResolveWikiLink: resolveTestWikiLink},
+			wantHTML: `<p>See <a href="/blog/hello-world/">Hello, World</a>.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "wiki links: label and fragment",
+			markdown: "[[hello-world#setup|the setup]] and *[[hello-world]]*",
+			opts:     Options{Extensions: extension.WikiLinks, ResolveWikiLink: resolveTestWikiLink},
+			wantHTML: `<p><a href="/blog/hello-world/#setup">the setup</a> and <em><a href="/blog/hello-world/">Hello, World</a></em></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "wiki links: code spans are untouched",
+			markdown: "`[[missing]]`",
+			opts:     Options{Extensions: extension.WikiLinks, ResolveWikiLink: resolveTestWikiLink},
+			wantHTML: `<p><code>[[missing]]</code></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "wiki links: unknown target is a diagnostic",
+			markdown: "a\n\n- [[missing]]",
+			opts:     Options{Extensions: extension.WikiLinks, ResolveWikiLink: resolveTestWikiLink},
+			wantHTML: "",
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `unknown wiki link target "missing"`,
+				Span:     source.ByteSpan{Start: 7, End: 14},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+		{
+			name:     "wiki links: nil resolver knows no targets",
+			markdown: "[[hello-world]]",
+			opts:     Options{Extensions: extension.WikiLinks},
+			wantHTML: "",
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `unknown wiki link target "hello-world"`,
+				Span:     source.ByteSpan{Start: 2, End: 13},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+		{
+			name:     "wiki links: brackets are text when disabled",
+			markdown: "[[hello-world]]",
+			opts:     Options{},
+			wantHTML: `<p>[[hello-world]]</p>`,
+			wantErr:  nil,
+		},
+
+		// url rewriting
+
+		{
+			name:     "url rewriting: relative image source is rewritten",
+			markdown: `![a cat](media/cat.png "Cat")`,
+			opts:     Options{RewriteURL: rewriteTestURL},
+			wantHTML: `<p><img alt="a cat" src="/blog/post/media/cat.png" title="Cat"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: external link gains attributes",
+			markdown: `[site](https://example.com)`,
+			opts:     Options{RewriteURL: rewriteTestURL},
+			wantHTML: `<p><a href="https://example.com" rel="noopener" target="_blank">site</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: absolute path is left unchanged",
+			markdown: `[home](/about/)`,
+			opts:     Options{RewriteURL: rewriteTestURL},
+			wantHTML: `<p><a href="/about/">home</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: extended autolink is rewritten",
+			markdown: `see www.example.com`,
+			opts:     Options{Extensions: extension.Autolinks, RewriteURL: rewriteTestURL},
+			wantHTML: `<p>see <a href="http://www.example.com" rel="noopener" target="_blank">www.example.com</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: reference link destination is rewritten",
+			markdown: md("[doc][d]", "", "[d]: files/doc.pdf"),
+			opts:     Options{RewriteURL: rewriteTestURL},
+			wantHTML: `<p><a href="/blog/post/files/doc.pdf">doc</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: wiki link destination is rewritten",
+			markdown: "[[hello-world]]",
+			opts:     Options{Extensions: extension.WikiLinks, ResolveWikiLink: resolveTestWikiLink, RewriteURL: rewriteTestURL},
+			wantHTML: `<p><a href="/blog/hello-world/">Hello, World</a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "url rewriting: rewritten url wins over explicit attributes",
+			markdown: `[site](https://example.com){href=/elsewhere rel=nofollow .ext}`,
+			opts:     Options{Extensions: extension.Attributes, RewriteURL: rewriteTestURL},
+			wantHTML: `<p><a class="ext" href="https://example.com" rel="noopener" target="_blank">site</a></p>`,
+			wantErr:  nil,
+		},
+
+		// images
+
+		{
+			name:     "images: lone image becomes a figure",
+			markdown: `![a cat](media/cat.png "A *cat*")`,
+			opts:     Options{Figures: true},
+			wantHTML: `<figure><img alt="a cat" src="media/cat.png"><figcaption>A *cat*</figcaption></figure>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: figure without title has no caption",
+			markdown: `![a cat](media/cat.png)`,
+			opts:     Options{Figures: true},
+			wantHTML: `<figure><img alt="a cat" src="media/cat.png"></figure>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: paragraph attributes move to the figure",
+			markdown: md(`![a cat](media/cat.png "Cat")`, "{.wide}"),
+			opts:     Options{Extensions: extension.Attributes, Figures: true},
+			wantHTML: `<figure class="wide"><img alt="a cat" src="media/cat.png"><figcaption>Cat</figcaption></figure>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: linked image is not a figure",
+			markdown: `[![a cat](media/cat.png)](/cats/)`,
+			opts:     Options{Figures: true},
+			wantHTML: `<p><a href="/cats/"><img alt="a cat" src="media/cat.png"></a></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: lazy loading hints are added",
+			markdown: `![a](a.png) ![b](b.png){loading=eager}`,
+			opts:     Options{Extensions: extension.Attributes, LazyImages: true},
+			wantHTML: `<p><img alt="a" decoding="async" loading="lazy" src="a.png"> <img alt="b" decoding="async" loading="eager" src="b.png"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: intrinsic size is added",
+			markdown: `![a](a.png)`,
+			opts:     Options{ImageSize: sizeTestImage},
+			wantHTML: `<p><img alt="a" height="480" src="a.png" width="640"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: explicit dimensions are kept",
+			markdown: `![a](a.png){width=320}`,
+			opts:     Options{Extensions: extension.Attributes, ImageSize: sizeTestImage},
+			wantHTML: `<p><img alt="a" src="a.png" width="320"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: size is read before url rewriting",
+			markdown: `![a](a.png)`,
+			opts:     Options{RewriteURL: rewriteTestURL, ImageSize: sizeTestImage},
+			wantHTML: `<p><img alt="a" height="480" src="/blog/post/a.png" width="640"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: srcset candidates are added with default sizes",
+			markdown: `![a](a.png)`,
+			opts:     Options{Srcset: srcsetTestImage},
+			wantHTML: `<p><img alt="a" sizes="100vw" src="a.png" srcset="a-480.png 480w, a.png 960w"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: srcset uses configured sizes",
+			markdown: `![a](a.png) ![b](b.png)`,
+			opts:     Options{Srcset: srcsetTestImage, Sizes: "(max-width: 40rem) 100vw, 40rem"},
+			wantHTML: `<p><img alt="a" sizes="(max-width: 40rem) 100vw, 40rem" src="a.png" srcset="a-480.png 480w, a.png 960w"> <img alt="b" src="b.png"></p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "images: explicit srcset is kept",
+			markdown: `![a](a.png){srcset="x.png 1x"}`,
+			opts:     Options{Extensions: extension.Attributes, Srcset: srcsetTestImage},
+			wantHTML: `<p><img alt="a" src="a.png" srcset="x.png 1x"></p>`,
+			wantErr:  nil,
+		},
+
+		// hard breaks and line blocks
+
+		{
+			name:     "hard breaks: soft breaks render as br",
+			markdown: md("Roses are red,", "violets are blue."),
+			opts:     Options{Extensions: extension.HardBreaks},
+			wantHTML: `<p>Roses are red,<br>violets are blue.</p>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "hard breaks: headings and code are unaffected",
+			markdown: md("Title", "=====", "", "```", "a", "b", "```"),
+			opts:     Options{Extensions: extension.HardBreaks},
+			wantHTML: "<h1>Title</h1><pre><code>a\nb</code></pre>",
+			wantErr:  nil,
+		},
+		{
+			name: "line block: lines and indentation are preserved",
+			markdown: md(
+				"| The limerick packs laughs *anatomical*",
+				"|     In space that is quite economical.",
+				"|",
+				"| But the good ones",
+				"  I've seen",
+			),
+			opts:     Options{Extensions: extension.LineBlocks},
+			wantHTML: "<div class=\"line-block\">The limerick packs laughs <em>anatomical</em><br>\u00a0\u00a0\u00a0\u00a0In space that is quite economical.<br><br>But the good ones I&#39;ve seen</div>",
+			wantErr:  nil,
+		},
+		{
+			name:     "line block: attributes merge with the line-block class",
+			markdown: md("| a", "| b", "{.poem #verse}"),
+			opts:     Options{Extensions: extension.LineBlocks | extension.Attributes},
+			wantHTML: `<div class="line-block poem" id="verse">a<br>b</div>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "line block: pipes are text when disabled",
+			markdown: "| a",
+			opts:     Options{},
+			wantHTML: `<p>| a</p>`,
+			wantErr:  nil,
+		},
+
+		// heading offset
+
+		{
+			name:     "heading offset: levels shift down",
+			markdown: md("# a", "", "## b"),
+			opts:     Options{HeadingOffset: 1},
+			wantHTML: `<h2>a</h2><h3>b</h3>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "heading offset: levels clamp at six",
+			markdown: md("##### a", "", "###### b"),
+			opts:     Options{HeadingOffset: 2},
+			wantHTML: `<h6>a</h6><h6>b</h6>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "heading offset: negative offsets clamp at one",
+			markdown: md("# a", "", "### b"),
+			opts:     Options{HeadingOffset: -1},
+			wantHTML: `<h1>a</h1><h2>b</h2>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "heading offset: setext headings shift",
+			markdown: md("a", "===", "", "b", "---"),
+			opts:     Options{HeadingOffset: 1},
+			wantHTML: `<h2>a</h2><h3>b</h3>`,
+			wantErr:  nil,
+		},
+		{
+			name:     "heading offset: callout titles sit below the heading before them",
+			markdown: md("> [!NOTE]", "> a", "", "## b", "", "> [!TIP]", "> c"),
+			opts:     Options{Extensions: extension.Callouts, HeadingOffset: 1},
+			wantHTML: `<aside class="callout callout-note"><h3 class="callout-title">Note</h3><p>a</p></aside><h3>b</h3><aside class="callout callout-tip"><h4 class="callout-title">Tip</h4><p>c</p></aside>`,
+			wantErr:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+
+			doc, err := CompileWith(tc.markdown, tc.opts)
+			if err == nil {
+				got, err = html.Render(doc)
+			}
+
+			assert.Equal(t, got, tc.wantHTML)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCompileWith_CheckOutline(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		exts     extension.Set
+		want     []Diagnostic
+	}{
+		{
+			name:     "sections starting at level 2 are clean",
+			markdown: md("## a", "", "### b", "", "## c", "", "### d"),
+			want:     nil,
+		},
+		{
+			name:     "level-1 heading competes with the title",
+			markdown: md("intro", "", "# a"),
+			want: []Diagnostic{
+				{
+					Message:  "level-1 heading competes with the document title",
+					Span:     source.ByteSpan{Start: 7, End: 10},
+					Severity: diagnostic.SeverityWarning,
+				},
+			},
+		},
+		{
+			name:     "skipped levels are reported",
+			markdown: md("### a", "", "## b", "", "#### c"),
+			want: []Diagnostic{
+				{
+					Message:  "heading level 3 skips level 2",
+					Span:     source.ByteSpan{Start: 0, End: 5},
+					Severity: diagnostic.SeverityWarning,
+				},
+				{
+					Message:  "heading level 4 skips level 3",
+					Span:     source.ByteSpan{Start: 13, End: 19},
+					Severity: diagnostic.SeverityWarning,
+				},
+			},
+		},
+		{
+			name:     "headings in block quotes are not checked",
+			markdown: md("## a", "", "> # quoted"),
+			want:     nil,
+		},
+		{
+			name:     "callout body headings sit below the callout title",
+			markdown: md("## a", "", "> [!NOTE]", "> #### b", "", "> [!TIP]", "> #### c"),
+			exts:     extension.Callouts,
+			want:     nil,
+		},
+		{
+			name:     "callout body heading above its title is reported",
+			markdown: md("> [!NOTE]", "> # x", "> ##### y"),
+			exts:     extension.Callouts,
+			want: []Diagnostic{
+				{
+					Message:  "heading level 1 is not below its callout title at level 2",
+					Span:     source.ByteSpan{Start: 12, End: 15},
+					Severity: diagnostic.SeverityWarning,
+				},
+				{
+					Message:  "heading level 5 skips level 3",
+					Span:     source.ByteSpan{Start: 18, End: 25},
+					Severity: diagnostic.SeverityWarning,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []Diagnostic
+
+			_, err := CompileWith(tc.markdown, Options{
+				Extensions:   tc.exts,
+				CheckOutline: true,
+				Warn: func(d Diagnostic) {
+					got = append(got, d)
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
 func md(xs ...string) string {
 	return strings.Join(xs, "\n")
 }
+
+func resolveTestWikiLink(target string) (WikiLinkTarget, bool) {
+	if target != "hello-world" {
+		return WikiLinkTarget{}, false
+	}
+
+	return WikiLinkTarget{URL: "/blog/hello-world/", Title: "Hello, World"}, true
+}
+
+// rewriteTestURL resolves relative destinations under /blog/post/ and opens
+// external links in a new tab.
+func rewriteTestURL(ref URLRef) URLRewrite {
+	switch {
+	case strings.HasPrefix(ref.URL, "http://"), strings.HasPrefix(ref.URL, "https://"):
+		return URLRewrite{
+			URL: ref.URL,
+			Attributes: html.Attributes{
+				"rel":    "noopener",
+				"target": "_blank",
+			},
+		}
+	case strings.HasPrefix(ref.URL, "/"):
+		return URLRewrite{URL: ref.URL}
+	default:
+		return URLRewrite{URL: "/blog/post/" + ref.URL}
+	}
+}
+
+// sizeTestImage reports a fixed size for a.png and no size otherwise.
+func sizeTestImage(ref URLRef) (ImageSize, bool) {
+	if ref.URL != "a.png" {
+		return ImageSize{}, false
+	}
+
+	return ImageSize{Width: 640, Height: 480}, true
+}
+
+// srcsetTestImage reports two variants of a.png and none otherwise.
+func srcsetTestImage(ref URLRef) ([]ImageCandidate, bool) {
+	if ref.URL != "a.png" {
+		return nil, false
+	}
+
+	return []ImageCandidate{
+		{URL: "a-480.png", Width: 480},
+		{URL: "a.png", Width: 960},
+	}, true
+}
@@ -0,0 +1,8 @@
+// Package emoji resolves emoji shortcodes such as "tada" to their Unicode
+// characters.
+//
+// The table is embedded from emoji.json, which uses the schema of GitHub's
+// gemoji database: each entry lists its emoji, a description, and one or
+// more aliases. A larger or updated gemoji export may be dropped in place
+// of the bundled file without code changes.
+package emoji
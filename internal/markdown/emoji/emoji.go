@@ -0,0 +1,50 @@
+package emoji
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+//go:embed emoji.json
+var tableJSON []byte
+
+// Emoji is a single entry in the shortcode table.
+type Emoji struct {
+	// Emoji is the Unicode character sequence.
+	Emoji string `json:"emoji"`
+
+	// Description is the emoji's name in words, suitable as an accessible
+	// label.
+	Description string `json:"description"`
+
+	// Aliases are the shortcode names that resolve to the emoji.
+	Aliases []string `json:"aliases"`
+}
+
+var table = sync.OnceValue(func() map[string]Emoji {
+	var entries []Emoji
+	if err := json.Unmarshal(tableJSON, &entries); err != nil {
+		panic("emoji: invalid embedded table: " + err.Error())
+	}
+
+	byAlias := make(map[string]Emoji, len(entries))
+	for _, e := range entries {
+		for _, alias := range e.Aliases {
+			byAlias[alias] = e
+		}
+	}
+
+	return byAlias
+})
+
+// Lookup returns the emoji whose alias is name, without surrounding colons.
+func Lookup(name string) (Emoji, bool) {
+	e, ok := table()[name]
+	return e, ok
+}
+
+// IsNameByte reports whether b may appear in a shortcode name.
+func IsNameByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '_' || b == '+' || b == '-'
+}
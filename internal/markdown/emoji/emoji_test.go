@@ -0,0 +1,66 @@
+package emoji
+
+import (
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		wantEmoji string
+		wantDesc  string
+		wantOK    bool
+	}{
+		{
+			name:      "known alias",
+			input:     "rocket",
+			wantEmoji: "🚀",
+			wantDesc:  "rocket",
+			wantOK:    true,
+		},
+		{
+			name:      "secondary alias",
+			input:     "thumbsup",
+			wantEmoji: "👍",
+			wantDesc:  "thumbs up sign",
+			wantOK:    true,
+		},
+		{
+			name:      "alias with punctuation",
+			input:     "+1",
+			wantEmoji: "👍",
+			wantDesc:  "thumbs up sign",
+			wantOK:    true,
+		},
+		{
+			name:      "variation selector is kept",
+			input:     "heart",
+			wantEmoji: "❤️",
+			wantDesc:  "red heart",
+			wantOK:    true,
+		},
+		{
+			name:   "unknown alias",
+			input:  "not_an_emoji",
+			wantOK: false,
+		},
+		{
+			name:   "aliases are case-sensitive",
+			input:  "Rocket",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := Lookup(tc.input)
+
+			assert.Equal(t, ok, tc.wantOK)
+			assert.Equal(t, got.Emoji, tc.wantEmoji)
+			assert.Equal(t, got.Description, tc.wantDesc)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package extension defines the optional syntax and rendering extensions
+// understood by the Markdown compiler.
+//
+// Extensions are opt-in. The zero Set compiles the baseline CommonMark
+// subset described in the package README, and each enabled extension
+// widens the rule vocabulary of the stages that implement it.
+package extension
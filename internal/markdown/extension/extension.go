@@ -0,0 +1,87 @@
+package extension
+
+// Set is a bit set of enabled extensions.
+type Set uint32
+
+const (
+	// Typography converts straight quotes, dash runs, and ellipses in text
+	// into their typographic equivalents.
+	Typography Set = 1 << iota
+
+	// Math recognizes $inline$ and $$display$$ TeX math and renders it as
+	// MathML.
+	Math
+
+	// Callouts turns block quotes that open with a [!KIND] marker into
+	// callout blocks.
+	Callouts
+
+	// DefinitionLists recognizes term lines followed by ": definition"
+	// lines and renders them as <dl> lists.
+	DefinitionLists
+
+	// Attributes recognizes {#id .class key=value} attribute lists on
+	// blocks, links, images, and bracketed spans.
+	Attributes
+
+	// Autolinks recognizes bare http:// and https:// URLs, www. addresses,
+	// and email addresses in text as links, following the GFM extended
+	// autolink rules.
+	Autolinks
+
+	// Superscript recognizes ^text^ as superscript.
+	Superscript
+
+	// Subscript recognizes ~text~ as subscript.
+	Subscript
+
+	// Strikethrough recognizes ~~text~~ as struck-through text.
+	Strikethrough
+
+	// Highlight recognizes ==text== as highlighted text.
+	Highlight
+
+	// Insert recognizes ++text++ as inserted text.
+	Insert
+
+	// Emoji resolves :shortcode: names in text, such as :tada:, to their
+	// Unicode emoji.
+	Emoji
+
+	// EmojiLabels wraps each resolved emoji in a <span role="img"> carrying
+	// the emoji's description as its aria-label. It has no effect unless
+	// Emoji is also enabled.
+	EmojiLabels
+
+	// Abbreviations recognizes *[TERM]: expansion definitions and wraps
+	// whole-word occurrences of each term in text with <abbr>.
+	Abbreviations
+
+	// WikiLinks recognizes [[target]], [[target|label]], and
+	// [[target#fragment]] internal links, resolved by a caller-supplied
+	// resolver.
+	WikiLinks
+
+	// HardBreaks renders every soft line break within a paragraph as a
+	// hard break, so the line structure of the source is kept.
+	HardBreaks
+
+	// LineBlocks recognizes runs of lines prefixed with "| " as line
+	// blocks, which keep their line breaks and leading indentation.
+	LineBlocks
+)
+
+// Has reports whether every extension in x is enabled in s.
+func (s Set) Has(x Set) bool {
+	return s&x == x
+}
+
+// With returns s with the extensions in x enabled.
+func (s Set) With(x Set) Set {
+	return s | x
+}
+
+// Without returns s with the extensions in x disabled.
+func (s Set) Without(x Set) Set {
+	return s &^ x
+}
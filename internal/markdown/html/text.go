@@ -0,0 +1,342 @@
+package html
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// blockTags lists the elements whose boundaries separate words, so the
+// text of adjacent paragraphs or list items never runs together.
+var blockTags = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"br":         true,
+	"dd":         true,
+	"details":    true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"figcaption": true,
+	"figure":     true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"hr":         true,
+	"li":         true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"summary":    true,
+	"table":      true,
+	"td":         true,
+	"th":         true,
+	"tr":         true,
+	"ul":         true,
+}
+
+// PlainText returns the text content of n with runs of whitespace collapsed
+// to single spaces. Block-level element boundaries separate words, and raw
+// HTML contributes no text.
+func PlainText(n Node) string {
+	var sb strings.Builder
+	writeText(&sb, n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func writeText(sb *strings.Builder, n Node) {
+	switch v := n.(type) {
+	case Text:
+		sb.WriteString(v.Value)
+
+	case Fragment:
+		for _, c := range v.Children {
+			writeText(sb, c)
+		}
+
+	case Element:
+		if blockTags[v.Tag] {
+			sb.WriteByte(' ')
+		}
+		for _, c := range v.Children {
+			writeText(sb, c)
+		}
+		if blockTags[v.Tag] {
+			sb.WriteByte(' ')
+		}
+
+	case VoidElement:
+		if blockTags[v.Tag] {
+			sb.WriteByte(' ')
+		}
+	}
+}
+
+// CountWords returns the number of words in the text content of n, counted
+// as PlainText separates them.
+func CountWords(n Node) int {
+	return len(strings.Fields(PlainText(n)))
+}
+
+// TruncateWords returns a copy of n holding only its first limit words of
+// text, reporting whether any words were removed.
+//
+// The copy is cut just before the first word past the limit. Elements left
+// open at the cut are closed, and elements emptied by it are dropped, so
+// the result is well-formed. A cut within a run of text is marked with an
+// ellipsis.
+func TruncateWords(n Node, limit int) (Node, bool) {
+	t := truncator{limit: limit}
+	out, ok := t.node(n)
+	if !t.cut {
+		return n, false
+	}
+	if !ok {
+		return Fragment{}, true
+	}
+
+	return out, true
+}
+
+type truncator struct {
+	limit  int
+	words  int
+	inWord bool
+	cut    bool
+}
+
+// node returns the part of n that precedes the cut, reporting false when
+// nothing of n remains.
+func (t *truncator) node(n Node) (Node, bool) {
+	if t.cut {
+		return nil, false
+	}
+
+	switch v := n.(type) {
+	case Text:
+		return t.text(v)
+
+	case Fragment:
+		children, ok := t.children(v.Children)
+		if !ok {
+			return nil, false
+		}
+		v.Children = children
+		return v, true
+
+	case Element:
+		t.boundary(v.Tag)
+		children, ok := t.children(v.Children)
+		if !ok {
+			return nil, false
+		}
+		t.boundary(v.Tag)
+		v.Children = children
+		return v, true
+
+	case VoidElement:
+		t.boundary(v.Tag)
+		return v, true
+
+	default:
+		return n, true
+	}
+}
+
+// children returns the children kept before the cut. A non-empty list cut
+// down to nothing is reported as empty, so its parent is dropped.
+func (t *truncator) children(nodes []Node) ([]Node, bool) {
+	out := make([]Node, 0, len(nodes))
+	for _, c := range nodes {
+		kept, ok := t.node(c)
+		if ok {
+			out = append(out, kept)
+		}
+		if t.cut {
+			out = dropUnclosedRaw(out)
+			break
+		}
+	}
+
+	if len(nodes) > 0 && len(out) == 0 && t.cut {
+		return nil, false
+	}
+
+	return out, true
+}
+
+func (t *truncator) text(v Text) (Node, bool) {
+	for i, r := range v.Value {
+		if unicode.IsSpace(r) {
+			t.inWord = false
+			continue
+		}
+		if t.inWord {
+			continue
+		}
+
+		if t.words == t.limit {
+			t.cut = true
+
+			kept := strings.TrimRightFunc(v.Value[:i], unicode.IsSpace)
+			if kept == "" {
+				return nil, false
+			}
+			return Text{Value: kept + "…"}, true
+		}
+
+		t.words++
+		t.inWord = true
+	}
+
+	return v, true
+}
+
+func (t *truncator) boundary(tag string) {
+	if blockTags[tag] {
+		t.inWord = false
+	}
+}
+
+// CutBefore returns a copy of n holding only the content that precedes the
+// first node, in document order, for which match reports true, and reports
+// whether such a node was found. Elements left open at the cut are closed,
+// and elements emptied by it are dropped.
+func CutBefore(n Node, match func(Node) bool) (Node, bool) {
+	out, ok, found := cutBefore(n, match)
+	if !found {
+		return n, false
+	}
+	if !ok {
+		return Fragment{}, true
+	}
+
+	return out, true
+}
+
+// cutBefore returns the part of n preceding the first match, reporting
+// whether anything of n remains and whether a match was found.
+func cutBefore(n Node, match func(Node) bool) (Node, bool, bool) {
+	if match(n) {
+		return nil, false, true
+	}
+
+	switch v := n.(type) {
+	case Fragment:
+		children, found := cutChildren(v.Children, match)
+		if found && len(children) == 0 {
+			return nil, false, true
+		}
+		v.Children = children
+		return v, true, found
+
+	case Element:
+		children, found := cutChildren(v.Children, match)
+		if found && len(children) == 0 {
+			return nil, false, true
+		}
+		v.Children = children
+		return v, true, found
+
+	default:
+		return n, true, false
+	}
+}
+
+func cutChildren(nodes []Node, match func(Node) bool) ([]Node, bool) {
+	out := make([]Node, 0, len(nodes))
+	for _, c := range nodes {
+		kept, ok, found := cutBefore(c, match)
+		if ok {
+			out = append(out, kept)
+		}
+		if found {
+			return dropUnclosedRaw(out), true
+		}
+	}
+
+	return out, false
+}
+
+// rawOpenTag and rawCloseTag match a raw HTML node holding a single start
+// or end tag, capturing the tag name.
+var (
+	rawOpenTag  = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9-]*)(?:\s[^>]*)?>$`)
+	rawCloseTag = regexp.MustCompile(`^</([A-Za-z][A-Za-z0-9-]*)\s*>$`)
+)
+
+// rawVoidTags lists the elements a raw start tag opens without a matching
+// end tag.
+var rawVoidTags = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
+// dropUnclosedRaw removes each raw start tag in nodes whose end tag was
+// lost to a cut, so the raw HTML kept before the cut stays balanced. A raw
+// tag and its end tag are treated as one unit: both are kept or neither.
+func dropUnclosedRaw(nodes []Node) []Node {
+	var open []int
+	var names []string
+
+	for i, n := range nodes {
+		raw, ok := n.(Raw)
+		if !ok {
+			continue
+		}
+
+		s := strings.TrimSpace(raw.Value)
+		if m := rawCloseTag.FindStringSubmatch(s); m != nil {
+			name := strings.ToLower(m[1])
+			for j := len(names) - 1; j >= 0; j-- {
+				if names[j] == name {
+					open, names = open[:j], names[:j]
+					break
+				}
+			}
+			continue
+		}
+
+		m := rawOpenTag.FindStringSubmatch(s)
+		if m == nil || strings.HasSuffix(s, "/>") || rawVoidTags[strings.ToLower(m[1])] {
+			continue
+		}
+		open = append(open, i)
+		names = append(names, strings.ToLower(m[1]))
+	}
+
+	if len(open) == 0 {
+		return nodes
+	}
+
+	drop := make(map[int]bool, len(open))
+	for _, i := range open {
+		drop[i] = true
+	}
+
+	out := make([]Node, 0, len(nodes)-len(open))
+	for i, n := range nodes {
+		if !drop[i] {
+			out = append(out, n)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,203 @@
+package html_test
+
+import (
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	tk "github.com/spcameron/seanpatrickcameron.com/internal/markdown/testkit"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestPlainText(t *testing.T) {
+	testCases := []struct {
+		name  string
+		node  html.Node
+		want  string
+		words int
+	}{
+		{
+			name: "block boundaries separate words",
+			node: tk.HTMLFragmentNode(
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one")),
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("two")),
+			),
+			want:  "one two",
+			words: 2,
+		},
+		{
+			name: "inline boundaries join words",
+			node: tk.HTMLElementNode("p", nil,
+				tk.HTMLTextNode("un"),
+				tk.HTMLElementNode("em", nil, tk.HTMLTextNode("break")),
+				tk.HTMLTextNode("able  words\n"),
+			),
+			want:  "unbreakable words",
+			words: 2,
+		},
+		{
+			name: "raw html contributes no text",
+			node: tk.HTMLElementNode("p", nil,
+				tk.HTMLTextNode("a "),
+				tk.HTMLRawNode("<span>hidden</span>"),
+				tk.HTMLTextNode(" b"),
+			),
+			want:  "a b",
+			words: 2,
+		},
+		{
+			name: "line breaks separate words",
+			node: tk.HTMLElementNode("p", nil,
+				tk.HTMLTextNode("a"),
+				tk.HTMLVoidNode("br", nil),
+				tk.HTMLTextNode("b"),
+			),
+			want:  "a b",
+			words: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, html.PlainText(tc.node), tc.want)
+			assert.Equal(t, html.CountWords(tc.node), tc.words)
+		})
+	}
+}
+
+func TestTruncateWords(t *testing.T) {
+	testCases := []struct {
+		name      string
+		node      html.Node
+		limit     int
+		want      string
+		truncated bool
+	}{
+		{
+			name:      "short content is returned whole",
+			node:      tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one two")),
+			limit:     2,
+			want:      "<p>one two</p>",
+			truncated: false,
+		},
+		{
+			name:      "cut within text adds an ellipsis",
+			node:      tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one two three")),
+			limit:     2,
+			want:      "<p>one two…</p>",
+			truncated: true,
+		},
+		{
+			name: "open elements are closed at the cut",
+			node: tk.HTMLFragmentNode(
+				tk.HTMLElementNode("p", nil,
+					tk.HTMLTextNode("one "),
+					tk.HTMLElementNode("strong", nil,
+						tk.HTMLTextNode("two "),
+						tk.HTMLElementNode("em", nil, tk.HTMLTextNode("three four")),
+					),
+					tk.HTMLTextNode(" five"),
+				),
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("six")),
+			),
+			limit:     3,
+			want:      "<p>one <strong>two <em>three…</em></strong></p>",
+			truncated: true,
+		},
+		{
+			name: "cut at a block boundary drops the emptied block",
+			node: tk.HTMLFragmentNode(
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one two")),
+				tk.HTMLElementNode("blockquote", nil,
+					tk.HTMLElementNode("p", nil, tk.HTMLTextNode("three")),
+				),
+			),
+			limit:     2,
+			want:      "<p>one two</p>",
+			truncated: true,
+		},
+		{
+			name: "cut before an inline element keeps no empty element",
+			node: tk.HTMLElementNode("p", nil,
+				tk.HTMLTextNode("one "),
+				tk.HTMLElementNode("em", nil, tk.HTMLTextNode("two")),
+			),
+			limit:     1,
+			want:      "<p>one </p>",
+			truncated: true,
+		},
+		{
+			name:      "zero limit yields an empty fragment",
+			node:      tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one")),
+			limit:     0,
+			want:      "",
+			truncated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, truncated := html.TruncateWords(tc.node, tc.limit)
+
+			rendered, err := html.Render(got)
+			require.NoError(t, err)
+			assert.Equal(t, rendered, tc.want)
+			assert.Equal(t, truncated, tc.truncated)
+		})
+	}
+}
+
+func TestCutBefore(t *testing.T) {
+	isMarker := func(n html.Node) bool {
+		raw, ok := n.(html.Raw)
+		return ok && raw.Value == "<!--more-->"
+	}
+
+	testCases := []struct {
+		name  string
+		node  html.Node
+		want  string
+		found bool
+	}{
+		{
+			name: "top-level marker keeps the preceding blocks",
+			node: tk.HTMLFragmentNode(
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one")),
+				tk.HTMLFragmentNode(tk.HTMLRawNode("<!--more-->")),
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("two")),
+			),
+			want:  "<p>one</p>",
+			found: true,
+		},
+		{
+			name: "nested marker closes its ancestors",
+			node: tk.HTMLFragmentNode(
+				tk.HTMLElementNode("p", nil,
+					tk.HTMLTextNode("one "),
+					tk.HTMLRawNode("<!--more-->"),
+					tk.HTMLTextNode(" two"),
+				),
+				tk.HTMLElementNode("p", nil, tk.HTMLTextNode("three")),
+			),
+			want:  "<p>one </p>",
+			found: true,
+		},
+		{
+			name:  "missing marker returns the node whole",
+			node:  tk.HTMLElementNode("p", nil, tk.HTMLTextNode("one")),
+			want:  "<p>one</p>",
+			found: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := html.CutBefore(tc.node, isMarker)
+
+			rendered, err := html.Render(got)
+			require.NoError(t, err)
+			assert.Equal(t, rendered, tc.want)
+			assert.Equal(t, found, tc.found)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package inline
+
+import "strings"
+
+// IsAutolinkBoundary reports whether an extended URL autolink may begin
+// after the byte before: whitespace or one of the delimiter characters
+// *, _, ~, and (.
+func IsAutolinkBoundary(before byte) bool {
+	switch before {
+	case ' ', '\t', '\n', '\r', '*', '_', '~', '(':
+		return true
+	default:
+		return false
+	}
+}
+
+// ScanURLAutolink reports whether an http://, https://, or www. autolink
+// begins at pos in s and returns its end. www reports whether the link was
+// written without a scheme.
+//
+// The link runs to the next whitespace or '<', less any trailing
+// punctuation, unbalanced closing parentheses, or entity reference, as the
+// GFM extended autolink rules describe.
+func ScanURLAutolink(s string, pos int) (int, bool, bool) {
+	rest := s[pos:]
+
+	var prefix int
+	var www bool
+	switch {
+	case HasPrefixFold(rest, "https://"):
+		prefix = len("https://")
+	case HasPrefixFold(rest, "http://"):
+		prefix = len("http://")
+	case HasPrefixFold(rest, "www."):
+		www = true
+	default:
+		return 0, false, false
+	}
+
+	domainEnd, ok := scanDomain(rest, prefix)
+	if !ok {
+		return 0, false, false
+	}
+
+	end := domainEnd
+	for end < len(rest) && !isAutolinkTerminator(rest[end]) {
+		end++
+	}
+
+	end = trimAutolinkTail(rest, domainEnd, end)
+
+	return pos + end, www, true
+}
+
+// scanDomain returns the end of the valid domain beginning at pos.
+//
+// A valid domain is a run of segments of letters, digits, underscores, and
+// hyphens separated by periods. It holds at least one period, and its last
+// two segments contain no underscores.
+func scanDomain(s string, pos int) (int, bool) {
+	end := pos
+	for end < len(s) && (IsDomainByte(s[end]) || s[end] == '.') {
+		end++
+	}
+
+	// a trailing period ends the sentence rather than the domain
+	for end > pos && s[end-1] == '.' {
+		end--
+	}
+
+	domain := s[pos:end]
+	segments := strings.Split(domain, ".")
+	if len(segments) < 2 {
+		return 0, false
+	}
+
+	for _, seg := range segments {
+		if seg == "" {
+			return 0, false
+		}
+	}
+
+	for _, seg := range segments[len(segments)-2:] {
+		if strings.Contains(seg, "_") {
+			return 0, false
+		}
+	}
+
+	return end, true
+}
+
+// trimAutolinkTail removes trailing punctuation, unbalanced closing
+// parentheses, and a trailing entity reference from the link s[:end],
+// never trimming into the domain, and returns the new end.
+func trimAutolinkTail(s string, domainEnd, end int) int {
+	for end > domainEnd {
+		switch s[end-1] {
+		case '?', '!', '.', ',', ':', '*', '_', '~':
+			end--
+			continue
+
+		case ')':
+			if strings.Count(s[:end], ")") > strings.Count(s[:end], "(") {
+				end--
+				continue
+			}
+
+		case ';':
+			amp := strings.LastIndexByte(s[:end], '&')
+			if amp >= domainEnd && isEntityName(s[amp+1:end-1]) {
+				end = amp
+				continue
+			}
+		}
+
+		break
+	}
+
+	return end
+}
+
+// HasPrefixFold reports whether s begins with prefix, ignoring ASCII case.
+func HasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// IsDomainByte reports whether b may appear in a segment of an autolinked
+// domain: a letter, digit, hyphen, or underscore.
+func IsDomainByte(b byte) bool {
+	return IsASCIIAlnum(b) || b == '-' || b == '_'
+}
+
+// IsEmailLocalByte reports whether b may appear in the local part of an
+// autolinked email address: a letter, digit, or one of . + - _.
+func IsEmailLocalByte(b byte) bool {
+	return IsASCIIAlnum(b) || b == '.' || b == '+' || b == '-' || b == '_'
+}
+
+// IsASCIIAlnum reports whether b is an ASCII letter or digit.
+func IsASCIIAlnum(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func isEntityName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !IsASCIIAlnum(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAutolinkTerminator(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '<'
+}
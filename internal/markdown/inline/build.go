@@ -2,13 +2,15 @@ package inline
 
 import (
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
-// Build resolves a tokenized inline span into AST inline nodes.
-func Build(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan, tokens []Token) ([]ast.Inline, error) {
-	c := NewCursor(src, defs, span, tokens)
+// Build resolves a tokenized inline span into AST inline nodes, recognizing
+// the inline constructs of any enabled extensions.
+func Build(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan, tokens []Token, exts extension.Set) ([]ast.Inline, error) {
+	c := NewCursor(src, defs, span, tokens, exts)
 
 	inlines, err := c.Build()
 	if err != nil {
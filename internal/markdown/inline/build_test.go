@@ -3,6 +3,8 @@ package inline
 import (
 	"testing"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
@@ -15,6 +17,7 @@ func TestBuild(t *testing.T) {
 		input   string
 		span    source.ByteSpan
 		defs    map[string]ir.ReferenceDefinition
+		exts    extension.Set
 		want    []InlineSummary
 		wantErr error
 	}{
@@ -1248,6 +1251,341 @@ func TestBuild(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+
+		// Math
+
+		{
+			name:  "math: dollar is literal without the extension",
+			input: "$x$",
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "$x$"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: inline",
+			input: `a $x^2$ b`,
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "a "},
+				{Kind: "math", Lexeme: "x^2"},
+				{Kind: "text", Lexeme: " b"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: display delimiters inline",
+			input: `$$\sum_i x_i$$`,
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "display_math", Lexeme: `\sum_i x_i`},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: content is not inline parsed",
+			input: `$a * b * c$`,
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "math", Lexeme: "a * b * c"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: opener followed by space is literal",
+			input: "$ x$",
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "$"},
+				{Kind: "text", Lexeme: " x"},
+				{Kind: "text", Lexeme: "$"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: currency amounts stay literal",
+			input: "$5 and $10",
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "$"},
+				{Kind: "text", Lexeme: "5 and "},
+				{Kind: "text", Lexeme: "$"},
+				{Kind: "text", Lexeme: "10"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: escaped dollar inside content does not close",
+			input: `$a\$b$`,
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "math", Lexeme: `a\$b`},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: escaped opener is literal",
+			input: `\$x$`,
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "$"},
+				{Kind: "text", Lexeme: "x"},
+				{Kind: "text", Lexeme: "$"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math: code span takes precedence",
+			input: "`$x$`",
+			exts:  extension.Math,
+			want: []InlineSummary{
+				{Kind: "code_span", Lexeme: "$x$"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: braces are literal without the extension",
+			input: "[a]{.x}",
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "a"},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: "{.x}"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: inline link absorbs a following list",
+			input: "[a](/b){.x} c",
+			exts:  extension.Attributes,
+			want: []InlineSummary{
+				{Kind: "link", Lexeme: "[a](/b){.x}", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "a"},
+				}},
+				{Kind: "text", Lexeme: " c"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: image absorbs a following list",
+			input: "![a](/b){width=10}",
+			exts:  extension.Attributes,
+			want: []InlineSummary{
+				{Kind: "image", Lexeme: "![a](/b){width=10}", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "a"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: bracketed span",
+			input: "[*a*]{.x}",
+			exts:  extension.Attributes,
+			want: []InlineSummary{
+				{Kind: "bracketed_span", Lexeme: "[*a*]{.x}", Children: []InlineSummary{
+					{Kind: "emphasis", Lexeme: "*a*", Children: []InlineSummary{
+						{Kind: "text", Lexeme: "a"},
+					}},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: separated list is text",
+			input: "[a] {.x}",
+			exts:  extension.Attributes,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "a"},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: " "},
+				{Kind: "text", Lexeme: "{"},
+				{Kind: "text", Lexeme: ".x"},
+				{Kind: "text", Lexeme: "}"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes: invalid name is a diagnostic",
+			input: "[a]{1=b}",
+			exts:  extension.Attributes,
+			want:  []InlineSummary{},
+			wantErr: diagnostic.DiagnosticError{Diagnostic: diagnostic.Diagnostic{
+				Message:  `invalid attribute name "1"`,
+				Span:     source.ByteSpan{Start: 4, End: 5},
+				Severity: diagnostic.SeverityError,
+			}},
+		},
+		{
+			name:  "superscript: single caret",
+			input: "x^2^",
+			exts:  extension.Superscript,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "x"},
+				{Kind: "superscript", Lexeme: "^2^", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "2"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "superscript: disabled is text",
+			input: "x^2^",
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "x^2^"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "subscript: single tilde",
+			input: "H~2~O",
+			exts:  extension.Subscript,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "H"},
+				{Kind: "subscript", Lexeme: "~2~", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "2"},
+				}},
+				{Kind: "text", Lexeme: "O"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "strikethrough: double tilde alongside subscript",
+			input: "~~a~b~c~~",
+			exts:  extension.Subscript | extension.Strikethrough,
+			want: []InlineSummary{
+				{Kind: "strikethrough", Lexeme: "~~a~b~c~~", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "a"},
+					{Kind: "subscript", Lexeme: "~b~", Children: []InlineSummary{
+						{Kind: "text", Lexeme: "b"},
+					}},
+					{Kind: "text", Lexeme: "c"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "strikethrough: single tilde is text without subscript",
+			input: "~a~ ~~b~~",
+			exts:  extension.Strikethrough,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "~"},
+				{Kind: "text", Lexeme: "a"},
+				{Kind: "text", Lexeme: "~"},
+				{Kind: "text", Lexeme: " "},
+				{Kind: "strikethrough", Lexeme: "~~b~~", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "b"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "highlight: double equals",
+			input: "==mark== a = b",
+			exts:  extension.Highlight,
+			want: []InlineSummary{
+				{Kind: "highlight", Lexeme: "==mark==", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "mark"},
+				}},
+				{Kind: "text", Lexeme: " a "},
+				{Kind: "text", Lexeme: "="},
+				{Kind: "text", Lexeme: " b"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "insert: double plus",
+			input: "++new++",
+			exts:  extension.Insert,
+			want: []InlineSummary{
+				{Kind: "insert", Lexeme: "++new++", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "new"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "insert: C++ and C++ stays literal",
+			input: "C++ and C++",
+			exts:  extension.Insert,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "C"},
+				{Kind: "text", Lexeme: "++"},
+				{Kind: "text", Lexeme: " and C"},
+				{Kind: "text", Lexeme: "++"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "superscript: mixed with emphasis",
+			input: "*a^b^*",
+			exts:  extension.Superscript,
+			want: []InlineSummary{
+				{Kind: "emphasis", Lexeme: "*a^b^*", Children: []InlineSummary{
+					{Kind: "text", Lexeme: "a"},
+					{Kind: "superscript", Lexeme: "^b^", Children: []InlineSummary{
+						{Kind: "text", Lexeme: "b"},
+					}},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "wiki links: target only",
+			input: "see [[hello-world]]!",
+			exts:  extension.WikiLinks,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "see "},
+				{Kind: "wiki_link", Lexeme: "[[hello-world]]", Children: []InlineSummary{
+					{Kind: "wiki_target", Lexeme: "hello-world"},
+				}},
+				{Kind: "text", Lexeme: "!"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "wiki links: fragment and label are trimmed",
+			input: "[[ a-post # setup | the *setup* ]]",
+			exts:  extension.WikiLinks,
+			want: []InlineSummary{
+				{Kind: "wiki_link", Lexeme: "[[ a-post # setup | the *setup* ]]", Children: []InlineSummary{
+					{Kind: "wiki_target", Lexeme: "a-post"},
+					{Kind: "wiki_fragment", Lexeme: "setup"},
+					{Kind: "wiki_label", Lexeme: "the *setup*"},
+				}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "wiki links: empty parts are text",
+			input: "[[]] [[a|]]",
+			exts:  extension.WikiLinks,
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: " "},
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "a|"},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: "]"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "wiki links: brackets are ordinary when disabled",
+			input: "[[a]]",
+			want: []InlineSummary{
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "["},
+				{Kind: "text", Lexeme: "a"},
+				{Kind: "text", Lexeme: "]"},
+				{Kind: "text", Lexeme: "]"},
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1262,7 +1600,7 @@ func TestBuild(t *testing.T) {
 				}
 			}
 
-			tokens, err := Scan(src, span)
+			tokens, err := Scan(src, span, tc.exts)
 			require.NoError(t, err)
 
 			defs := tc.defs
@@ -1270,7 +1608,7 @@ func TestBuild(t *testing.T) {
 				defs = map[string]ir.ReferenceDefinition{}
 			}
 
-			inlines, err := Build(src, defs, span, tokens)
+			inlines, err := Build(src, defs, span, tokens, tc.exts)
 			got := summarizeInlines(src, inlines)
 
 			assert.Equal(t, got, tc.want)
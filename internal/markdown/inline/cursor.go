@@ -7,6 +7,8 @@ import (
 	"unicode/utf8"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/attribute"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/reference"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
@@ -22,10 +24,11 @@ type Cursor struct {
 	Index       int
 	Items       *ItemList
 	Delimiters  *DelimiterList
+	Extensions  extension.Set
 }
 
 // NewCursor constructs an inline parsing cursor over the given token stream.
-func NewCursor(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan, tokens []Token) *Cursor {
+func NewCursor(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan, tokens []Token, exts extension.Set) *Cursor {
 	return &Cursor{
 		Source:      src,
 		Definitions: defs,
@@ -34,6 +37,7 @@ func NewCursor(src *source.Source, defs map[string]ir.ReferenceDefinition, span
 		Index:       0,
 		Items:       NewItemList(),
 		Delimiters:  NewDelimiterList(),
+		Extensions:  exts,
 	}
 }
 
@@ -74,7 +78,10 @@ func (c *Cursor) Build() ([]ast.Inline, error) {
 		return []ast.Inline{}, err
 	}
 
-	inlines := c.lowerItems(c.Items)
+	inlines, err := c.lowerItems(c.Items)
+	if err != nil {
+		return []ast.Inline{}, err
+	}
 
 	return inlines, nil
 }
@@ -98,6 +105,18 @@ func (c *Cursor) buildItems() error {
 		case TokenUnderscoreDelimiter:
 			c.handleUnderscoreDelimiter()
 
+		case TokenCaretDelimiter:
+			c.handleExtendedDelimiter(DelimCaret)
+
+		case TokenTildeDelimiter:
+			c.handleExtendedDelimiter(DelimTilde)
+
+		case TokenEqualsDelimiter:
+			c.handleExtendedDelimiter(DelimEquals)
+
+		case TokenPlusDelimiter:
+			c.handleExtendedDelimiter(DelimPlus)
+
 		case TokenBacktick:
 			c.handleTokenBacktick()
 
@@ -128,6 +147,15 @@ func (c *Cursor) buildItems() error {
 		case TokenBackslash:
 			c.handleTokenBackslash()
 
+		case TokenDollar:
+			c.handleTokenDollar()
+
+		case TokenOpenBrace:
+			c.appendItemRecord(token.Span, ItemText)
+
+		case TokenCloseBrace:
+			c.appendItemRecord(token.Span, ItemText)
+
 		default:
 			panic(fmt.Sprintf("unknown token kind encountered (%d)", token.Kind))
 		}
@@ -140,7 +168,7 @@ func (c *Cursor) buildItems() error {
 }
 
 // lowerItems converts resolved item records into AST inline nodes.
-func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
+func (c *Cursor) lowerItems(items *ItemList) ([]ast.Inline, error) {
 	inlines := []ast.Inline{}
 
 	item := items.Front()
@@ -169,6 +197,7 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 			node := ast.Link{
 				Span:        item.OriginalSpan,
 				Destination: contentSpan,
+				Autolink:    true,
 				Children: []ast.Inline{
 					ast.Text{
 						Span: contentSpan,
@@ -188,6 +217,7 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 				Span:        item.OriginalSpan,
 				Destination: contentSpan,
 				MailTo:      true,
+				Autolink:    true,
 				Children: []ast.Inline{
 					ast.Text{
 						Span: contentSpan,
@@ -204,8 +234,30 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 
 			inlines = append(inlines, node)
 
+		case ItemWikiLink:
+			node := ast.WikiLink{
+				Span:     item.OriginalSpan,
+				Target:   item.DestinationSpan,
+				Fragment: item.FragmentSpan,
+				Label:    item.LabelSpan,
+			}
+
+			inlines = append(inlines, node)
+
+		case ItemMath, ItemDisplayMath:
+			node := ast.Math{
+				Span:    item.OriginalSpan,
+				Content: item.LiveSpan,
+				Display: item.Kind == ItemDisplayMath,
+			}
+
+			inlines = append(inlines, node)
+
 		case ItemEmphasis:
-			children := c.lowerItems(item.Children)
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
 
 			node := ast.Emph{
 				Span:     item.OriginalSpan,
@@ -215,7 +267,10 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 			inlines = append(inlines, node)
 
 		case ItemStrong:
-			children := c.lowerItems(item.Children)
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
 
 			node := ast.Strong{
 				Span:     item.OriginalSpan,
@@ -224,8 +279,19 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 
 			inlines = append(inlines, node)
 
+		case ItemSuperscript, ItemSubscript, ItemStrikethrough, ItemHighlight, ItemInsert:
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
+
+			inlines = append(inlines, lowerStyledItem(item, children))
+
 		case ItemLink:
-			children := c.lowerItems(item.Children)
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
 
 			node := ast.Link{
 				Span:        item.OriginalSpan,
@@ -237,10 +303,19 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 				node.Title = item.TitleSpan
 			}
 
+			attrs, err := c.lowerAttributes(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Attributes = attrs
+
 			inlines = append(inlines, node)
 
 		case ItemImage:
-			children := c.lowerItems(item.Children)
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
 
 			node := ast.Image{
 				Span:        item.OriginalSpan,
@@ -252,6 +327,31 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 				node.Title = item.TitleSpan
 			}
 
+			attrs, err := c.lowerAttributes(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Attributes = attrs
+
+			inlines = append(inlines, node)
+
+		case ItemBracketedSpan:
+			children, err := c.lowerItems(item.Children)
+			if err != nil {
+				return nil, err
+			}
+
+			attrs, err := c.lowerAttributes(item)
+			if err != nil {
+				return nil, err
+			}
+
+			node := ast.BracketedSpan{
+				Span:       item.OriginalSpan,
+				Children:   children,
+				Attributes: attrs,
+			}
+
 			inlines = append(inlines, node)
 
 		default:
@@ -261,7 +361,41 @@ func (c *Cursor) lowerItems(items *ItemList) []ast.Inline {
 		item = item.Next()
 	}
 
-	return inlines
+	return inlines, nil
+}
+
+// lowerStyledItem converts a resolved extension delimiter item into its AST
+// node.
+func lowerStyledItem(item *ItemRecord, children []ast.Inline) ast.Inline {
+	switch item.Kind {
+	case ItemSuperscript:
+		return ast.Superscript{Span: item.OriginalSpan, Children: children}
+
+	case ItemSubscript:
+		return ast.Subscript{Span: item.OriginalSpan, Children: children}
+
+	case ItemStrikethrough:
+		return ast.Strikethrough{Span: item.OriginalSpan, Children: children}
+
+	case ItemHighlight:
+		return ast.Highlight{Span: item.OriginalSpan, Children: children}
+
+	case ItemInsert:
+		return ast.Insert{Span: item.OriginalSpan, Children: children}
+
+	default:
+		panic(fmt.Sprintf("lowerStyledItem: unexpected item kind (%d)", item.Kind))
+	}
+}
+
+// lowerAttributes parses the attribute list attached to item, returning nil
+// when it has none.
+func (c *Cursor) lowerAttributes(item *ItemRecord) (ast.Attributes, error) {
+	if !item.HasAttributes {
+		return nil, nil
+	}
+
+	return attribute.Parse(c.Source, item.AttributesSpan)
 }
 
 // processEmphasis resolves emphasis and strong emphasis by matching
@@ -288,8 +422,8 @@ func (c *Cursor) processEmphasis(stackBottom *DelimiterRecord) {
 		opener := findMatchingOpener(current, stackBottom, openerBottom)
 
 		if opener != nil {
-			strong := opener.Count >= 2 && current.Count >= 2
-			current = c.resolveEmphasisMatch(opener, current, strong)
+			use, kind := matchedDelimiterUse(opener, current)
+			current = c.resolveEmphasisMatch(opener, current, use, kind)
 			continue
 		}
 
@@ -305,13 +439,39 @@ func (c *Cursor) processEmphasis(stackBottom *DelimiterRecord) {
 	c.removeAllDelimitersAbove(stackBottom)
 }
 
-// resolveEmphasisMatch consumes a matched opener/closer pair and
-// replaces their contents with an emphasis or strong item.
-func (c *Cursor) resolveEmphasisMatch(opener, closer *DelimiterRecord, strong bool) *DelimiterRecord {
-	use := 1
-	if strong {
-		use = 2
+// matchedDelimiterUse reports how many delimiter characters a matched
+// opener/closer pair consumes and the kind of item they produce.
+//
+// Emphasis consumes one character and strong emphasis two. The extension
+// delimiters only match runs of equal length and consume them whole.
+func matchedDelimiterUse(opener, closer *DelimiterRecord) (int, ItemKind) {
+	switch opener.Kind {
+	case DelimCaret:
+		return opener.Count, ItemSuperscript
+
+	case DelimTilde:
+		if opener.Count == 2 {
+			return opener.Count, ItemStrikethrough
+		}
+		return opener.Count, ItemSubscript
+
+	case DelimEquals:
+		return opener.Count, ItemHighlight
+
+	case DelimPlus:
+		return opener.Count, ItemInsert
+
+	default:
+		if opener.Count >= 2 && closer.Count >= 2 {
+			return 2, ItemStrong
+		}
+		return 1, ItemEmphasis
 	}
+}
+
+// resolveEmphasisMatch consumes use characters from a matched opener/closer
+// pair and replaces their contents with an item of the given kind.
+func (c *Cursor) resolveEmphasisMatch(opener, closer *DelimiterRecord, use int, kind ItemKind) *DelimiterRecord {
 
 	originalSpan := source.ByteSpan{
 		Start: opener.Item.LiveSpan.End - source.BytePos(use),
@@ -350,15 +510,10 @@ func (c *Cursor) resolveEmphasisMatch(opener, closer *DelimiterRecord, strong bo
 	item := &ItemRecord{
 		OriginalSpan: originalSpan,
 		LiveSpan:     liveSpan,
+		Kind:         kind,
 		Children:     childList,
 	}
 
-	if use == 1 {
-		item.Kind = ItemEmphasis
-	} else {
-		item.Kind = ItemStrong
-	}
-
 	c.Items.InsertAfter(item, opener.Item)
 
 	if opener.Count == 0 {
@@ -417,6 +572,10 @@ func newOpenersTable(bottom *DelimiterRecord) map[openerKey]*DelimiterRecord {
 	kinds := []DelimiterKind{
 		DelimAsterisk,
 		DelimUnderscore,
+		DelimCaret,
+		DelimTilde,
+		DelimEquals,
+		DelimPlus,
 	}
 
 	for _, kind := range kinds {
@@ -473,6 +632,11 @@ func delimitersMatch(opener, closer *DelimiterRecord) bool {
 		return false
 	}
 
+	// extension delimiters pair only with runs of the same length
+	if opener.Kind != DelimAsterisk && opener.Kind != DelimUnderscore {
+		return opener.Count == closer.Count
+	}
+
 	if (opener.CanClose || closer.CanOpen) &&
 		(opener.Count+closer.Count)%3 == 0 {
 		return false
@@ -536,6 +700,59 @@ func (c *Cursor) handleUnderscoreDelimiter() {
 	c.Delimiters.PushBack(delim)
 }
 
+// handleExtendedDelimiter records a delimiter run belonging to one of the
+// superscript, subscript, strikethrough, highlight, or insert extensions.
+//
+// Runs whose length does not form an enabled construct remain literal text.
+// Flanking follows the same rules as '*' runs.
+func (c *Cursor) handleExtendedDelimiter(kind DelimiterKind) {
+	tokenIdx := c.Index - 1
+	token := c.Tokens[tokenIdx]
+
+	item := c.appendItemRecord(token.Span, ItemText)
+
+	if !c.acceptsDelimiterRun(kind, token.Span.Width()) {
+		return
+	}
+
+	before, beforeOK := c.runeBefore(token.Span)
+	after, afterOK := c.runeAfter(token.Span)
+
+	delim := &DelimiterRecord{
+		Item:     item,
+		Kind:     kind,
+		Count:    token.Span.Width(),
+		Active:   true,
+		CanOpen:  leftFlanking(before, beforeOK, after, afterOK),
+		CanClose: rightFlanking(before, beforeOK, after, afterOK),
+	}
+
+	c.Delimiters.PushBack(delim)
+}
+
+// acceptsDelimiterRun reports whether a run of width delimiters of kind
+// forms a construct of an enabled extension: a single '^' or '~', or a
+// doubled '~', '=', or '+'.
+func (c *Cursor) acceptsDelimiterRun(kind DelimiterKind, width int) bool {
+	switch kind {
+	case DelimCaret:
+		return width == 1 && c.Extensions.Has(extension.Superscript)
+
+	case DelimTilde:
+		return width == 1 && c.Extensions.Has(extension.Subscript) ||
+			width == 2 && c.Extensions.Has(extension.Strikethrough)
+
+	case DelimEquals:
+		return width == 2 && c.Extensions.Has(extension.Highlight)
+
+	case DelimPlus:
+		return width == 2 && c.Extensions.Has(extension.Insert)
+
+	default:
+		return false
+	}
+}
+
 func (c *Cursor) handleTokenBacktick() {
 	openerIdx := c.Index - 1
 	openerToken := c.Tokens[openerIdx]
@@ -592,10 +809,105 @@ func (c *Cursor) handleTokenBacktick() {
 	c.Index = closerIdx + 1
 }
 
+// handleTokenDollar resolves inline math delimited by $ or $$.
+//
+// As with code spans, the content is taken verbatim up to the first
+// matching closer. A single $ must be followed by a non-space to open, and
+// preceded by a non-space and not followed by a digit to close, so that
+// amounts such as $5 and $10 remain literal text.
+func (c *Cursor) handleTokenDollar() {
+	openerIdx := c.Index - 1
+	openerToken := c.Tokens[openerIdx]
+	openerWidth := openerToken.Span.Width()
+
+	if openerWidth > 2 || (openerWidth == 1 && !c.canOpenMath(openerToken)) {
+		c.appendItemRecord(openerToken.Span, ItemText)
+		return
+	}
+
+	closerIdx := openerIdx + 1
+	for closerIdx < len(c.Tokens) {
+		next := c.Tokens[closerIdx]
+		if next.Kind != TokenDollar || next.Span.Width() != openerWidth {
+			closerIdx++
+			continue
+		}
+
+		// an escaped dollar belongs to the TeX content
+		prev := c.Tokens[closerIdx-1]
+		if prev.Kind == TokenBackslash && prev.Span.End == next.Span.Start {
+			closerIdx++
+			continue
+		}
+
+		if openerWidth == 1 && !c.canCloseMath(next) {
+			closerIdx++
+			continue
+		}
+
+		break
+	}
+
+	if closerIdx >= len(c.Tokens) {
+		c.appendItemRecord(openerToken.Span, ItemText)
+		return
+	}
+
+	originalSpan := source.ByteSpan{
+		Start: c.Tokens[openerIdx].Span.Start,
+		End:   c.Tokens[closerIdx].Span.End,
+	}
+
+	liveSpan := source.ByteSpan{
+		Start: c.Tokens[openerIdx].Span.End,
+		End:   c.Tokens[closerIdx].Span.Start,
+	}
+
+	if isAllSpaces(c.Source.Slice(liveSpan)) {
+		c.appendItemRecord(openerToken.Span, ItemText)
+		return
+	}
+
+	kind := ItemMath
+	if openerWidth == 2 {
+		kind = ItemDisplayMath
+	}
+
+	item := &ItemRecord{
+		OriginalSpan: originalSpan,
+		LiveSpan:     liveSpan,
+		Kind:         kind,
+	}
+
+	c.Items.PushBack(item)
+	c.Index = closerIdx + 1
+}
+
+// canOpenMath reports whether a single-dollar token may open inline math.
+func (c *Cursor) canOpenMath(tok Token) bool {
+	after, ok := c.runeAfter(tok.Span)
+	return ok && !isWhitespace(after)
+}
+
+// canCloseMath reports whether a single-dollar token may close inline math.
+func (c *Cursor) canCloseMath(tok Token) bool {
+	before, ok := c.runeBefore(tok.Span)
+	if !ok || isWhitespace(before) {
+		return false
+	}
+
+	after, ok := c.runeAfter(tok.Span)
+	return !ok || !unicode.IsDigit(after)
+}
+
 func (c *Cursor) handleTokenOpenBracket() {
 	tokenIdx := c.Index - 1
 	token := c.Tokens[tokenIdx]
 
+	if c.Extensions.Has(extension.WikiLinks) && c.tryParseWikiLink(token) {
+		return
+	}
+
 	item := c.appendItemRecord(token.Span, ItemText)
 
 	delim := &DelimiterRecord{
@@ -640,6 +952,10 @@ func (c *Cursor) handleTokenCloseBracket() {
 			return
 		}
 
+		if c.tryResolveBracketedSpan(openerDelim, token) {
+			return
+		}
+
 		c.Delimiters.Remove(openerDelim)
 		c.appendItemRecord(token.Span, ItemText)
 		return
@@ -658,6 +974,90 @@ func (c *Cursor) handleTokenCloseBracket() {
 	}
 }
 
+// tryParseWikiLink recognizes a [[target#fragment|label]] wiki link
+// opening at token, appending it as a single resolved item.
+//
+// The target is required, and the fragment and label are optional. The
+// link may not span lines or contain brackets, and an empty fragment or
+// label leaves the brackets as literal text.
+func (c *Cursor) tryParseWikiLink(token Token) bool {
+	start := token.Span.Start
+	s := c.Source.Slice(source.ByteSpan{Start: start, End: c.Span.End})
+
+	if !strings.HasPrefix(s, "[[") {
+		return false
+	}
+
+	end := strings.Index(s, "]]")
+	if end == -1 {
+		return false
+	}
+
+	inner := s[2:end]
+	if strings.ContainsAny(inner, "[]\n\r") {
+		return false
+	}
+
+	relSpan := func(from, to int) source.ByteSpan {
+		return source.ByteSpan{
+			Start: start + source.BytePos(from),
+			End:   start + source.BytePos(to),
+		}
+	}
+
+	targetEnd := 2 + len(inner)
+	labelSpan := source.ByteSpan{}
+	if bar := strings.IndexByte(inner, '|'); bar != -1 {
+		targetEnd = 2 + bar
+
+		from, to, ok := trimmedBounds(s, targetEnd+1, 2+len(inner))
+		if !ok {
+			return false
+		}
+		labelSpan = relSpan(from, to)
+	}
+
+	fragmentSpan := source.ByteSpan{}
+	if hash := strings.IndexByte(s[2:targetEnd], '#'); hash != -1 {
+		from, to, ok := trimmedBounds(s, 2+hash+1, targetEnd)
+		if !ok {
+			return false
+		}
+		fragmentSpan = relSpan(from, to)
+		targetEnd = 2 + hash
+	}
+
+	from, to, ok := trimmedBounds(s, 2, targetEnd)
+	if !ok {
+		return false
+	}
+
+	item := c.appendItemRecord(relSpan(0, end+2), ItemWikiLink)
+	item.DestinationSpan = relSpan(from, to)
+	item.FragmentSpan = fragmentSpan
+	item.LabelSpan = labelSpan
+
+	c.advanceToBytePos(item.OriginalSpan.End)
+	return true
+}
+
+// trimmedBounds returns the bounds of s[from:to] with surrounding spaces
+// and tabs removed, reporting false when nothing remains.
+func trimmedBounds(s string, from, to int) (int, int, bool) {
+	for from < to && isSpace(s[from]) {
+		from++
+	}
+	for to > from && isSpace(s[to-1]) {
+		to--
+	}
+
+	if from == to {
+		return 0, 0, false
+	}
+
+	return from, to, true
+}
+
 func (c *Cursor) handleTokenOpenAngle() {
 	openerIdx := c.Index - 1
 	openerToken := c.Tokens[openerIdx]
@@ -828,10 +1228,13 @@ type ResolvedBracketResult struct {
 	DestinationSpan source.ByteSpan
 	TitleSpan       source.ByteSpan
 	HasTitle        bool
+	AttributesSpan  source.ByteSpan
+	HasAttributes   bool
 }
 
 // tryResolveBracket attempts the supported bracket resolution forms in
-// precedence order and finalizes the first successful match.
+// precedence order and finalizes the first successful match, along with
+// any attribute list that immediately follows it.
 func (c *Cursor) tryResolveBracket(opener *DelimiterRecord, token Token, kind ItemKind) bool {
 	tryFns := []func(*DelimiterRecord, Token) (ResolvedBracketResult, bool){
 		c.tryParseInlineBracket,
@@ -842,6 +1245,12 @@ func (c *Cursor) tryResolveBracket(opener *DelimiterRecord, token Token, kind It
 
 	for _, fn := range tryFns {
 		if result, ok := fn(opener, token); ok {
+			if attrSpan, ok := c.tryParseAttributes(result.FullSpan.End); ok {
+				result.FullSpan.End = attrSpan.End
+				result.AttributesSpan = attrSpan
+				result.HasAttributes = true
+			}
+
 			cmd := FinalizeBracketCommand{
 				Kind:           kind,
 				OpenerDelim:    opener,
@@ -856,6 +1265,58 @@ func (c *Cursor) tryResolveBracket(opener *DelimiterRecord, token Token, kind It
 	return false
 }
 
+// tryResolveBracketedSpan finalizes a bracket whose closing ']' is
+// immediately followed by an attribute list as a bracketed span.
+func (c *Cursor) tryResolveBracketedSpan(opener *DelimiterRecord, token Token) bool {
+	attrSpan, ok := c.tryParseAttributes(token.Span.End)
+	if !ok {
+		return false
+	}
+
+	cmd := FinalizeBracketCommand{
+		Kind:           ItemBracketedSpan,
+		OpenerDelim:    opener,
+		CloseTokenSpan: token.Span,
+		Result: ResolvedBracketResult{
+			FullSpan: source.ByteSpan{
+				Start: opener.Item.OriginalSpan.Start,
+				End:   attrSpan.End,
+			},
+			AttributesSpan: attrSpan,
+			HasAttributes:  true,
+		},
+	}
+	c.finalizeBracket(cmd)
+
+	return true
+}
+
+// tryParseAttributes reports whether an attribute list begins at start and
+// returns its span. It always reports false when the Attributes extension is
+// disabled.
+func (c *Cursor) tryParseAttributes(start source.BytePos) (source.ByteSpan, bool) {
+	if !c.Extensions.Has(extension.Attributes) {
+		return source.ByteSpan{}, false
+	}
+
+	s := c.Source.Slice(source.ByteSpan{
+		Start: start,
+		End:   c.Span.End,
+	})
+
+	n, ok := attribute.Scan(s)
+	if !ok {
+		return source.ByteSpan{}, false
+	}
+
+	span := source.ByteSpan{
+		Start: start,
+		End:   start + source.BytePos(n),
+	}
+
+	return span, true
+}
+
 // tryParseInlineBracket attempts to parse an inline link or image tail
 // following a closing bracket.
 func (c *Cursor) tryParseInlineBracket(opener *DelimiterRecord, token Token) (ResolvedBracketResult, bool) {
@@ -1092,6 +1553,8 @@ func (c *Cursor) finalizeBracket(cmd FinalizeBracketCommand) {
 	if cmd.Result.HasTitle {
 		openerItem.TitleSpan = cmd.Result.TitleSpan
 	}
+	openerItem.HasAttributes = cmd.Result.HasAttributes
+	openerItem.AttributesSpan = cmd.Result.AttributesSpan
 	openerItem.Children = childList
 
 	// for links, deactivate all prior '[' delimiters
@@ -9,6 +9,10 @@ const (
 	DelimImageOpenBracket
 	DelimAsterisk
 	DelimUnderscore
+	DelimCaret
+	DelimTilde
+	DelimEquals
+	DelimPlus
 )
 
 // DelimiterRecord represents a delimiter in the inline parse, participating
@@ -55,6 +55,27 @@ func (ts TokenSummary) String() string {
 	case TokenBackslash:
 		return `backslash("\")`
 
+	case TokenDollar:
+		return fmt.Sprintf("dollar(%q)", ts.Lexeme)
+
+	case TokenCaretDelimiter:
+		return fmt.Sprintf("caret(%q)", ts.Lexeme)
+
+	case TokenTildeDelimiter:
+		return fmt.Sprintf("tilde(%q)", ts.Lexeme)
+
+	case TokenEqualsDelimiter:
+		return fmt.Sprintf("equals(%q)", ts.Lexeme)
+
+	case TokenPlusDelimiter:
+		return fmt.Sprintf("plus(%q)", ts.Lexeme)
+
+	case TokenOpenBrace:
+		return `open_brace("{")`
+
+	case TokenCloseBrace:
+		return `close_brace("}")`
+
 	case TokenEOF:
 		return "EOF"
 
@@ -88,7 +109,8 @@ type InlineSummary struct {
 
 func (s InlineSummary) String() string {
 	switch s.Kind {
-	case "text", "raw_text", "hard_break", "soft_break", "newline":
+	case "text", "raw_text", "hard_break", "soft_break", "newline", "math", "display_math",
+		"wiki_target", "wiki_fragment", "wiki_label":
 		return fmt.Sprintf("%s(%q)", s.Kind, s.Lexeme)
 
 	default:
@@ -125,6 +147,23 @@ func summarizeInline(src *source.Source, inl ast.Inline) InlineSummary {
 			Children: summarizeInlines(src, n.Children),
 		}
 
+	case ast.WikiLink:
+		children := []InlineSummary{
+			{Kind: "wiki_target", Lexeme: src.Slice(n.Target)},
+		}
+		if n.Fragment != (source.ByteSpan{}) {
+			children = append(children, InlineSummary{Kind: "wiki_fragment", Lexeme: src.Slice(n.Fragment)})
+		}
+		if n.Label != (source.ByteSpan{}) {
+			children = append(children, InlineSummary{Kind: "wiki_label", Lexeme: src.Slice(n.Label)})
+		}
+
+		return InlineSummary{
+			Kind:     "wiki_link",
+			Lexeme:   src.Slice(n.Span),
+			Children: children,
+		}
+
 	case ast.Image:
 		return InlineSummary{
 			Kind:     "image",
@@ -132,6 +171,13 @@ func summarizeInline(src *source.Source, inl ast.Inline) InlineSummary {
 			Children: summarizeInlines(src, n.Children),
 		}
 
+	case ast.BracketedSpan:
+		return InlineSummary{
+			Kind:     "bracketed_span",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
 	case ast.Emph:
 		return InlineSummary{
 			Kind:     "emphasis",
@@ -146,6 +192,41 @@ func summarizeInline(src *source.Source, inl ast.Inline) InlineSummary {
 			Children: summarizeInlines(src, n.Children),
 		}
 
+	case ast.Superscript:
+		return InlineSummary{
+			Kind:     "superscript",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
+	case ast.Subscript:
+		return InlineSummary{
+			Kind:     "subscript",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
+	case ast.Strikethrough:
+		return InlineSummary{
+			Kind:     "strikethrough",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
+	case ast.Highlight:
+		return InlineSummary{
+			Kind:     "highlight",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
+	case ast.Insert:
+		return InlineSummary{
+			Kind:     "insert",
+			Lexeme:   src.Slice(n.Span),
+			Children: summarizeInlines(src, n.Children),
+		}
+
 	case ast.Text:
 		return InlineSummary{
 			Kind:   "text",
@@ -158,6 +239,17 @@ func summarizeInline(src *source.Source, inl ast.Inline) InlineSummary {
 			Lexeme: src.Slice(n.Span),
 		}
 
+	case ast.Math:
+		kind := "math"
+		if n.Display {
+			kind = "display_math"
+		}
+
+		return InlineSummary{
+			Kind:   kind,
+			Lexeme: src.Slice(n.Content),
+		}
+
 	case ast.HardBreak:
 		return InlineSummary{
 			Kind:   "hard_break",
@@ -16,6 +16,15 @@ const (
 	ItemHTML
 	ItemEmphasis
 	ItemStrong
+	ItemMath
+	ItemDisplayMath
+	ItemBracketedSpan
+	ItemSuperscript
+	ItemSubscript
+	ItemStrikethrough
+	ItemHighlight
+	ItemInsert
+	ItemWikiLink
 )
 
 // ItemRecord represents a provisional or resolved inline item in the
@@ -31,6 +40,10 @@ type ItemRecord struct {
 	DestinationSpan source.ByteSpan
 	TitleSpan       source.ByteSpan
 	HasTitle        bool
+	AttributesSpan  source.ByteSpan
+	HasAttributes   bool
+	FragmentSpan    source.ByteSpan
+	LabelSpan       source.ByteSpan
 
 	Children *ItemList
 }
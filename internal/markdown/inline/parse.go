@@ -2,18 +2,20 @@ package inline
 
 import (
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
-// Parse scans and builds inline content within span into AST inline nodes.
-func Parse(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan) ([]ast.Inline, error) {
-	tokens, err := Scan(src, span)
+// Parse scans and builds inline content within span into AST inline nodes,
+// recognizing the inline syntax of any enabled extensions.
+func Parse(src *source.Source, defs map[string]ir.ReferenceDefinition, span source.ByteSpan, exts extension.Set) ([]ast.Inline, error) {
+	tokens, err := Scan(src, span, exts)
 	if err != nil {
 		return nil, err
 	}
 
-	out, err := Build(src, defs, span, tokens)
+	out, err := Build(src, defs, span, tokens, exts)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,14 @@
 package inline
 
 import (
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 )
 
 // Scan tokenizes the inline source covered by span.
-func Scan(src *source.Source, span source.ByteSpan) ([]Token, error) {
+func Scan(src *source.Source, span source.ByteSpan, exts extension.Set) ([]Token, error) {
 	input := src.Slice(span)
-	scanner := NewScanner(input, span.Start)
+	scanner := NewScanner(input, span.Start, exts)
 
 	tokens := []Token{}
 	for {
@@ -35,18 +36,27 @@ func Scan(src *source.Source, span source.ByteSpan) ([]Token, error) {
 }
 
 // Scanner tokenizes inline source relative to a source-base offset.
+//
+// Extensions determines which extension delimiters are emitted as tokens
+// rather than left as text.
 type Scanner struct {
-	Input    string
-	Position int
-	Base     source.BytePos
+	Input      string
+	Position   int
+	Base       source.BytePos
+	Extensions extension.Set
+
+	// autolinkEnd is the end of the extended autolink containing Position,
+	// if any. Delimiters within it are part of the URL, not openers.
+	autolinkEnd int
 }
 
 // NewScanner constructs a scanner over input whose spans are anchored at base.
-func NewScanner(input string, base source.BytePos) *Scanner {
+func NewScanner(input string, base source.BytePos, exts extension.Set) *Scanner {
 	return &Scanner{
-		Input:    input,
-		Position: 0,
-		Base:     base,
+		Input:      input,
+		Position:   0,
+		Base:       base,
+		Extensions: exts,
 	}
 }
 
@@ -111,17 +121,29 @@ func (s *Scanner) Special() (TokenKind, int, bool) {
 		return 0, 0, false
 	}
 
+	inAutolink := s.inAutolink()
+
 	switch b {
 	case '*':
+		if inAutolink {
+			return 0, 0, false
+		}
 		return TokenStarDelimiter, s.runLength(b), true
 
 	case '_':
+		if inAutolink {
+			return 0, 0, false
+		}
 		return TokenUnderscoreDelimiter, s.runLength(b), true
 
 	case '`':
 		return TokenBacktick, s.runLength(b), true
 
 	case '[':
+		// a wiki link opener inside a URL is part of it
+		if inAutolink && s.inBracketPair() {
+			return 0, 0, false
+		}
 		return TokenOpenBracket, 1, true
 
 	case ']':
@@ -148,6 +170,48 @@ func (s *Scanner) Special() (TokenKind, int, bool) {
 	case '\\':
 		return TokenBackslash, 1, true
 
+	case '$':
+		if !inAutolink && s.Extensions.Has(extension.Math) {
+			return TokenDollar, s.runLength(b), true
+		}
+		return 0, 0, false
+
+	case '^':
+		if !inAutolink && s.Extensions.Has(extension.Superscript) {
+			return TokenCaretDelimiter, s.runLength(b), true
+		}
+		return 0, 0, false
+
+	case '~':
+		if !inAutolink && (s.Extensions.Has(extension.Subscript) || s.Extensions.Has(extension.Strikethrough)) {
+			return TokenTildeDelimiter, s.runLength(b), true
+		}
+		return 0, 0, false
+
+	case '=':
+		if !inAutolink && s.Extensions.Has(extension.Highlight) {
+			return TokenEqualsDelimiter, s.runLength(b), true
+		}
+		return 0, 0, false
+
+	case '+':
+		if !inAutolink && s.Extensions.Has(extension.Insert) {
+			return TokenPlusDelimiter, s.runLength(b), true
+		}
+		return 0, 0, false
+
+	case '{':
+		if s.Extensions.Has(extension.Attributes) {
+			return TokenOpenBrace, 1, true
+		}
+		return 0, 0, false
+
+	case '}':
+		if s.Extensions.Has(extension.Attributes) {
+			return TokenCloseBrace, 1, true
+		}
+		return 0, 0, false
+
 	case '\n':
 		panic("illegal newline character encountered during inline parsing")
 
@@ -157,6 +221,32 @@ func (s *Scanner) Special() (TokenKind, int, bool) {
 
 }
 
+// inAutolink reports whether the current position lies within an extended
+// URL autolink, finding the extent of one that begins here. The extent is
+// taken from the raw input so that delimiters inside the URL do not split
+// it before autolinks are applied.
+func (s *Scanner) inAutolink() bool {
+	if !s.Extensions.Has(extension.Autolinks) {
+		return false
+	}
+
+	if s.Position < s.autolinkEnd {
+		return true
+	}
+
+	if s.Position > 0 && !IsAutolinkBoundary(s.Input[s.Position-1]) {
+		return false
+	}
+
+	end, _, ok := ScanURLAutolink(s.Input, s.Position)
+	if !ok {
+		return false
+	}
+
+	s.autolinkEnd = end
+	return s.Position < s.autolinkEnd
+}
+
 func (s *Scanner) token(kind TokenKind, width int) Token {
 	start := s.Position
 	s.Position += width
@@ -174,6 +264,16 @@ func (s *Scanner) span(start, end int) source.ByteSpan {
 	}
 }
 
+// inBracketPair reports whether the '[' at the current position is one of
+// a pair of adjacent open brackets.
+func (s *Scanner) inBracketPair() bool {
+	if next, ok := s.Peek(); ok && next == '[' {
+		return true
+	}
+
+	return s.Position > 0 && s.Input[s.Position-1] == '['
+}
+
 // runLength returns the length of the delimiter run beginning at the
 // current scanner position.
 func (s *Scanner) runLength(b byte) int {
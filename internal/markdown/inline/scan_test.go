@@ -3,6 +3,7 @@ package inline
 import (
 	"testing"
 
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
 )
@@ -12,6 +13,7 @@ func TestScan(t *testing.T) {
 		name    string
 		input   string
 		span    source.ByteSpan
+		exts    extension.Set
 		want    []TokenSummary
 		wantErr error
 	}{
@@ -629,12 +631,81 @@ func TestScan(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+
+		// Extension delimiters
+
+		{
+			name:  "dollar is text without the math extension",
+			input: "a$b$",
+			span:  source.ByteSpan{Start: 0, End: 4},
+			want: []TokenSummary{
+				{Kind: TokenText, Lexeme: "a$b$"},
+				{Kind: TokenEOF},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "math extension emits dollar runs",
+			input: "a$b$$c",
+			span:  source.ByteSpan{Start: 0, End: 6},
+			exts:  extension.Math,
+			want: []TokenSummary{
+				{Kind: TokenText, Lexeme: "a"},
+				{Kind: TokenDollar, Lexeme: "$"},
+				{Kind: TokenText, Lexeme: "b"},
+				{Kind: TokenDollar, Lexeme: "$$"},
+				{Kind: TokenText, Lexeme: "c"},
+				{Kind: TokenEOF},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "attributes extension emits braces",
+			input: "a{.b}",
+			span:  source.ByteSpan{Start: 0, End: 5},
+			exts:  extension.Attributes,
+			want: []TokenSummary{
+				{Kind: TokenText, Lexeme: "a"},
+				{Kind: TokenOpenBrace, Lexeme: "{"},
+				{Kind: TokenText, Lexeme: ".b"},
+				{Kind: TokenCloseBrace, Lexeme: "}"},
+				{Kind: TokenEOF},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "extended delimiters emit runs",
+			input: "^a~~b==c++",
+			span:  source.ByteSpan{Start: 0, End: 10},
+			exts:  extension.Superscript | extension.Strikethrough | extension.Highlight | extension.Insert,
+			want: []TokenSummary{
+				{Kind: TokenCaretDelimiter, Lexeme: "^"},
+				{Kind: TokenText, Lexeme: "a"},
+				{Kind: TokenTildeDelimiter, Lexeme: "~~"},
+				{Kind: TokenText, Lexeme: "b"},
+				{Kind: TokenEqualsDelimiter, Lexeme: "=="},
+				{Kind: TokenText, Lexeme: "c"},
+				{Kind: TokenPlusDelimiter, Lexeme: "++"},
+				{Kind: TokenEOF},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "extended delimiters are text when disabled",
+			input: "^a~~b==c++",
+			span:  source.ByteSpan{Start: 0, End: 10},
+			want: []TokenSummary{
+				{Kind: TokenText, Lexeme: "^a~~b==c++"},
+				{Kind: TokenEOF},
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			src := source.NewSource(tc.input)
-			tokens, err := Scan(src, tc.span)
+			tokens, err := Scan(src, tc.span, tc.exts)
 			got := summarizeTokens(src, tokens)
 
 			assert.Equal(t, got, tc.want)
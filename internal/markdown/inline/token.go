@@ -22,6 +22,13 @@ const (
 	TokenBang
 	TokenImageOpenBracket
 	TokenBackslash
+	TokenDollar
+	TokenOpenBrace
+	TokenCloseBrace
+	TokenCaretDelimiter
+	TokenTildeDelimiter
+	TokenEqualsDelimiter
+	TokenPlusDelimiter
 	TokenEOF
 )
 
@@ -12,8 +12,9 @@ type Block interface {
 }
 
 type BlockQuote struct {
-	Span     source.ByteSpan
-	Children []Block
+	Span       source.ByteSpan
+	Children   []Block
+	Attributes []source.ByteSpan
 }
 
 func (BlockQuote) isBlock() {}
@@ -31,6 +32,7 @@ type Header struct {
 	ContentSpan  source.ByteSpan
 	ContentLines []source.ByteSpan
 	Level        int
+	Attributes   []source.ByteSpan
 }
 
 func (Header) isBlock() {}
@@ -43,7 +45,8 @@ func (h Header) String() string {
 }
 
 type ThematicBreak struct {
-	Span source.ByteSpan
+	Span       source.ByteSpan
+	Attributes []source.ByteSpan
 }
 
 func (ThematicBreak) isBlock() {}
@@ -53,10 +56,11 @@ func (tb ThematicBreak) String() string {
 }
 
 type OrderedList struct {
-	Span  source.ByteSpan
-	Items []ListItem
-	Tight bool
-	Start int
+	Span       source.ByteSpan
+	Items      []ListItem
+	Tight      bool
+	Start      int
+	Attributes []source.ByteSpan
 }
 
 func (OrderedList) isBlock() {}
@@ -66,9 +70,10 @@ func (ol OrderedList) String() string {
 }
 
 type UnorderedList struct {
-	Span  source.ByteSpan
-	Items []ListItem
-	Tight bool
+	Span       source.ByteSpan
+	Items      []ListItem
+	Tight      bool
+	Attributes []source.ByteSpan
 }
 
 func (UnorderedList) isBlock() {}
@@ -89,9 +94,50 @@ func (li ListItem) String() string {
 	return fmt.Sprintf("[ListItem] (Children = %d)", len(li.Children))
 }
 
+// DefinitionList represents a definition list of terms and their
+// definitions.
+type DefinitionList struct {
+	Span       source.ByteSpan
+	Items      []DefinitionItem
+	Tight      bool
+	Attributes []source.ByteSpan
+}
+
+func (DefinitionList) isBlock() {}
+
+func (dl DefinitionList) String() string {
+	return fmt.Sprintf("[DefinitionList] (Items = %d)", len(dl.Items))
+}
+
+// DefinitionItem groups one or more terms with the definitions that follow
+// them.
+//
+// Terms holds the content span of each term line, trimmed of surrounding
+// whitespace.
+type DefinitionItem struct {
+	Span        source.ByteSpan
+	Terms       []source.ByteSpan
+	Definitions []Definition
+}
+
+func (di DefinitionItem) String() string {
+	return fmt.Sprintf("[DefinitionItem] (Terms = %d, Definitions = %d)", len(di.Terms), len(di.Definitions))
+}
+
+// Definition represents a single definition within a definition item.
+type Definition struct {
+	Span     source.ByteSpan
+	Children []Block
+}
+
+func (d Definition) String() string {
+	return fmt.Sprintf("[Definition] (Children = %d)", len(d.Children))
+}
+
 type IndentedCodeBlock struct {
-	Span  source.ByteSpan
-	Lines []source.ByteSpan
+	Span       source.ByteSpan
+	Lines      []source.ByteSpan
+	Attributes []source.ByteSpan
 }
 
 func (IndentedCodeBlock) isBlock() {}
@@ -109,6 +155,7 @@ type FencedCodeBlock struct {
 	OpenIndentCols int
 	InfoStringSpan source.ByteSpan
 	Lines          []source.ByteSpan
+	Attributes     []source.ByteSpan
 }
 
 func (FencedCodeBlock) isBlock() {}
@@ -128,9 +175,50 @@ func (hb HTMLBlock) String() string {
 	return fmt.Sprintf("[HTMLBlock] (Lines = %d)", len(hb.Lines))
 }
 
+// MathBlock represents a display math block delimited by $$ fences.
+//
+// Lines holds the TeX payload lines between the fences.
+type MathBlock struct {
+	Span       source.ByteSpan
+	Lines      []source.ByteSpan
+	Attributes []source.ByteSpan
+}
+
+func (MathBlock) isBlock() {}
+
+func (mb MathBlock) String() string {
+	return fmt.Sprintf("[MathBlock] (Lines = %d)", len(mb.Lines))
+}
+
+// LineBlock represents a run of "| " prefixed lines whose line structure is
+// preserved.
+type LineBlock struct {
+	Span       source.ByteSpan
+	Lines      []LineBlockLine
+	Attributes []source.ByteSpan
+}
+
+func (LineBlock) isBlock() {}
+
+func (lb LineBlock) String() string {
+	return fmt.Sprintf("[LineBlock] (Lines = %d)", len(lb.Lines))
+}
+
+// LineBlockLine is a single line of a line block.
+//
+// Indent counts the spaces between the "| " marker and the content. Spans
+// holds the content of the line followed by any continuation lines joined
+// to it, each trimmed of surrounding whitespace; it is empty for a blank
+// line.
+type LineBlockLine struct {
+	Indent int
+	Spans  []source.ByteSpan
+}
+
 type Paragraph struct {
-	Span  source.ByteSpan
-	Lines []source.ByteSpan
+	Span       source.ByteSpan
+	Lines      []source.ByteSpan
+	Attributes []source.ByteSpan
 }
 
 func (Paragraph) isBlock() {}
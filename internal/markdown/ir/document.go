@@ -4,9 +4,10 @@ import "github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 
 // Document is the root IR node produced by block parsing.
 type Document struct {
-	Source      *source.Source
-	Blocks      []Block
-	Definitions map[string]ReferenceDefinition
+	Source        *source.Source
+	Blocks        []Block
+	Definitions   map[string]ReferenceDefinition
+	Abbreviations map[string]AbbreviationDefinition
 }
 
 // ReferenceDefinition records a parsed link or image reference definition
@@ -19,3 +20,16 @@ type ReferenceDefinition struct {
 	HasTitle        bool
 	NormalizedKey   string
 }
+
+// AbbreviationDefinition records a parsed *[TERM]: title abbreviation
+// definition in source-oriented form.
+//
+// Term is the abbreviation exactly as written, which is matched
+// case-sensitively. TitleSpan is empty when the definition supplies no
+// expansion.
+type AbbreviationDefinition struct {
+	FullSpan  source.ByteSpan
+	TermSpan  source.ByteSpan
+	TitleSpan source.ByteSpan
+	Term      string
+}
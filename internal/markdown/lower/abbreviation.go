@@ -0,0 +1,198 @@
+package lower
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// applyAbbreviations wraps whole-word occurrences of each defined
+// abbreviation in text.
+//
+// Adjacent text nodes are scanned as a single run, so a term is matched
+// even where the inline parser split the text. Code spans, raw HTML, and
+// the contents of links and images are never scanned. Where terms overlap,
+// the longest match wins.
+func applyAbbreviations(src *source.Source, blocks []ast.Block, defs map[string]ir.AbbreviationDefinition) []ast.Block {
+	terms := make([]ir.AbbreviationDefinition, 0, len(defs))
+	for _, def := range defs {
+		terms = append(terms, def)
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i].Term) != len(terms[j].Term) {
+			return len(terms[i].Term) > len(terms[j].Term)
+		}
+		return terms[i].Term < terms[j].Term
+	})
+
+	return rewriteInlines(blocks, func(inlines []ast.Inline) []ast.Inline {
+		return abbreviateInlines(src, inlines, terms)
+	})
+}
+
+func abbreviateInlines(src *source.Source, inlines []ast.Inline, terms []ir.AbbreviationDefinition) []ast.Inline {
+	out := make([]ast.Inline, 0, len(inlines))
+
+	for i := 0; i < len(inlines); i++ {
+		switch v := inlines[i].(type) {
+		case ast.Text:
+			// gather the run of text nodes contiguous in the source
+			j := i + 1
+			for j < len(inlines) {
+				next, ok := inlines[j].(ast.Text)
+				if !ok || next.Span.Start != inlines[j-1].(ast.Text).Span.End {
+					break
+				}
+				j++
+			}
+
+			run := source.ByteSpan{
+				Start: v.Span.Start,
+				End:   inlines[j-1].(ast.Text).Span.End,
+			}
+
+			if wrapped, ok := abbreviateText(src, run, terms); ok {
+				out = append(out, wrapped...)
+			} else {
+				out = append(out, inlines[i:j]...)
+			}
+
+			i = j - 1
+
+		case ast.Emph:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Strong:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.BracketedSpan:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Superscript:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Subscript:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Strikethrough:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Highlight:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		case ast.Insert:
+			v.Children = abbreviateInlines(src, v.Children, terms)
+			out = append(out, v)
+
+		default:
+			// links and images keep their labels as written
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// abbreviateText splits the text covered by span around any whole-word
+// abbreviations it contains, reporting false when there are none.
+//
+// Word boundaries are judged within the run, whose start and end are
+// boundaries themselves: the source beyond it may be a delimiter, such as
+// the _ of emphasis, that is not part of the text.
+func abbreviateText(src *source.Source, span source.ByteSpan, terms []ir.AbbreviationDefinition) ([]ast.Inline, bool) {
+	s := src.Slice(span)
+	base := span.Start
+
+	out := []ast.Inline{}
+	segStart := 0
+	pos := 0
+
+	for pos < len(s) {
+		start := base + source.BytePos(pos)
+		if isWordRune(runeBefore(s, pos)) {
+			pos++
+			continue
+		}
+
+		matched := false
+		for _, def := range terms {
+			if !strings.HasPrefix(s[pos:], def.Term) {
+				continue
+			}
+
+			end := start + source.BytePos(len(def.Term))
+			if isWordRune(runeAt(s, pos+len(def.Term))) {
+				continue
+			}
+
+			if segStart < pos {
+				out = append(out, ast.Text{
+					Span: source.ByteSpan{
+						Start: base + source.BytePos(segStart),
+						End:   start,
+					},
+				})
+			}
+
+			out = append(out, ast.Abbreviation{
+				Span:  source.ByteSpan{Start: start, End: end},
+				Title: def.TitleSpan,
+			})
+
+			pos += len(def.Term)
+			segStart = pos
+			matched = true
+			break
+		}
+
+		if !matched {
+			pos++
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, false
+	}
+
+	if segStart < len(s) {
+		out = append(out, ast.Text{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(segStart),
+				End:   span.End,
+			},
+		})
+	}
+
+	return out, true
+}
+
+// runeBefore returns the rune ending at pos in s, or utf8.RuneError at the
+// start of s.
+func runeBefore(s string, pos int) rune {
+	r, _ := utf8.DecodeLastRuneInString(s[:pos])
+	return r
+}
+
+// runeAt returns the rune beginning at pos in s, or utf8.RuneError at the
+// end of s.
+func runeAt(s string, pos int) rune {
+	r, _ := utf8.DecodeRuneInString(s[pos:])
+	return r
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
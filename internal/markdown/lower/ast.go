@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/attribute"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/block"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/inline"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
@@ -13,15 +15,22 @@ import (
 
 // Context carries shared lowering state used while converting IR to AST.
 type Context struct {
-	Source      *source.Source
-	Definitions map[string]ir.ReferenceDefinition
+	Source        *source.Source
+	Definitions   map[string]ir.ReferenceDefinition
+	Abbreviations map[string]ir.AbbreviationDefinition
+	Extensions    extension.Set
 }
 
 // Document lowers an IR document into its AST form.
-func Document(irDoc ir.Document) (ast.Document, error) {
+//
+// Lowering passes belonging to enabled extensions run after the AST has
+// been fully constructed.
+func Document(irDoc ir.Document, exts extension.Set) (ast.Document, error) {
 	ctx := &Context{
-		Source:      irDoc.Source,
-		Definitions: irDoc.Definitions,
+		Source:        irDoc.Source,
+		Definitions:   irDoc.Definitions,
+		Abbreviations: irDoc.Abbreviations,
+		Extensions:    exts,
 	}
 
 	astDoc := ast.Document{
@@ -38,6 +47,27 @@ func Document(irDoc ir.Document) (ast.Document, error) {
 		astDoc.Blocks = append(astDoc.Blocks, block)
 	}
 
+	if ctx.Extensions.Has(extension.Callouts) {
+		astDoc.Blocks = levelCallouts(astDoc.Blocks, 1)
+	}
+
+	if ctx.Extensions.Has(extension.Autolinks) {
+		astDoc.Blocks = applyAutolinks(ctx.Source, astDoc.Blocks)
+	}
+
+	if ctx.Extensions.Has(extension.Emoji) {
+		labeled := ctx.Extensions.Has(extension.EmojiLabels)
+		astDoc.Blocks = applyEmoji(ctx.Source, astDoc.Blocks, labeled)
+	}
+
+	if ctx.Extensions.Has(extension.Abbreviations) && len(ctx.Abbreviations) > 0 {
+		astDoc.Blocks = applyAbbreviations(ctx.Source, astDoc.Blocks, ctx.Abbreviations)
+	}
+
+	if ctx.Extensions.Has(extension.Typography) {
+		astDoc.Blocks = applyTypography(ctx.Source, astDoc.Blocks)
+	}
+
 	return astDoc, nil
 }
 
@@ -50,7 +80,7 @@ func buildBlock(ctx *Context, block ir.Block) (ast.Block, error) {
 		return buildHeader(ctx, v)
 
 	case ir.ThematicBreak:
-		return buildThematicBreak(v)
+		return buildThematicBreak(ctx, v)
 
 	case ir.OrderedList:
 		return buildOrderedList(ctx, v)
@@ -61,6 +91,9 @@ func buildBlock(ctx *Context, block ir.Block) (ast.Block, error) {
 	case ir.ListItem:
 		return buildListItem(ctx, v)
 
+	case ir.DefinitionList:
+		return buildDefinitionList(ctx, v)
+
 	case ir.IndentedCodeBlock:
 		return buildIndentedCodeBlock(ctx, v)
 
@@ -70,6 +103,12 @@ func buildBlock(ctx *Context, block ir.Block) (ast.Block, error) {
 	case ir.HTMLBlock:
 		return buildHTMLBlock(v)
 
+	case ir.MathBlock:
+		return buildMathBlock(ctx, v)
+
+	case ir.LineBlock:
+		return buildLineBlock(ctx, v)
+
 	case ir.Paragraph:
 		return buildParagraph(ctx, v)
 
@@ -79,6 +118,16 @@ func buildBlock(ctx *Context, block ir.Block) (ast.Block, error) {
 }
 
 func buildBlockQuote(ctx *Context, bq ir.BlockQuote) (ast.Block, error) {
+	if ctx.Extensions.Has(extension.Callouts) {
+		callout, ok, err := buildCallout(ctx, bq)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return callout, nil
+		}
+	}
+
 	astChildren := make([]ast.Block, 0, len(bq.Children))
 
 	for _, bqChild := range bq.Children {
@@ -90,9 +139,15 @@ func buildBlockQuote(ctx *Context, bq ir.BlockQuote) (ast.Block, error) {
 		astChildren = append(astChildren, astChild)
 	}
 
+	attrs, err := lowerAttributes(ctx, bq.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.BlockQuote{
-		Span:     bq.Span,
-		Children: astChildren,
+		Span:       bq.Span,
+		Children:   astChildren,
+		Attributes: attrs,
 	}
 
 	return block, nil
@@ -104,18 +159,30 @@ func buildHeader(ctx *Context, h ir.Header) (ast.Block, error) {
 		return nil, err
 	}
 
+	attrs, err := lowerAttributes(ctx, h.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.Header{
-		Span:    h.Span,
-		Level:   h.Level,
-		Inlines: inlines,
+		Span:       h.Span,
+		Level:      h.Level,
+		Inlines:    inlines,
+		Attributes: attrs,
 	}
 
 	return block, nil
 }
 
-func buildThematicBreak(tb ir.ThematicBreak) (ast.Block, error) {
+func buildThematicBreak(ctx *Context, tb ir.ThematicBreak) (ast.Block, error) {
+	attrs, err := lowerAttributes(ctx, tb.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.ThematicBreak{
-		Span: tb.Span,
+		Span:       tb.Span,
+		Attributes: attrs,
 	}
 
 	return block, nil
@@ -138,10 +205,16 @@ func buildUnorderedList(ctx *Context, ul ir.UnorderedList) (ast.Block, error) {
 		astItems = append(astItems, astItem)
 	}
 
+	attrs, err := lowerAttributes(ctx, ul.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.UnorderedList{
-		Span:  ul.Span,
-		Items: astItems,
-		Tight: ul.Tight,
+		Span:       ul.Span,
+		Items:      astItems,
+		Tight:      ul.Tight,
+		Attributes: attrs,
 	}
 
 	return block, nil
@@ -165,11 +238,17 @@ func buildOrderedList(ctx *Context, ol ir.OrderedList) (ast.Block, error) {
 		astItems = append(astItems, astItem)
 	}
 
+	attrs, err := lowerAttributes(ctx, ol.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.OrderedList{
-		Span:  ol.Span,
-		Items: astItems,
-		Tight: ol.Tight,
-		Start: ol.Start,
+		Span:       ol.Span,
+		Items:      astItems,
+		Tight:      ol.Tight,
+		Start:      ol.Start,
+		Attributes: attrs,
 	}
 
 	return block, nil
@@ -195,14 +274,77 @@ func buildListItem(ctx *Context, li ir.ListItem) (ast.Block, error) {
 	return block, nil
 }
 
+func buildDefinitionList(ctx *Context, dl ir.DefinitionList) (ast.Block, error) {
+	astItems := make([]ast.DefinitionItem, 0, len(dl.Items))
+
+	for _, dlItem := range dl.Items {
+		terms := make([]ast.DefinitionTerm, 0, len(dlItem.Terms))
+		for _, termSpan := range dlItem.Terms {
+			inlines, err := inline.Parse(ctx.Source, ctx.Definitions, termSpan, ctx.Extensions)
+			if err != nil {
+				return nil, err
+			}
+
+			terms = append(terms, ast.DefinitionTerm{
+				Span:    termSpan,
+				Inlines: inlines,
+			})
+		}
+
+		definitions := make([]ast.Definition, 0, len(dlItem.Definitions))
+		for _, def := range dlItem.Definitions {
+			children := make([]ast.Block, 0, len(def.Children))
+			for _, defChild := range def.Children {
+				astChild, err := buildBlock(ctx, defChild)
+				if err != nil {
+					return nil, err
+				}
+
+				children = append(children, astChild)
+			}
+
+			definitions = append(definitions, ast.Definition{
+				Span:     def.Span,
+				Children: children,
+			})
+		}
+
+		astItems = append(astItems, ast.DefinitionItem{
+			Span:        dlItem.Span,
+			Terms:       terms,
+			Definitions: definitions,
+		})
+	}
+
+	attrs, err := lowerAttributes(ctx, dl.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	block := ast.DefinitionList{
+		Span:       dl.Span,
+		Items:      astItems,
+		Tight:      dl.Tight,
+		Attributes: attrs,
+	}
+
+	return block, nil
+}
+
 func buildIndentedCodeBlock(ctx *Context, cb ir.IndentedCodeBlock) (ast.Block, error) {
 	payload := normalizeCodeBlockPayload(ctx.Source, cb.Lines, block.MinValidCodeBlockIndentation)
 
+	attrs, err := lowerAttributes(ctx, cb.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.CodeBlock{
 		Span:              cb.Span,
 		Kind:              ast.Indented,
 		LanguageTokenSpan: source.ByteSpan{},
 		Payload:           payload,
+		Attributes:        attrs,
 	}
 
 	return block, nil
@@ -212,11 +354,17 @@ func buildFencedCodeBlock(ctx *Context, cb ir.FencedCodeBlock) (ast.Block, error
 	payload := normalizeCodeBlockPayload(ctx.Source, cb.Lines, cb.OpenIndentCols)
 	languageString := extractLanguageString(ctx.Source, cb.InfoStringSpan)
 
+	attrs, err := lowerAttributes(ctx, cb.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.CodeBlock{
 		Span:              cb.Span,
 		Kind:              ast.Fenced,
 		LanguageTokenSpan: languageString,
 		Payload:           payload,
+		Attributes:        attrs,
 	}
 
 	return block, nil
@@ -323,15 +471,70 @@ func buildHTMLBlock(hb ir.HTMLBlock) (ast.Block, error) {
 	return block, nil
 }
 
+func buildMathBlock(ctx *Context, mb ir.MathBlock) (ast.Block, error) {
+	attrs, err := lowerAttributes(ctx, mb.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	block := ast.MathBlock{
+		Span:       mb.Span,
+		Lines:      mb.Lines,
+		Attributes: attrs,
+	}
+
+	return block, nil
+}
+
+// buildLineBlock lowers each line of a line block. Continuation lines are
+// joined to their line by soft breaks, even when HardBreaks is enabled,
+// since the line block itself marks where lines end.
+func buildLineBlock(ctx *Context, lb ir.LineBlock) (ast.Block, error) {
+	joined := *ctx
+	joined.Extensions = joined.Extensions.Without(extension.HardBreaks)
+
+	lines := make([]ast.Line, 0, len(lb.Lines))
+	for _, l := range lb.Lines {
+		inlines, err := lowerLineSpans(&joined, l.Spans)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, ast.Line{
+			Indent:  l.Indent,
+			Inlines: inlines,
+		})
+	}
+
+	attrs, err := lowerAttributes(ctx, lb.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	block := ast.LineBlock{
+		Span:       lb.Span,
+		Lines:      lines,
+		Attributes: attrs,
+	}
+
+	return block, nil
+}
+
 func buildParagraph(ctx *Context, p ir.Paragraph) (ast.Block, error) {
 	inlines, err := lowerLineSpans(ctx, p.Lines)
 	if err != nil {
 		return nil, err
 	}
 
+	attrs, err := lowerAttributes(ctx, p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	block := ast.Paragraph{
-		Span:    p.Span,
-		Inlines: inlines,
+		Span:       p.Span,
+		Inlines:    inlines,
+		Attributes: attrs,
 	}
 
 	return block, nil
@@ -339,7 +542,8 @@ func buildParagraph(ctx *Context, p ir.Paragraph) (ast.Block, error) {
 
 // lowerLineSpans parses inline content across multiple source lines,
 // converting inter-line boundaries into soft or hard breaks according to
-// Markdown paragraph break rules.
+// Markdown paragraph break rules. Every boundary becomes a hard break when
+// HardBreaks is enabled.
 func lowerLineSpans(ctx *Context, spans []source.ByteSpan) ([]ast.Inline, error) {
 	if len(spans) == 0 {
 		return []ast.Inline{}, nil
@@ -363,7 +567,7 @@ func lowerLineSpans(ctx *Context, spans []source.ByteSpan) ([]ast.Inline, error)
 			}
 		}
 
-		lineInlines, err := inline.Parse(ctx.Source, ctx.Definitions, ps)
+		lineInlines, err := inline.Parse(ctx.Source, ctx.Definitions, ps, ctx.Extensions)
 		if err != nil {
 			return nil, err
 		}
@@ -376,7 +580,7 @@ func lowerLineSpans(ctx *Context, spans []source.ByteSpan) ([]ast.Inline, error)
 				End:   ls.End,
 			}
 
-			if hardBreak {
+			if hardBreak || ctx.Extensions.Has(extension.HardBreaks) {
 				inlines = append(inlines, ast.HardBreak{Span: anchor})
 			} else {
 				inlines = append(inlines, ast.SoftBreak{Span: anchor})
@@ -386,3 +590,23 @@ func lowerLineSpans(ctx *Context, spans []source.ByteSpan) ([]ast.Inline, error)
 
 	return inlines, nil
 }
+
+// lowerAttributes parses and merges the attribute lists covered by spans,
+// returning nil when there are none.
+func lowerAttributes(ctx *Context, spans []source.ByteSpan) (ast.Attributes, error) {
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	attrs := ast.Attributes{}
+	for _, span := range spans {
+		parsed, err := attribute.Parse(ctx.Source, span)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs.Merge(parsed)
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,240 @@
+package lower
+
+import (
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/inline"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// applyAutolinks turns bare URLs, www. addresses, and email addresses found
+// in text into links, following the GFM extended autolink rules.
+//
+// Adjacent text nodes are scanned as a single run, since the inline parser
+// splits text at every potential delimiter. Code spans, raw HTML, and the
+// contents of existing links and images are never scanned.
+func applyAutolinks(src *source.Source, blocks []ast.Block) []ast.Block {
+	return rewriteInlines(blocks, func(inlines []ast.Inline) []ast.Inline {
+		return linkifyInlines(src, inlines)
+	})
+}
+
+func linkifyInlines(src *source.Source, inlines []ast.Inline) []ast.Inline {
+	out := make([]ast.Inline, 0, len(inlines))
+
+	for i := 0; i < len(inlines); i++ {
+		switch v := inlines[i].(type) {
+		case ast.Text:
+			// gather the run of text nodes contiguous in the source
+			j := i + 1
+			for j < len(inlines) {
+				next, ok := inlines[j].(ast.Text)
+				if !ok || next.Span.Start != inlines[j-1].(ast.Text).Span.End {
+					break
+				}
+				j++
+			}
+
+			run := source.ByteSpan{
+				Start: v.Span.Start,
+				End:   inlines[j-1].(ast.Text).Span.End,
+			}
+
+			if linked, ok := linkifyText(src, run); ok {
+				out = append(out, linked...)
+			} else {
+				out = append(out, inlines[i:j]...)
+			}
+
+			i = j - 1
+
+		case ast.Emph:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Strong:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.BracketedSpan:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Superscript:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Subscript:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Strikethrough:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Highlight:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Insert:
+			v.Children = linkifyInlines(src, v.Children)
+			out = append(out, v)
+
+		default:
+			// links and images already link, and their labels must not
+			// nest further links
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// linkifyText splits the text covered by span around any extended
+// autolinks it contains, reporting false when there are none.
+func linkifyText(src *source.Source, span source.ByteSpan) ([]ast.Inline, bool) {
+	s := src.Slice(span)
+	base := span.Start
+
+	out := []ast.Inline{}
+	segStart := 0
+	pos := 0
+
+	emit := func(start, end int, link ast.Link) {
+		if segStart < start {
+			out = append(out, ast.Text{
+				Span: source.ByteSpan{
+					Start: base + source.BytePos(segStart),
+					End:   base + source.BytePos(start),
+				},
+			})
+		}
+
+		out = append(out, link)
+		segStart = end
+	}
+
+	for pos < len(s) {
+		if atAutolinkBoundary(src, s, base, pos) {
+			if end, www, ok := inline.ScanURLAutolink(s, pos); ok {
+				linkSpan := source.ByteSpan{
+					Start: base + source.BytePos(pos),
+					End:   base + source.BytePos(end),
+				}
+
+				emit(pos, end, autolink(linkSpan, false, www))
+				pos = end
+				continue
+			}
+		}
+
+		if s[pos] == '@' {
+			if start, end, ok := scanEmailAutolink(s, segStart, pos); ok {
+				linkSpan := source.ByteSpan{
+					Start: base + source.BytePos(start),
+					End:   base + source.BytePos(end),
+				}
+
+				emit(start, end, autolink(linkSpan, true, false))
+				pos = end
+				continue
+			}
+		}
+
+		pos++
+	}
+
+	if len(out) == 0 {
+		return nil, false
+	}
+
+	if segStart < len(s) {
+		out = append(out, ast.Text{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(segStart),
+				End:   span.End,
+			},
+		})
+	}
+
+	return out, true
+}
+
+func autolink(span source.ByteSpan, mailTo, www bool) ast.Link {
+	return ast.Link{
+		Span:        span,
+		Destination: span,
+		MailTo:      mailTo,
+		WWW:         www,
+		Autolink:    true,
+		Children: []ast.Inline{
+			ast.Text{Span: span},
+		},
+	}
+}
+
+// atAutolinkBoundary reports whether an extended URL autolink may begin at
+// pos: at the start of a line, or after whitespace or one of the delimiter
+// characters *, _, ~, and (.
+//
+// The byte before the run is read from the source, since the run may follow
+// other inline content.
+func atAutolinkBoundary(src *source.Source, s string, base source.BytePos, pos int) bool {
+	var before byte
+	switch {
+	case pos > 0:
+		before = s[pos-1]
+	case base > 0:
+		before = src.Slice(source.ByteSpan{Start: base - 1, End: base})[0]
+	default:
+		return true
+	}
+
+	return inline.IsAutolinkBoundary(before)
+}
+
+// scanEmailAutolink reports whether the '@' at at belongs to an email
+// address and returns its bounds. The local part extends backward from at
+// but never before floor.
+//
+// The local part holds letters, digits, and the characters . + - _. The
+// domain holds letters, digits, and the characters - _ separated by
+// periods, contains at least one period, and may not end in - or _.
+func scanEmailAutolink(s string, floor, at int) (int, int, bool) {
+	start := at
+	for start > floor && inline.IsEmailLocalByte(s[start-1]) {
+		start--
+	}
+
+	if start == at {
+		return 0, 0, false
+	}
+
+	end := at + 1
+	for end < len(s) && (inline.IsDomainByte(s[end]) || s[end] == '.') {
+		end++
+	}
+
+	for end > at+1 && s[end-1] == '.' {
+		end--
+	}
+
+	domain := s[at+1 : end]
+	segments := strings.Split(domain, ".")
+	if len(segments) < 2 {
+		return 0, 0, false
+	}
+
+	for _, seg := range segments {
+		if seg == "" {
+			return 0, 0, false
+		}
+	}
+
+	if last := domain[len(domain)-1]; last == '-' || last == '_' {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
@@ -0,0 +1,212 @@
+package lower
+
+import (
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/inline"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ir"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// calloutMarker describes a [!KIND] marker found on the first line of a
+// block quote.
+//
+// TitleSpan is empty when no text follows the marker on its line.
+type calloutMarker struct {
+	Kind      string
+	TitleSpan source.ByteSpan
+}
+
+// buildCallout lowers bq into a callout when its first line carries a
+// recognized marker.
+//
+// Standard kinds are recognized with or without a title. Any other kind is
+// recognized only when it supplies a title, so that unknown markers without
+// one remain ordinary block quotes. The marker line is removed from the
+// leading paragraph, and the paragraph is dropped when nothing else remains.
+func buildCallout(ctx *Context, bq ir.BlockQuote) (ast.Block, bool, error) {
+	if len(bq.Children) == 0 {
+		return nil, false, nil
+	}
+
+	p, ok := bq.Children[0].(ir.Paragraph)
+	if !ok || len(p.Lines) == 0 {
+		return nil, false, nil
+	}
+
+	marker, ok := parseCalloutMarker(ctx.Source, p.Lines[0])
+	if !ok {
+		return nil, false, nil
+	}
+
+	_, standard := ast.CalloutKinds[marker.Kind]
+	if !standard && marker.TitleSpan.End == marker.TitleSpan.Start {
+		return nil, false, nil
+	}
+
+	title := []ast.Inline{}
+	if marker.TitleSpan.End > marker.TitleSpan.Start {
+		inlines, err := inline.Parse(ctx.Source, ctx.Definitions, marker.TitleSpan, ctx.Extensions)
+		if err != nil {
+			return nil, false, err
+		}
+
+		title = inlines
+	}
+
+	rest := make([]ir.Block, 0, len(bq.Children))
+	if len(p.Lines) > 1 {
+		rest = append(rest, ir.Paragraph{
+			Span:       source.ByteSpan{Start: p.Lines[1].Start, End: p.Span.End},
+			Lines:      p.Lines[1:],
+			Attributes: p.Attributes,
+		})
+	}
+	rest = append(rest, bq.Children[1:]...)
+
+	children := make([]ast.Block, 0, len(rest))
+	for _, child := range rest {
+		astChild, err := buildBlock(ctx, child)
+		if err != nil {
+			return nil, false, err
+		}
+
+		children = append(children, astChild)
+	}
+
+	attrs, err := lowerAttributes(ctx, bq.Attributes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	block := ast.Callout{
+		Span:       bq.Span,
+		Kind:       marker.Kind,
+		Title:      title,
+		Children:   children,
+		Attributes: attrs,
+	}
+
+	return block, true, nil
+}
+
+// levelCallouts sets TitleLevel on every callout in blocks, whose enclosing
+// section has the given level until a heading among them opens another.
+// The document is taken to sit beneath a level-1 title of its own.
+//
+// A callout's title is one level below its section, and the callout's own
+// body forms a section at the title's level.
+func levelCallouts(blocks []ast.Block, section int) []ast.Block {
+	out := make([]ast.Block, 0, len(blocks))
+
+	for _, blk := range blocks {
+		switch v := blk.(type) {
+		case ast.Header:
+			section = v.Level
+
+		case ast.Callout:
+			v.TitleLevel = min(section+1, 6)
+			v.Children = levelCallouts(v.Children, v.TitleLevel)
+			blk = v
+
+		case ast.BlockQuote:
+			v.Children = levelCallouts(v.Children, section)
+			blk = v
+
+		case ast.OrderedList:
+			v.Items = levelListItemCallouts(v.Items, section)
+			blk = v
+
+		case ast.UnorderedList:
+			v.Items = levelListItemCallouts(v.Items, section)
+			blk = v
+
+		case ast.DefinitionList:
+			for i, item := range v.Items {
+				for j, def := range item.Definitions {
+					def.Children = levelCallouts(def.Children, section)
+					item.Definitions[j] = def
+				}
+				v.Items[i] = item
+			}
+			blk = v
+		}
+
+		out = append(out, blk)
+	}
+
+	return out
+}
+
+func levelListItemCallouts(items []ast.ListItem, section int) []ast.ListItem {
+	out := make([]ast.ListItem, 0, len(items))
+
+	for _, item := range items {
+		item.Children = levelCallouts(item.Children, section)
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// parseCalloutMarker recognizes a line of the form "[!KIND] title".
+//
+// KIND is an ASCII letter followed by letters, digits, or hyphens, and is
+// matched case-insensitively. The marker must be followed by whitespace or
+// the end of the line.
+func parseCalloutMarker(src *source.Source, line source.ByteSpan) (calloutMarker, bool) {
+	s := src.Slice(line)
+
+	pos := 0
+	for pos < len(s) && (s[pos] == ' ' || s[pos] == '\t') {
+		pos++
+	}
+
+	if !strings.HasPrefix(s[pos:], "[!") {
+		return calloutMarker{}, false
+	}
+	pos += 2
+
+	kindStart := pos
+	for pos < len(s) && isCalloutKindByte(s[pos], pos == kindStart) {
+		pos++
+	}
+
+	if pos == kindStart || pos >= len(s) || s[pos] != ']' {
+		return calloutMarker{}, false
+	}
+
+	kind := strings.ToLower(s[kindStart:pos])
+	pos++
+
+	if pos < len(s) && s[pos] != ' ' && s[pos] != '\t' {
+		return calloutMarker{}, false
+	}
+
+	title := strings.TrimSpace(s[pos:])
+	titleStart := pos + strings.Index(s[pos:], title)
+
+	marker := calloutMarker{
+		Kind: kind,
+		TitleSpan: source.ByteSpan{
+			Start: line.Start + source.BytePos(titleStart),
+			End:   line.Start + source.BytePos(titleStart+len(title)),
+		},
+	}
+
+	return marker, true
+}
+
+func isCalloutKindByte(b byte, first bool) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z':
+		return true
+	case first:
+		return false
+	case b >= '0' && b <= '9', b == '-':
+		return true
+	default:
+		return false
+	}
+}
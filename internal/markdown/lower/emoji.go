@@ -0,0 +1,216 @@
+package lower
+
+import (
+	"strings"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/emoji"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/inline"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// applyEmoji replaces :shortcode: names in text with the emoji they name.
+// Unknown names are left as written.
+//
+// Like autolinking, adjacent text nodes are scanned as a single run. Code
+// spans, raw HTML, and autolinks are never scanned, and a shortcode must
+// stand apart from the surrounding word, so times such as 10:30:00 and
+// URLs are left alone.
+func applyEmoji(src *source.Source, blocks []ast.Block, labeled bool) []ast.Block {
+	return rewriteInlines(blocks, func(inlines []ast.Inline) []ast.Inline {
+		return emojifyInlines(src, inlines, labeled)
+	})
+}
+
+func emojifyInlines(src *source.Source, inlines []ast.Inline, labeled bool) []ast.Inline {
+	out := make([]ast.Inline, 0, len(inlines))
+
+	for i := 0; i < len(inlines); i++ {
+		switch v := inlines[i].(type) {
+		case ast.Text:
+			// gather the run of text nodes contiguous in the source
+			j := i + 1
+			for j < len(inlines) {
+				next, ok := inlines[j].(ast.Text)
+				if !ok || next.Span.Start != inlines[j-1].(ast.Text).Span.End {
+					break
+				}
+				j++
+			}
+
+			run := source.ByteSpan{
+				Start: v.Span.Start,
+				End:   inlines[j-1].(ast.Text).Span.End,
+			}
+
+			if replaced, ok := emojifyText(src, run, labeled); ok {
+				out = append(out, replaced...)
+			} else {
+				out = append(out, inlines[i:j]...)
+			}
+
+			i = j - 1
+
+		case ast.Emph:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Strong:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Link:
+			if !v.Autolink {
+				v.Children = emojifyInlines(src, v.Children, labeled)
+			}
+			out = append(out, v)
+
+		case ast.Image:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.BracketedSpan:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Superscript:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Subscript:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Strikethrough:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Highlight:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		case ast.Insert:
+			v.Children = emojifyInlines(src, v.Children, labeled)
+			out = append(out, v)
+
+		default:
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// emojifyText splits the text covered by span around any known shortcodes
+// it contains, reporting false when there are none.
+func emojifyText(src *source.Source, span source.ByteSpan, labeled bool) ([]ast.Inline, bool) {
+	s := src.Slice(span)
+	base := span.Start
+
+	out := []ast.Inline{}
+	segStart := 0
+	lastEnd := -1
+	pos := 0
+
+	for pos < len(s) {
+		if s[pos] != ':' || (pos != lastEnd && !atShortcodeBoundary(src, s, base, pos)) {
+			pos++
+			continue
+		}
+
+		end, ok := scanShortcode(s, pos)
+		if !ok {
+			pos++
+			continue
+		}
+
+		e, ok := emoji.Lookup(s[pos+1 : end-1])
+		if !ok {
+			pos++
+			continue
+		}
+
+		if segStart < pos {
+			out = append(out, ast.Text{
+				Span: source.ByteSpan{
+					Start: base + source.BytePos(segStart),
+					End:   base + source.BytePos(pos),
+				},
+			})
+		}
+
+		out = append(out, ast.Emoji{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(pos),
+				End:   base + source.BytePos(end),
+			},
+			Value:       e.Emoji,
+			Description: e.Description,
+			Labeled:     labeled,
+		})
+
+		segStart = end
+		lastEnd = end
+		pos = end
+	}
+
+	if len(out) == 0 {
+		return nil, false
+	}
+
+	if segStart < len(s) {
+		out = append(out, ast.Text{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(segStart),
+				End:   span.End,
+			},
+		})
+	}
+
+	return out, true
+}
+
+// scanShortcode reports whether a :name: shortcode begins at pos and returns
+// its end. The closing colon must not be followed by a letter or digit.
+func scanShortcode(s string, pos int) (int, bool) {
+	end := pos + 1
+	for end < len(s) && emoji.IsNameByte(s[end]) {
+		end++
+	}
+
+	if end == pos+1 || end >= len(s) || s[end] != ':' {
+		return 0, false
+	}
+	end++
+
+	if end < len(s) && inline.IsASCIIAlnum(s[end]) {
+		return 0, false
+	}
+
+	return end, true
+}
+
+// atShortcodeBoundary reports whether a shortcode may open at pos: its colon
+// must not follow a letter, digit, colon, or backslash escape, and must not
+// fall within a word that holds a URL. A shortcode directly after another
+// is matched by the caller.
+//
+// Bytes before the run are read from the source, since the run may follow
+// other inline content.
+func atShortcodeBoundary(src *source.Source, s string, base source.BytePos, pos int) bool {
+	abs := base + source.BytePos(pos)
+	before := src.Slice(source.ByteSpan{Start: 0, End: abs})
+
+	wordStart := strings.LastIndexAny(before, " \t\r\n") + 1
+	word := before[wordStart:]
+	if strings.Contains(word, "://") || inline.HasPrefixFold(word, "www.") {
+		return false
+	}
+
+	if word == "" {
+		return true
+	}
+
+	last := word[len(word)-1]
+	return !inline.IsASCIIAlnum(last) && last != ':' && last != '\\'
+}
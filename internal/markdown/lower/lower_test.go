@@ -5,6 +5,7 @@ import (
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/block"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/extension"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/lower"
 	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
 	tk "github.com/spcameron/seanpatrickcameron.com/internal/markdown/testkit"
@@ -16,6 +17,7 @@ func TestLowerDocument(t *testing.T) {
 	testCases := []struct {
 		name    string
 		input   string
+		exts    extension.Set
 		want    ast.Document
 		wantErr error
 	}{
@@ -672,16 +674,379 @@ func TestLowerDocument(t *testing.T) {
 			),
 			wantErr: nil,
 		},
+
+		// Typography
+		{
+			name:  "typography: disabled leaves straight punctuation as text",
+			input: `"a" -- b...`,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText(`"a" -- b...`),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: double quotes open and close",
+			input: `"a"`,
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTSmartPunct(ast.LeftDoubleQuote),
+					tk.ASTText("a"),
+					tk.ASTSmartPunct(ast.RightDoubleQuote),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: apostrophe inside a word closes",
+			input: "don't",
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("don"),
+					tk.ASTSmartPunct(ast.RightSingleQuote),
+					tk.ASTText("t"),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: dashes and ellipsis",
+			input: "a -- b --- c...",
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("a "),
+					tk.ASTSmartPunct(ast.EnDash),
+					tk.ASTText(" b "),
+					tk.ASTSmartPunct(ast.EmDash),
+					tk.ASTText(" c"),
+					tk.ASTSmartPunct(ast.Ellipsis),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: quotes inside emphasis are converted",
+			input: `*"a"*`,
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTEm(
+						tk.ASTSmartPunct(ast.LeftDoubleQuote),
+						tk.ASTText("a"),
+						tk.ASTSmartPunct(ast.RightDoubleQuote),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: code spans are untouched",
+			input: "`\"a\" --`",
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTCodeSpan(`"a" --`),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: fenced code blocks are untouched",
+			input: "```\n\"a\" -- b...\n```",
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTFencedCodeBlock(
+					tk.ASTText(`"a" -- b...`),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "typography: escaped quote stays straight",
+			input: `\"a`,
+			exts:  extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText(`"`),
+					tk.ASTText("a"),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Math
+
+		{
+			name:  "math: inline and display",
+			input: "a $x$ b $$y$$",
+			exts:  extension.Math,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("a "),
+					tk.ASTMath(false, "x"),
+					tk.ASTText(" b "),
+					tk.ASTMath(true, "y"),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "math: block",
+			input: "$$\nx\n$$",
+			exts:  extension.Math,
+			want: tk.ASTDoc(
+				tk.ASTMathBlock("x"),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "math: typography leaves math untouched",
+			input: `$a'$ -- "b"`,
+			exts:  extension.Math | extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTMath(false, "a'"),
+					tk.ASTText(" "),
+					tk.ASTSmartPunct(ast.EnDash),
+					tk.ASTText(" "),
+					tk.ASTSmartPunct(ast.LeftDoubleQuote),
+					tk.ASTText("b"),
+					tk.ASTSmartPunct(ast.RightDoubleQuote),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Callouts
+
+		{
+			name:  "callout: marker line is removed from the body",
+			input: "> [!NOTE]\n> body",
+			exts:  extension.Callouts,
+			want: tk.ASTDoc(
+				tk.ASTCallout("note", 2, []ast.Inline{},
+					tk.ASTPara(
+						tk.ASTText("body"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "callout: title is inline parsed",
+			input: "> [!Tip] Use *this*",
+			exts:  extension.Callouts,
+			want: tk.ASTDoc(
+				tk.ASTCallout("tip", 2, []ast.Inline{
+					tk.ASTText("Use "),
+					tk.ASTEm(tk.ASTText("this")),
+				}),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "callout: unknown kind without a title stays a block quote",
+			input: "> [!UNKNOWN]",
+			exts:  extension.Callouts,
+			want: tk.ASTDoc(
+				tk.ASTBlockQuote(
+					tk.ASTPara(
+						tk.ASTText("["),
+						tk.ASTText("!"),
+						tk.ASTText("UNKNOWN"),
+						tk.ASTText("]"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "callout: typography applies to title and body",
+			input: "> [!NOTE] It's\n> done...",
+			exts:  extension.Callouts | extension.Typography,
+			want: tk.ASTDoc(
+				tk.ASTCallout("note", 2, []ast.Inline{
+					tk.ASTText("It"),
+					tk.ASTSmartPunct(ast.RightSingleQuote),
+					tk.ASTText("s"),
+				},
+					tk.ASTPara(
+						tk.ASTText("done"),
+						tk.ASTSmartPunct(ast.Ellipsis),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		{
+			name:  "callout: title sits one level below its section",
+			input: "## a\n\n> [!NOTE]\n> ### b\n>\n> > [!TIP]",
+			exts:  extension.Callouts,
+			want: tk.ASTDoc(
+				tk.ASTHeader(2, tk.ASTText("a")),
+				tk.ASTCallout("note", 3, []ast.Inline{},
+					tk.ASTHeader(3, tk.ASTText("b")),
+					tk.ASTCallout("tip", 4, []ast.Inline{}),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Definition lists
+
+		{
+			name:  "definition list: terms are inline parsed",
+			input: "*a*\nb\n: c",
+			exts:  extension.DefinitionLists,
+			want: tk.ASTDoc(
+				tk.ASTDefinitionList(
+					true,
+					tk.ASTDefinitionItem(
+						[]ast.DefinitionTerm{
+							tk.ASTDefinitionTerm(
+								tk.ASTEm(tk.ASTText("a")),
+							),
+							tk.ASTDefinitionTerm(
+								tk.ASTText("b"),
+							),
+						},
+						tk.ASTDefinition(
+							tk.ASTPara(
+								tk.ASTText("c"),
+							),
+						),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Attributes
+
+		{
+			name:  "attributes: lists are parsed and merged",
+			input: "# a {.b #c}\n{.d #e}\n\n[f]{lang=fr}",
+			exts:  extension.Attributes,
+			want: tk.ASTDoc(
+				ast.Header{
+					Level:      1,
+					Inlines:    []ast.Inline{tk.ASTText("a")},
+					Attributes: ast.Attributes{"class": "b d", "id": "e"},
+				},
+				tk.ASTPara(
+					ast.BracketedSpan{
+						Children:   []ast.Inline{tk.ASTText("f")},
+						Attributes: ast.Attributes{"lang": "fr"},
+					},
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Autolinks
+
+		{
+			name:  "autolinks: text runs split around links",
+			input: "a www.b.com c@d.org",
+			exts:  extension.Autolinks,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("a "),
+					ast.Link{
+						WWW:      true,
+						Autolink: true,
+						Children: []ast.Inline{tk.ASTText("www.b.com")},
+					},
+					tk.ASTText(" "),
+					ast.Link{
+						MailTo:   true,
+						Autolink: true,
+						Children: []ast.Inline{tk.ASTText("c@d.org")},
+					},
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Emoji
+
+		{
+			name:  "emoji: text runs split around shortcodes",
+			input: "a :rocket: b",
+			exts:  extension.Emoji | extension.EmojiLabels,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("a "),
+					tk.ASTEmoji("🚀", "rocket", true),
+					tk.ASTText(" b"),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// Abbreviations
+
+		{
+			name:  "abbreviations: whole words split text runs",
+			input: "*[GC]: garbage collector\n\nGC and GCs *GC*",
+			exts:  extension.Abbreviations,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTAbbreviation("GC"),
+					tk.ASTText(" and GCs "),
+					tk.ASTEm(
+						tk.ASTAbbreviation("GC"),
+					),
+				),
+			),
+			wantErr: nil,
+		},
+
+		// hard breaks and line blocks
+
+		{
+			name:  "hard breaks: every soft break becomes a hard break",
+			input: "alpha\nbeta\\\ngamma",
+			exts:  extension.HardBreaks,
+			want: tk.ASTDoc(
+				tk.ASTPara(
+					tk.ASTText("alpha"),
+					tk.ASTHardBreak(),
+					tk.ASTText("beta"),
+					tk.ASTHardBreak(),
+					tk.ASTText("gamma"),
+				),
+			),
+			wantErr: nil,
+		},
+		{
+			name:  "line block: lines lower with indentation and continuations",
+			input: "| *a*\n|   b\n  c\n|",
+			exts:  extension.LineBlocks | extension.HardBreaks,
+			want: tk.ASTDoc(
+				tk.ASTLineBlock(
+					tk.ASTLine(0, tk.ASTEm(tk.ASTText("a"))),
+					tk.ASTLine(2, tk.ASTText("b"), tk.ASTSoftBreak(), tk.ASTText("c")),
+					tk.ASTLine(0),
+				),
+			),
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			src := source.NewSource(tc.input)
 
-			irDoc, err := block.Parse(src)
+			irDoc, err := block.Parse(src, tc.exts)
 			require.NoError(t, err)
 
-			got, err := lower.Document(irDoc)
+			got, err := lower.Document(irDoc, tc.exts)
 
 			got = tk.NormalizeAST(got)
 			want := tk.NormalizeAST(tc.want)
@@ -0,0 +1,122 @@
+package lower
+
+import (
+	"fmt"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+)
+
+// CheckOutline reports warnings for the top-level headings of a document
+// that is rendered beneath a title of its own, such as a post body beneath
+// the post title.
+//
+// The title is taken to hold level 1, so a level-1 heading in the body is
+// reported as competing with it, and any heading that descends more than
+// one level below the heading before it is reported as skipping a level.
+//
+// A callout title counts as a heading at its TitleLevel, and the headings
+// directly in the callout's body are checked beneath it, so a body heading
+// at or above the title's level is reported. Other headings nested in
+// block quotes, callouts, and lists begin their own outlines and are not
+// checked.
+func CheckOutline(doc ast.Document) []diagnostic.Diagnostic {
+	var diags []diagnostic.Diagnostic
+	prev := 1
+
+	for _, blk := range doc.Blocks {
+		diags = append(diags, checkCalloutOutlines(blk)...)
+
+		h, ok := blk.(ast.Header)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case h.Level == 1:
+			diags = append(diags, diagnostic.Diagnostic{
+				Message:  "level-1 heading competes with the document title",
+				Span:     h.Span,
+				Severity: diagnostic.SeverityWarning,
+			})
+
+		case h.Level > prev+1:
+			diags = append(diags, skippedLevel(h, prev))
+		}
+
+		prev = h.Level
+	}
+
+	return diags
+}
+
+// checkCalloutOutlines checks the outline beneath the title of every
+// callout within blk.
+func checkCalloutOutlines(blk ast.Block) []diagnostic.Diagnostic {
+	var diags []diagnostic.Diagnostic
+
+	switch v := blk.(type) {
+	case ast.Callout:
+		prev := v.TitleLevel
+		for _, child := range v.Children {
+			diags = append(diags, checkCalloutOutlines(child)...)
+
+			h, ok := child.(ast.Header)
+			if !ok {
+				continue
+			}
+
+			switch {
+			case h.Level <= v.TitleLevel:
+				diags = append(diags, diagnostic.Diagnostic{
+					Message:  fmt.Sprintf("heading level %d is not below its callout title at level %d", h.Level, v.TitleLevel),
+					Span:     h.Span,
+					Severity: diagnostic.SeverityWarning,
+				})
+
+			case h.Level > prev+1:
+				diags = append(diags, skippedLevel(h, prev))
+			}
+
+			prev = max(h.Level, v.TitleLevel)
+		}
+
+	case ast.BlockQuote:
+		for _, child := range v.Children {
+			diags = append(diags, checkCalloutOutlines(child)...)
+		}
+
+	case ast.OrderedList:
+		for _, item := range v.Items {
+			for _, child := range item.Children {
+				diags = append(diags, checkCalloutOutlines(child)...)
+			}
+		}
+
+	case ast.UnorderedList:
+		for _, item := range v.Items {
+			for _, child := range item.Children {
+				diags = append(diags, checkCalloutOutlines(child)...)
+			}
+		}
+
+	case ast.DefinitionList:
+		for _, item := range v.Items {
+			for _, def := range item.Definitions {
+				for _, child := range def.Children {
+					diags = append(diags, checkCalloutOutlines(child)...)
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+func skippedLevel(h ast.Header, prev int) diagnostic.Diagnostic {
+	return diagnostic.Diagnostic{
+		Message:  fmt.Sprintf("heading level %d skips level %d", h.Level, prev+1),
+		Span:     h.Span,
+		Severity: diagnostic.SeverityWarning,
+	}
+}
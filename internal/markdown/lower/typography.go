@@ -0,0 +1,287 @@
+package lower
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// applyTypography rewrites straight quotes, dash runs, and ellipses found
+// in text nodes into typographic punctuation nodes.
+//
+// Code blocks, HTML blocks, code spans, raw HTML, and autolinks are left
+// untouched, as are link and image destinations, which are never
+// represented as text nodes.
+func applyTypography(src *source.Source, blocks []ast.Block) []ast.Block {
+	return rewriteInlines(blocks, func(inlines []ast.Inline) []ast.Inline {
+		return smartenInlines(src, inlines)
+	})
+}
+
+func smartenInlines(src *source.Source, inlines []ast.Inline) []ast.Inline {
+	out := make([]ast.Inline, 0, len(inlines))
+
+	for _, inl := range inlines {
+		switch v := inl.(type) {
+		case ast.Text:
+			out = append(out, smartenText(src, v)...)
+
+		case ast.Emph:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Strong:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Link:
+			if !v.Autolink {
+				v.Children = smartenInlines(src, v.Children)
+			}
+			out = append(out, v)
+
+		case ast.Image:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.BracketedSpan:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Superscript:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Subscript:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Strikethrough:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Highlight:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		case ast.Insert:
+			v.Children = smartenInlines(src, v.Children)
+			out = append(out, v)
+
+		default:
+			out = append(out, inl)
+		}
+	}
+
+	return out
+}
+
+// smartenText splits a text node around any typographic punctuation it
+// contains. A text node without candidates is returned unchanged.
+func smartenText(src *source.Source, t ast.Text) []ast.Inline {
+	s := src.Slice(t.Span)
+	base := t.Span.Start
+
+	out := []ast.Inline{}
+	segStart := 0
+	pos := 0
+
+	for pos < len(s) {
+		kind, width := classifyPunct(src, s, base, pos)
+		if kind == 0 {
+			pos += width
+			continue
+		}
+
+		if segStart < pos {
+			out = append(out, ast.Text{
+				Span: source.ByteSpan{
+					Start: base + source.BytePos(segStart),
+					End:   base + source.BytePos(pos),
+				},
+			})
+		}
+
+		out = append(out, ast.SmartPunct{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(pos),
+				End:   base + source.BytePos(pos+width),
+			},
+			Kind: kind,
+		})
+
+		pos += width
+		segStart = pos
+	}
+
+	if len(out) == 0 {
+		return []ast.Inline{t}
+	}
+
+	if segStart < len(s) {
+		out = append(out, ast.Text{
+			Span: source.ByteSpan{
+				Start: base + source.BytePos(segStart),
+				End:   t.Span.End,
+			},
+		})
+	}
+
+	return out
+}
+
+// classifyPunct reports the typographic punctuation beginning at s[pos],
+// if any, along with the number of bytes examined. A zero kind means the
+// examined bytes remain literal text.
+func classifyPunct(src *source.Source, s string, base source.BytePos, pos int) (ast.PunctKind, int) {
+	abs := base + source.BytePos(pos)
+
+	switch s[pos] {
+	case '"', '\'':
+		if isEscapedAt(src, abs) {
+			return 0, 1
+		}
+		return classifyQuote(src, s[pos], abs), 1
+
+	case '-':
+		run := runLength(s, pos, '-')
+		if isEscapedAt(src, abs) {
+			return 0, 1
+		}
+
+		switch run {
+		case 2:
+			return ast.EnDash, run
+		case 3:
+			return ast.EmDash, run
+		default:
+			return 0, run
+		}
+
+	case '.':
+		run := runLength(s, pos, '.')
+		if isEscapedAt(src, abs) {
+			return 0, 1
+		}
+
+		if run == 3 {
+			return ast.Ellipsis, run
+		}
+		return 0, run
+
+	default:
+		_, width := utf8.DecodeRuneInString(s[pos:])
+		return 0, width
+	}
+}
+
+// classifyQuote chooses an opening or closing form for the quote at pos
+// based on the flanking runes in the source.
+//
+// A single quote that closes, or that sits inside a word, is rendered as a
+// right single quote so that apostrophes come out correctly. A single quote
+// opening a run of digits, as in '90s, is treated as an elision. Double
+// quotes that flank both ways, such as an inch mark, stay straight.
+func classifyQuote(src *source.Source, q byte, pos source.BytePos) ast.PunctKind {
+	before := runeBeforePos(src, pos)
+	after := runeAfterPos(src, pos+1)
+
+	left := leftFlanking(before, after)
+	right := rightFlanking(before, after)
+
+	if q == '\'' {
+		switch {
+		case right:
+			return ast.RightSingleQuote
+		case left && unicode.IsDigit(after):
+			return ast.RightSingleQuote
+		case left:
+			return ast.LeftSingleQuote
+		default:
+			return 0
+		}
+	}
+
+	switch {
+	case left && !right:
+		return ast.LeftDoubleQuote
+	case right && !left:
+		return ast.RightDoubleQuote
+	default:
+		return 0
+	}
+}
+
+// isEscapedAt reports whether the byte at pos is escaped by an odd-length
+// run of preceding backslashes.
+func isEscapedAt(src *source.Source, pos source.BytePos) bool {
+	slashes := 0
+	for i := int(pos) - 1; i >= 0 && src.Raw[i] == '\\'; i-- {
+		slashes++
+	}
+
+	return slashes%2 == 1
+}
+
+func runLength(s string, pos int, b byte) int {
+	end := pos
+	for end < len(s) && s[end] == b {
+		end++
+	}
+
+	return end - pos
+}
+
+// runeBeforePos returns the rune preceding pos, treating the start of the
+// source as whitespace.
+func runeBeforePos(src *source.Source, pos source.BytePos) rune {
+	if pos <= 0 {
+		return ' '
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(src.Raw[:pos])
+	return r
+}
+
+// runeAfterPos returns the rune at pos, treating the end of the source as
+// whitespace.
+func runeAfterPos(src *source.Source, pos source.BytePos) rune {
+	if pos >= src.EOF() {
+		return ' '
+	}
+
+	r, _ := utf8.DecodeRuneInString(src.Raw[pos:])
+	return r
+}
+
+// leftFlanking reports whether a quote between before and after can open,
+// using the same flanking rules as emphasis delimiters.
+func leftFlanking(before, after rune) bool {
+	if unicode.IsSpace(after) {
+		return false
+	}
+	if !isPunctOrSymbol(after) {
+		return true
+	}
+	return unicode.IsSpace(before) || isPunctOrSymbol(before)
+}
+
+// rightFlanking reports whether a quote between before and after can
+// close, using the same flanking rules as emphasis delimiters.
+func rightFlanking(before, after rune) bool {
+	if unicode.IsSpace(before) {
+		return false
+	}
+	if !isPunctOrSymbol(before) {
+		return true
+	}
+	return unicode.IsSpace(after) || isPunctOrSymbol(after)
+}
+
+// isPunctOrSymbol reports whether r is a Unicode punctuation or symbol
+// character, so that markup such as '>' or '*' counts as punctuation.
+func isPunctOrSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
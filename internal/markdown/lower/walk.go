@@ -0,0 +1,88 @@
+package lower
+
+import "github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+
+// rewriteInlines applies fn to every sequence of prose inlines held by
+// blocks and their descendants, replacing each sequence with the result.
+//
+// Code blocks, HTML blocks, math blocks, and thematic breaks carry no prose
+// and are returned unchanged. fn is responsible for descending into inline
+// containers such as emphasis and links.
+func rewriteInlines(blocks []ast.Block, fn func([]ast.Inline) []ast.Inline) []ast.Block {
+	out := make([]ast.Block, 0, len(blocks))
+
+	for _, blk := range blocks {
+		out = append(out, rewriteBlockInlines(blk, fn))
+	}
+
+	return out
+}
+
+func rewriteBlockInlines(block ast.Block, fn func([]ast.Inline) []ast.Inline) ast.Block {
+	switch v := block.(type) {
+	case ast.BlockQuote:
+		v.Children = rewriteInlines(v.Children, fn)
+		return v
+
+	case ast.Callout:
+		v.Title = fn(v.Title)
+		v.Children = rewriteInlines(v.Children, fn)
+		return v
+
+	case ast.Header:
+		v.Inlines = fn(v.Inlines)
+		return v
+
+	case ast.OrderedList:
+		v.Items = rewriteListItemInlines(v.Items, fn)
+		return v
+
+	case ast.UnorderedList:
+		v.Items = rewriteListItemInlines(v.Items, fn)
+		return v
+
+	case ast.ListItem:
+		v.Children = rewriteInlines(v.Children, fn)
+		return v
+
+	case ast.DefinitionList:
+		for i, item := range v.Items {
+			for j, term := range item.Terms {
+				term.Inlines = fn(term.Inlines)
+				item.Terms[j] = term
+			}
+			for j, def := range item.Definitions {
+				def.Children = rewriteInlines(def.Children, fn)
+				item.Definitions[j] = def
+			}
+			v.Items[i] = item
+		}
+		return v
+
+	case ast.LineBlock:
+		for i, line := range v.Lines {
+			line.Inlines = fn(line.Inlines)
+			v.Lines[i] = line
+		}
+		return v
+
+	case ast.Paragraph:
+		v.Inlines = fn(v.Inlines)
+		return v
+
+	default:
+		// code blocks, HTML blocks, and thematic breaks carry no prose
+		return block
+	}
+}
+
+func rewriteListItemInlines(items []ast.ListItem, fn func([]ast.Inline) []ast.Inline) []ast.ListItem {
+	out := make([]ast.ListItem, 0, len(items))
+
+	for _, item := range items {
+		item.Children = rewriteInlines(item.Children, fn)
+		out = append(out, item)
+	}
+
+	return out
+}
@@ -0,0 +1,126 @@
+package lower
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/ast"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// WikiLinkTarget is the resolved destination of a wiki link.
+type WikiLinkTarget struct {
+	URL   string
+	Title string
+}
+
+// WikiLinkResolver resolves the target named by a [[target]] wiki link,
+// reporting false when no such target exists.
+type WikiLinkResolver func(target string) (WikiLinkTarget, bool)
+
+// ResolveWikiLinks fills in the destination and title of every wiki link in
+// doc using resolve.
+//
+// A fragment is appended to the resolved URL as written. A link whose
+// target cannot be resolved is left in the returned document as its source
+// text and reported as a diagnostic.DiagnosticError located at the target;
+// when several cannot be resolved, the error joins one for each. A nil
+// resolver resolves nothing.
+func ResolveWikiLinks(doc ast.Document, resolve WikiLinkResolver) (ast.Document, error) {
+	if resolve == nil {
+		resolve = func(string) (WikiLinkTarget, bool) {
+			return WikiLinkTarget{}, false
+		}
+	}
+
+	var errs []error
+	doc.Blocks = rewriteInlines(doc.Blocks, func(inlines []ast.Inline) []ast.Inline {
+		return resolveWikiLinkInlines(doc.Source, inlines, resolve, &errs)
+	})
+
+	switch len(errs) {
+	case 0:
+		return doc, nil
+	case 1:
+		return doc, errs[0]
+	default:
+		return doc, errors.Join(errs...)
+	}
+}
+
+func resolveWikiLinkInlines(src *source.Source, inlines []ast.Inline, resolve WikiLinkResolver, errs *[]error) []ast.Inline {
+	out := make([]ast.Inline, 0, len(inlines))
+
+	for _, inl := range inlines {
+		switch v := inl.(type) {
+		case ast.WikiLink:
+			link, err := resolveWikiLink(src, v, resolve)
+			if err != nil {
+				*errs = append(*errs, err)
+				inl = ast.Text{Span: v.Span}
+			} else {
+				inl = link
+			}
+
+		case ast.Emph:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Strong:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.BracketedSpan:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Superscript:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Subscript:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Strikethrough:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Highlight:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+
+		case ast.Insert:
+			v.Children = resolveWikiLinkInlines(src, v.Children, resolve, errs)
+			inl = v
+		}
+
+		out = append(out, inl)
+	}
+
+	return out
+}
+
+func resolveWikiLink(src *source.Source, link ast.WikiLink, resolve WikiLinkResolver) (ast.WikiLink, error) {
+	name := src.Slice(link.Target)
+
+	target, ok := resolve(name)
+	if !ok {
+		return ast.WikiLink{}, diagnostic.DiagnosticError{
+			Diagnostic: diagnostic.Diagnostic{
+				Message:  fmt.Sprintf("unknown wiki link target %q", name),
+				Span:     link.Target,
+				Severity: diagnostic.SeverityError,
+			},
+		}
+	}
+
+	link.Destination = target.URL
+	if link.Fragment != (source.ByteSpan{}) {
+		link.Destination += "#" + src.Slice(link.Fragment)
+	}
+	link.Title = target.Title
+
+	return link, nil
+}
@@ -0,0 +1,13 @@
+// Package mathml translates a practical subset of TeX math notation into
+// MathML node trees.
+//
+// The translator reads TeX directly from spans of the shared source buffer
+// and produces html nodes, so math is rendered entirely at build time
+// without a client-side renderer. The supported vocabulary covers
+// fractions, roots, sub- and superscripts, Greek letters, common operators
+// and relations, big operators such as sums and integrals, accents, font
+// commands, stretchy delimiters, and matrix-style environments.
+//
+// Commands outside the supported subset are reported as a
+// diagnostic.DiagnosticError located at the offending command.
+package mathml
@@ -0,0 +1,168 @@
+package mathml
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// tokenKind identifies the kind of a lexical TeX token.
+type tokenKind int
+
+const (
+	_ tokenKind = iota
+	tokenCommand
+	tokenOpenBrace
+	tokenCloseBrace
+	tokenSuperscript
+	tokenSubscript
+	tokenAlign
+	tokenLetter
+	tokenNumber
+	tokenSymbol
+	tokenEOF
+)
+
+// token is a TeX token with its source span.
+//
+// For commands, Text holds the command name without the leading backslash.
+// For all other kinds, Text holds the token's source text. Segment records
+// which input span the token was read from.
+type token struct {
+	Kind    tokenKind
+	Text    string
+	Span    source.ByteSpan
+	Segment int
+}
+
+// lex tokenizes the TeX source covered by spans. Whitespace is discarded,
+// and a % comment runs to the end of its span.
+func lex(src *source.Source, spans []source.ByteSpan) []token {
+	tokens := []token{}
+
+	for seg, span := range spans {
+		s := src.Slice(span)
+		pos := 0
+
+		for pos < len(s) {
+			start := pos
+			b := s[pos]
+
+			var kind tokenKind
+
+			switch {
+			case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+				pos++
+				continue
+
+			case b == '%':
+				pos = len(s)
+				continue
+
+			case b == '\\':
+				pos++
+				kind = tokenCommand
+
+				if pos < len(s) && isASCIILetter(s[pos]) {
+					for pos < len(s) && isASCIILetter(s[pos]) {
+						pos++
+					}
+				} else if pos < len(s) {
+					_, width := utf8.DecodeRuneInString(s[pos:])
+					pos += width
+				}
+
+			case b == '{':
+				pos++
+				kind = tokenOpenBrace
+
+			case b == '}':
+				pos++
+				kind = tokenCloseBrace
+
+			case b == '^':
+				pos++
+				kind = tokenSuperscript
+
+			case b == '_':
+				pos++
+				kind = tokenSubscript
+
+			case b == '&':
+				pos++
+				kind = tokenAlign
+
+			case isASCIIDigit(b):
+				pos = numberEnd(s, pos)
+				kind = tokenNumber
+
+			default:
+				r, width := utf8.DecodeRuneInString(s[pos:])
+				pos += width
+
+				kind = tokenSymbol
+				if unicode.IsLetter(r) {
+					kind = tokenLetter
+				}
+			}
+
+			text := s[start:pos]
+			if kind == tokenCommand {
+				text = text[1:]
+			}
+
+			tokens = append(tokens, token{
+				Kind: kind,
+				Text: text,
+				Span: source.ByteSpan{
+					Start: span.Start + source.BytePos(start),
+					End:   span.Start + source.BytePos(pos),
+				},
+				Segment: seg,
+			})
+		}
+	}
+
+	var anchor source.ByteSpan
+	if len(spans) > 0 {
+		last := spans[len(spans)-1]
+		anchor = source.ByteSpan{Start: last.End, End: last.End}
+	}
+
+	tokens = append(tokens, token{
+		Kind:    tokenEOF,
+		Span:    anchor,
+		Segment: len(spans) - 1,
+	})
+
+	return tokens
+}
+
+// numberEnd returns the end of the number beginning at pos. A decimal point
+// belongs to the number only when a digit follows it.
+func numberEnd(s string, pos int) int {
+	for pos < len(s) {
+		if isASCIIDigit(s[pos]) {
+			pos++
+			continue
+		}
+
+		if s[pos] == '.' && pos+1 < len(s) && isASCIIDigit(s[pos+1]) {
+			pos++
+			continue
+		}
+
+		break
+	}
+
+	return pos
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isASCIIDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
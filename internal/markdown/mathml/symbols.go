@@ -0,0 +1,376 @@
+package mathml
+
+// greekLetters maps Greek letter commands to their characters. Uppercase
+// letters are set upright, following TeX convention.
+var greekLetters = map[string]string{
+	"alpha":      "α",
+	"beta":       "β",
+	"gamma":      "γ",
+	"delta":      "δ",
+	"epsilon":    "ϵ",
+	"varepsilon": "ε",
+	"zeta":       "ζ",
+	"eta":        "η",
+	"theta":      "θ",
+	"vartheta":   "ϑ",
+	"iota":       "ι",
+	"kappa":      "κ",
+	"lambda":     "λ",
+	"mu":         "μ",
+	"nu":         "ν",
+	"xi":         "ξ",
+	"pi":         "π",
+	"varpi":      "ϖ",
+	"rho":        "ρ",
+	"varrho":     "ϱ",
+	"sigma":      "σ",
+	"varsigma":   "ς",
+	"tau":        "τ",
+	"upsilon":    "υ",
+	"phi":        "ϕ",
+	"varphi":     "φ",
+	"chi":        "χ",
+	"psi":        "ψ",
+	"omega":      "ω",
+	"Gamma":      "Γ",
+	"Delta":      "Δ",
+	"Theta":      "Θ",
+	"Lambda":     "Λ",
+	"Xi":         "Ξ",
+	"Pi":         "Π",
+	"Sigma":      "Σ",
+	"Upsilon":    "Υ",
+	"Phi":        "Φ",
+	"Psi":        "Ψ",
+	"Omega":      "Ω",
+}
+
+// identifiers maps commands that denote symbols used as identifiers.
+var identifiers = map[string]string{
+	"infty":      "∞",
+	"partial":    "∂",
+	"nabla":      "∇",
+	"emptyset":   "∅",
+	"varnothing": "∅",
+	"hbar":       "ℏ",
+	"ell":        "ℓ",
+	"Re":         "ℜ",
+	"Im":         "ℑ",
+	"aleph":      "ℵ",
+}
+
+// operators maps commands that denote operators, relations, arrows,
+// punctuation, and escaped characters.
+var operators = map[string]string{
+	// binary operators
+	"pm":       "±",
+	"mp":       "∓",
+	"times":    "×",
+	"div":      "÷",
+	"cdot":     "⋅",
+	"ast":      "∗",
+	"star":     "⋆",
+	"circ":     "∘",
+	"bullet":   "∙",
+	"oplus":    "⊕",
+	"otimes":   "⊗",
+	"cup":      "∪",
+	"cap":      "∩",
+	"setminus": "∖",
+	"wedge":    "∧",
+	"land":     "∧",
+	"vee":      "∨",
+	"lor":      "∨",
+	"neg":      "¬",
+	"lnot":     "¬",
+	"bmod":     "mod",
+
+	// relations
+	"le":       "≤",
+	"leq":      "≤",
+	"ge":       "≥",
+	"geq":      "≥",
+	"ne":       "≠",
+	"neq":      "≠",
+	"approx":   "≈",
+	"equiv":    "≡",
+	"sim":      "∼",
+	"simeq":    "≃",
+	"cong":     "≅",
+	"propto":   "∝",
+	"ll":       "≪",
+	"gg":       "≫",
+	"in":       "∈",
+	"notin":    "∉",
+	"ni":       "∋",
+	"subset":   "⊂",
+	"subseteq": "⊆",
+	"supset":   "⊃",
+	"supseteq": "⊇",
+	"perp":     "⊥",
+	"parallel": "∥",
+	"mid":      "∣",
+
+	// arrows
+	"to":              "→",
+	"rightarrow":      "→",
+	"leftarrow":       "←",
+	"gets":            "←",
+	"leftrightarrow":  "↔",
+	"Rightarrow":      "⇒",
+	"Leftarrow":       "⇐",
+	"Leftrightarrow":  "⇔",
+	"implies":         "⟹",
+	"impliedby":       "⟸",
+	"iff":             "⟺",
+	"mapsto":          "↦",
+	"uparrow":         "↑",
+	"downarrow":       "↓",
+	"longrightarrow":  "⟶",
+	"longleftarrow":   "⟵",
+	"Longrightarrow":  "⟹",
+	"Longleftarrow":   "⟸",
+	"hookrightarrow":  "↪",
+	"rightleftarrows": "⇄",
+
+	// quantifiers and logic
+	"forall":  "∀",
+	"exists":  "∃",
+	"nexists": "∄",
+
+	// dots
+	"ldots": "…",
+	"dots":  "…",
+	"cdots": "⋯",
+	"vdots": "⋮",
+	"ddots": "⋱",
+
+	// delimiters and punctuation
+	"langle": "⟨",
+	"rangle": "⟩",
+	"lfloor": "⌊",
+	"rfloor": "⌋",
+	"lceil":  "⌈",
+	"rceil":  "⌉",
+	"vert":   "|",
+	"lvert":  "|",
+	"rvert":  "|",
+	"Vert":   "‖",
+	"lVert":  "‖",
+	"rVert":  "‖",
+	"colon":  ":",
+
+	// escaped characters
+	"{": "{",
+	"}": "}",
+	"|": "‖",
+	"%": "%",
+	"$": "$",
+	"&": "&",
+	"#": "#",
+	"_": "_",
+}
+
+// bigOperator describes a large operator such as a sum or integral.
+//
+// Limits reports whether scripts are placed above and below the operator
+// in display style rather than to its right.
+type bigOperator struct {
+	Text   string
+	Limits bool
+}
+
+var bigOperators = map[string]bigOperator{
+	"sum":       {Text: "∑", Limits: true},
+	"prod":      {Text: "∏", Limits: true},
+	"coprod":    {Text: "∐", Limits: true},
+	"bigcup":    {Text: "⋃", Limits: true},
+	"bigcap":    {Text: "⋂", Limits: true},
+	"bigoplus":  {Text: "⨁", Limits: true},
+	"bigotimes": {Text: "⨂", Limits: true},
+	"bigvee":    {Text: "⋁", Limits: true},
+	"bigwedge":  {Text: "⋀", Limits: true},
+	"int":       {Text: "∫"},
+	"iint":      {Text: "∬"},
+	"iiint":     {Text: "∭"},
+	"oint":      {Text: "∮"},
+}
+
+// namedFunction describes an operator name such as sin or lim.
+type namedFunction struct {
+	Text   string
+	Limits bool
+}
+
+var namedFunctions = map[string]namedFunction{
+	"sin":    {Text: "sin"},
+	"cos":    {Text: "cos"},
+	"tan":    {Text: "tan"},
+	"cot":    {Text: "cot"},
+	"sec":    {Text: "sec"},
+	"csc":    {Text: "csc"},
+	"arcsin": {Text: "arcsin"},
+	"arccos": {Text: "arccos"},
+	"arctan": {Text: "arctan"},
+	"sinh":   {Text: "sinh"},
+	"cosh":   {Text: "cosh"},
+	"tanh":   {Text: "tanh"},
+	"log":    {Text: "log"},
+	"ln":     {Text: "ln"},
+	"lg":     {Text: "lg"},
+	"exp":    {Text: "exp"},
+	"deg":    {Text: "deg"},
+	"dim":    {Text: "dim"},
+	"ker":    {Text: "ker"},
+	"arg":    {Text: "arg"},
+	"hom":    {Text: "hom"},
+	"lim":    {Text: "lim", Limits: true},
+	"liminf": {Text: "lim inf", Limits: true},
+	"limsup": {Text: "lim sup", Limits: true},
+	"max":    {Text: "max", Limits: true},
+	"min":    {Text: "min", Limits: true},
+	"sup":    {Text: "sup", Limits: true},
+	"inf":    {Text: "inf", Limits: true},
+	"det":    {Text: "det", Limits: true},
+	"gcd":    {Text: "gcd", Limits: true},
+	"Pr":     {Text: "Pr", Limits: true},
+}
+
+// spaces maps spacing commands to MathML widths.
+var spaces = map[string]string{
+	",":     "0.1667em",
+	":":     "0.2222em",
+	">":     "0.2222em",
+	";":     "0.2778em",
+	"!":     "-0.1667em",
+	" ":     "0.3333em",
+	"quad":  "1em",
+	"qquad": "2em",
+}
+
+// accent describes a mark placed over or under its argument.
+//
+// Stretchy marks such as braces are not accents and take their scripts as
+// limits.
+type accent struct {
+	Mark   string
+	Under  bool
+	Accent bool
+}
+
+var accents = map[string]accent{
+	"hat":        {Mark: "^", Accent: true},
+	"widehat":    {Mark: "^", Accent: true},
+	"bar":        {Mark: "¯", Accent: true},
+	"overline":   {Mark: "‾", Accent: true},
+	"vec":        {Mark: "→", Accent: true},
+	"tilde":      {Mark: "~", Accent: true},
+	"widetilde":  {Mark: "~", Accent: true},
+	"dot":        {Mark: "˙", Accent: true},
+	"ddot":       {Mark: "¨", Accent: true},
+	"check":      {Mark: "ˇ", Accent: true},
+	"breve":      {Mark: "˘", Accent: true},
+	"acute":      {Mark: "´", Accent: true},
+	"grave":      {Mark: "`", Accent: true},
+	"underline":  {Mark: "_", Under: true, Accent: true},
+	"overbrace":  {Mark: "⏞"},
+	"underbrace": {Mark: "⏟", Under: true},
+}
+
+// delimiters maps commands accepted after \left and \right.
+var delimiters = map[string]string{
+	"{":      "{",
+	"}":      "}",
+	"|":      "‖",
+	"langle": "⟨",
+	"rangle": "⟩",
+	"lfloor": "⌊",
+	"rfloor": "⌋",
+	"lceil":  "⌈",
+	"rceil":  "⌉",
+	"vert":   "|",
+	"lvert":  "|",
+	"rvert":  "|",
+	"Vert":   "‖",
+	"lVert":  "‖",
+	"rVert":  "‖",
+}
+
+// environment describes a matrix-style \begin ... \end environment.
+type environment struct {
+	Open        string
+	Close       string
+	ColumnAlign string
+}
+
+var environments = map[string]environment{
+	"matrix":   {},
+	"pmatrix":  {Open: "(", Close: ")"},
+	"bmatrix":  {Open: "[", Close: "]"},
+	"Bmatrix":  {Open: "{", Close: "}"},
+	"vmatrix":  {Open: "|", Close: "|"},
+	"Vmatrix":  {Open: "‖", Close: "‖"},
+	"cases":    {Open: "{", ColumnAlign: "left left"},
+	"aligned":  {ColumnAlign: "right left"},
+	"gathered": {},
+}
+
+// alphabet maps ASCII letters and digits to a styled Unicode alphabet.
+//
+// Upper, Lower, and Digit hold the first code point of each run, or zero
+// when the style has no such run. Exceptions holds letters that Unicode
+// encodes outside the contiguous run.
+type alphabet struct {
+	Upper      rune
+	Lower      rune
+	Digit      rune
+	Exceptions map[rune]rune
+}
+
+var alphabets = map[string]alphabet{
+	"mathbf": {
+		Upper: 0x1D400,
+		Lower: 0x1D41A,
+		Digit: 0x1D7CE,
+	},
+	"mathbb": {
+		Upper: 0x1D538,
+		Lower: 0x1D552,
+		Digit: 0x1D7D8,
+		Exceptions: map[rune]rune{
+			'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ',
+		},
+	},
+	"mathcal": {
+		Upper: 0x1D49C,
+		Exceptions: map[rune]rune{
+			'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ',
+		},
+	},
+	"mathfrak": {
+		Upper: 0x1D504,
+		Lower: 0x1D51E,
+		Exceptions: map[rune]rune{
+			'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ',
+		},
+	},
+}
+
+// styled maps r into the alphabet, reporting false when the alphabet has
+// no form for r.
+func (a alphabet) styled(r rune) (rune, bool) {
+	if ex, ok := a.Exceptions[r]; ok {
+		return ex, true
+	}
+
+	switch {
+	case r >= 'A' && r <= 'Z' && a.Upper != 0:
+		return a.Upper + (r - 'A'), true
+	case r >= 'a' && r <= 'z' && a.Lower != 0:
+		return a.Lower + (r - 'a'), true
+	case r >= '0' && r <= '9' && a.Digit != 0:
+		return a.Digit + (r - '0'), true
+	default:
+		return 0, false
+	}
+}
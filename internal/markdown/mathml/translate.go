@@ -0,0 +1,822 @@
+package mathml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+)
+
+// Translate converts the TeX source covered by spans into a <math>
+// element. Spans are read in order as consecutive lines, so the payload
+// lines of a math block may be passed directly.
+//
+// Display selects block layout, in which big operators such as sums take
+// their scripts as limits above and below.
+func Translate(src *source.Source, spans []source.ByteSpan, display bool) (html.Node, error) {
+	p := &parser{
+		Source:  src,
+		Spans:   spans,
+		Tokens:  lex(src, spans),
+		Display: display,
+	}
+
+	children, err := p.parseRow()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.Kind != tokenEOF {
+		return nil, unexpected(tok)
+	}
+
+	attr := html.Attributes{}
+	if display {
+		attr["display"] = "block"
+	}
+
+	node := html.Element{
+		Tag:      "math",
+		Attr:     attr,
+		Children: children,
+	}
+
+	return node, nil
+}
+
+// parser is a recursive-descent parser over a TeX token stream.
+type parser struct {
+	Source  *source.Source
+	Spans   []source.ByteSpan
+	Tokens  []token
+	Index   int
+	Display bool
+}
+
+// atom is a parsed base together with how scripts attach to it.
+type atom struct {
+	Node   html.Node
+	Limits bool
+}
+
+// peek returns the current token without advancing.
+func (p *parser) peek() token {
+	return p.Tokens[p.Index]
+}
+
+// next returns the current token and advances, never moving past EOF.
+func (p *parser) next() token {
+	tok := p.Tokens[p.Index]
+	if tok.Kind != tokenEOF {
+		p.Index++
+	}
+
+	return tok
+}
+
+// parseRow parses atoms until a token that ends the current row.
+func (p *parser) parseRow() ([]html.Node, error) {
+	return p.parseRowUntil(nil)
+}
+
+// parseRowUntil parses atoms until a token that ends the current row or
+// satisfies stop.
+func (p *parser) parseRowUntil(stop func(token) bool) ([]html.Node, error) {
+	nodes := []html.Node{}
+
+	for {
+		tok := p.peek()
+		if endsRow(tok) || (stop != nil && stop(tok)) {
+			return nodes, nil
+		}
+
+		node, err := p.parseScripted()
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+}
+
+// endsRow reports whether tok closes the row being parsed.
+func endsRow(tok token) bool {
+	switch tok.Kind {
+	case tokenEOF, tokenCloseBrace, tokenAlign:
+		return true
+
+	case tokenCommand:
+		return tok.Text == "\\" || tok.Text == "end" || tok.Text == "right"
+
+	default:
+		return false
+	}
+}
+
+// parseScripted parses an atom followed by any primes, subscript, and
+// superscript.
+func (p *parser) parseScripted() (html.Node, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	var sub, sup html.Node
+	primes := 0
+
+	for {
+		tok := p.peek()
+
+		if tok.Kind == tokenSymbol && tok.Text == "'" {
+			p.next()
+			primes++
+			continue
+		}
+
+		if tok.Kind == tokenSuperscript {
+			if sup != nil {
+				return nil, errorAt(tok.Span, "double superscript")
+			}
+
+			p.next()
+			sup, err = p.parseArgument(tok)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if tok.Kind == tokenSubscript {
+			if sub != nil {
+				return nil, errorAt(tok.Span, "double subscript")
+			}
+
+			p.next()
+			sub, err = p.parseArgument(tok)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		break
+	}
+
+	if primes > 0 {
+		prime := mo(strings.Repeat("′", primes))
+		if sup == nil {
+			sup = prime
+		} else {
+			sup = mrow(prime, sup)
+		}
+	}
+
+	return attachScripts(base, sub, sup, p.Display), nil
+}
+
+// attachScripts wraps base with its scripts, placing them as limits when
+// the base takes limits in display style.
+func attachScripts(base atom, sub, sup html.Node, display bool) html.Node {
+	limits := base.Limits && display
+
+	switch {
+	case sub == nil && sup == nil:
+		return base.Node
+	case sup == nil && limits:
+		return element("munder", nil, base.Node, sub)
+	case sub == nil && limits:
+		return element("mover", nil, base.Node, sup)
+	case limits:
+		return element("munderover", nil, base.Node, sub, sup)
+	case sup == nil:
+		return element("msub", nil, base.Node, sub)
+	case sub == nil:
+		return element("msup", nil, base.Node, sup)
+	default:
+		return element("msubsup", nil, base.Node, sub, sup)
+	}
+}
+
+// parseArgument parses a single command or script argument: a braced
+// group or one token. A multi-digit number contributes only its first
+// digit, as in x^23 or \frac12.
+func (p *parser) parseArgument(owner token) (html.Node, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.Kind == tokenOpenBrace:
+		return p.parseGroup()
+
+	case endsRow(tok) || tok.Kind == tokenSuperscript || tok.Kind == tokenSubscript:
+		return nil, errorAt(owner.Span, "missing argument for %s", describe(owner))
+
+	case tok.Kind == tokenNumber && len(tok.Text) > 1:
+		rest := &p.Tokens[p.Index]
+		rest.Text = rest.Text[1:]
+		rest.Span.Start++
+
+		return mn(tok.Text[:1]), nil
+
+	default:
+		a, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+
+		return a.Node, nil
+	}
+}
+
+// parseGroup parses a braced group into a single node.
+func (p *parser) parseGroup() (html.Node, error) {
+	open := p.next()
+
+	children, err := p.parseRow()
+	if err != nil {
+		return nil, err
+	}
+
+	closer := p.peek()
+	if closer.Kind == tokenEOF {
+		return nil, errorAt(open.Span, "missing closing brace")
+	}
+	if closer.Kind != tokenCloseBrace {
+		return nil, unexpected(closer)
+	}
+	p.next()
+
+	return rowNode(children), nil
+}
+
+// parseAtom parses a single base without scripts.
+func (p *parser) parseAtom() (atom, error) {
+	tok := p.peek()
+
+	switch tok.Kind {
+	case tokenOpenBrace:
+		node, err := p.parseGroup()
+		if err != nil {
+			return atom{}, err
+		}
+
+		return atom{Node: node}, nil
+
+	case tokenLetter:
+		p.next()
+		return atom{Node: mi(tok.Text)}, nil
+
+	case tokenNumber:
+		p.next()
+		return atom{Node: mn(tok.Text)}, nil
+
+	case tokenSymbol:
+		p.next()
+		if tok.Text == "~" {
+			return atom{Node: mspace(spaces[" "])}, nil
+		}
+
+		return atom{Node: mo(symbolText(tok.Text))}, nil
+
+	case tokenSuperscript, tokenSubscript:
+		// scripts without a base attach to an empty row
+		return atom{Node: mrow()}, nil
+
+	case tokenCommand:
+		p.next()
+		return p.parseCommand(tok)
+
+	default:
+		return atom{}, unexpected(tok)
+	}
+}
+
+// symbolText maps ASCII operator characters to their typeset forms.
+func symbolText(s string) string {
+	switch s {
+	case "-":
+		return "−"
+	case "*":
+		return "∗"
+	case "'":
+		return "′"
+	default:
+		return s
+	}
+}
+
+// parseCommand parses the command tok and its arguments.
+func (p *parser) parseCommand(tok token) (atom, error) {
+	name := tok.Text
+
+	if s, ok := greekLetters[name]; ok {
+		r, _ := utf8.DecodeRuneInString(s)
+		if unicode.IsUpper(r) {
+			return atom{Node: miNormal(s)}, nil
+		}
+		return atom{Node: mi(s)}, nil
+	}
+
+	if s, ok := identifiers[name]; ok {
+		return atom{Node: mi(s)}, nil
+	}
+
+	if s, ok := operators[name]; ok {
+		return atom{Node: mo(s)}, nil
+	}
+
+	if op, ok := bigOperators[name]; ok {
+		return atom{Node: mo(op.Text), Limits: op.Limits}, nil
+	}
+
+	if fn, ok := namedFunctions[name]; ok {
+		return atom{Node: mi(fn.Text), Limits: fn.Limits}, nil
+	}
+
+	if width, ok := spaces[name]; ok {
+		return atom{Node: mspace(width)}, nil
+	}
+
+	if acc, ok := accents[name]; ok {
+		return p.parseAccent(tok, acc)
+	}
+
+	if _, ok := alphabets[name]; ok {
+		return p.parseFont(tok)
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac":
+		return p.parseFraction(tok)
+
+	case "binom":
+		return p.parseBinomial(tok)
+
+	case "sqrt":
+		return p.parseRoot(tok)
+
+	case "text", "textrm", "mbox":
+		text, err := p.rawGroup(tok)
+		if err != nil {
+			return atom{}, err
+		}
+
+		return atom{Node: element("mtext", nil, html.Text{Value: text})}, nil
+
+	case "mathrm", "operatorname":
+		return p.parseFont(tok)
+
+	case "left":
+		return p.parseFenced(tok)
+
+	case "begin":
+		return p.parseEnvironment(tok)
+
+	case "":
+		return atom{}, errorAt(tok.Span, "incomplete math command \\")
+
+	default:
+		return atom{}, errorAt(tok.Span, "unsupported math command \\%s", name)
+	}
+}
+
+func (p *parser) parseFraction(tok token) (atom, error) {
+	num, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	den, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	return atom{Node: element("mfrac", nil, num, den)}, nil
+}
+
+func (p *parser) parseBinomial(tok token) (atom, error) {
+	top, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	bottom, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	frac := element("mfrac", html.Attributes{"linethickness": "0"}, top, bottom)
+
+	return atom{Node: mrow(mo("("), frac, mo(")"))}, nil
+}
+
+// parseRoot parses \sqrt with an optional bracketed index.
+func (p *parser) parseRoot(tok token) (atom, error) {
+	var index html.Node
+
+	if open := p.peek(); open.Kind == tokenSymbol && open.Text == "[" {
+		p.next()
+
+		children, err := p.parseRowUntil(isSymbol("]"))
+		if err != nil {
+			return atom{}, err
+		}
+
+		if closer := p.peek(); closer.Kind != tokenSymbol || closer.Text != "]" {
+			return atom{}, errorAt(open.Span, "missing closing bracket for \\sqrt index")
+		}
+		p.next()
+
+		index = rowNode(children)
+	}
+
+	radicand, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	if index != nil {
+		return atom{Node: element("mroot", nil, radicand, index)}, nil
+	}
+
+	return atom{Node: element("msqrt", nil, radicand)}, nil
+}
+
+func (p *parser) parseAccent(tok token, acc accent) (atom, error) {
+	base, err := p.parseArgument(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	tag, attrName := "mover", "accent"
+	if acc.Under {
+		tag, attrName = "munder", "accentunder"
+	}
+
+	attr := html.Attributes{}
+	if acc.Accent {
+		attr[attrName] = "true"
+	}
+
+	node := element(tag, attr, base, mo(acc.Mark))
+
+	// braces take their scripts as limits, like big operators
+	return atom{Node: node, Limits: !acc.Accent}, nil
+}
+
+// parseFont parses a font command whose argument is a run of letters and
+// digits.
+func (p *parser) parseFont(tok token) (atom, error) {
+	text, err := p.rawGroup(tok)
+	if err != nil {
+		return atom{}, err
+	}
+
+	content := strings.Join(strings.Fields(text), "")
+	if content == "" {
+		return atom{Node: mrow()}, nil
+	}
+
+	for _, r := range content {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return atom{}, errorAt(tok.Span, "unsupported content in \\%s: expected letters and digits", tok.Text)
+		}
+	}
+
+	switch tok.Text {
+	case "operatorname":
+		return atom{Node: mi(content)}, nil
+
+	case "mathrm":
+		if utf8.RuneCountInString(content) == 1 {
+			return atom{Node: miNormal(content)}, nil
+		}
+		return atom{Node: mi(content)}, nil
+	}
+
+	alpha := alphabets[tok.Text]
+
+	var b strings.Builder
+	for _, r := range content {
+		styled, ok := alpha.styled(r)
+		if !ok {
+			return atom{}, errorAt(tok.Span, "unsupported character %q in \\%s", r, tok.Text)
+		}
+		b.WriteRune(styled)
+	}
+
+	return atom{Node: mi(b.String())}, nil
+}
+
+// parseFenced parses a \left ... \right group.
+func (p *parser) parseFenced(left token) (atom, error) {
+	open, err := p.parseDelimiter(left)
+	if err != nil {
+		return atom{}, err
+	}
+
+	children, err := p.parseRow()
+	if err != nil {
+		return atom{}, err
+	}
+
+	right := p.peek()
+	if right.Kind != tokenCommand || right.Text != "right" {
+		return atom{}, errorAt(left.Span, "\\left without matching \\right")
+	}
+	p.next()
+
+	closer, err := p.parseDelimiter(right)
+	if err != nil {
+		return atom{}, err
+	}
+
+	nodes := make([]html.Node, 0, len(children)+2)
+	if open != "" {
+		nodes = append(nodes, mo(open))
+	}
+	nodes = append(nodes, children...)
+	if closer != "" {
+		nodes = append(nodes, mo(closer))
+	}
+
+	return atom{Node: mrow(nodes...)}, nil
+}
+
+// parseDelimiter parses the delimiter following \left or \right. The
+// null delimiter "." yields an empty string.
+func (p *parser) parseDelimiter(owner token) (string, error) {
+	tok := p.peek()
+
+	switch tok.Kind {
+	case tokenSymbol:
+		switch tok.Text {
+		case ".":
+			p.next()
+			return "", nil
+		case "(", ")", "[", "]", "|", "/":
+			p.next()
+			return tok.Text, nil
+		case "<":
+			p.next()
+			return "⟨", nil
+		case ">":
+			p.next()
+			return "⟩", nil
+		}
+
+	case tokenCommand:
+		if d, ok := delimiters[tok.Text]; ok {
+			p.next()
+			return d, nil
+		}
+	}
+
+	return "", errorAt(owner.Span, "missing delimiter after \\%s", owner.Text)
+}
+
+// parseEnvironment parses a matrix-style environment into an mtable,
+// splitting cells on & and rows on \\.
+func (p *parser) parseEnvironment(begin token) (atom, error) {
+	rawName, err := p.rawGroup(begin)
+	if err != nil {
+		return atom{}, err
+	}
+
+	name := strings.TrimSpace(rawName)
+
+	env, ok := environments[name]
+	if !ok {
+		return atom{}, errorAt(begin.Span, "unsupported math environment %q", name)
+	}
+
+	rows := []html.Node{}
+	cells := []html.Node{}
+
+	for {
+		children, err := p.parseRow()
+		if err != nil {
+			return atom{}, err
+		}
+
+		cells = append(cells, element("mtd", nil, children...))
+
+		tok := p.next()
+
+		switch {
+		case tok.Kind == tokenAlign:
+			continue
+
+		case tok.Kind == tokenCommand && tok.Text == "\\":
+			rows = append(rows, element("mtr", nil, cells...))
+			cells = []html.Node{}
+			continue
+
+		case tok.Kind == tokenCommand && tok.Text == "end":
+			endName, err := p.rawGroup(tok)
+			if err != nil {
+				return atom{}, err
+			}
+
+			if strings.TrimSpace(endName) != name {
+				return atom{}, errorAt(tok.Span, "\\end{%s} does not match \\begin{%s}", strings.TrimSpace(endName), name)
+			}
+
+			// a trailing \\ does not introduce an empty final row
+			if len(rows) == 0 || len(cells) > 1 || len(children) > 0 {
+				rows = append(rows, element("mtr", nil, cells...))
+			}
+
+			return atom{Node: environmentNode(env, rows)}, nil
+
+		case tok.Kind == tokenEOF:
+			return atom{}, errorAt(begin.Span, "missing \\end{%s}", name)
+
+		default:
+			return atom{}, unexpected(tok)
+		}
+	}
+}
+
+func environmentNode(env environment, rows []html.Node) html.Node {
+	attr := html.Attributes{}
+	if env.ColumnAlign != "" {
+		attr["columnalign"] = env.ColumnAlign
+	}
+
+	table := element("mtable", attr, rows...)
+
+	if env.Open == "" && env.Close == "" {
+		return table
+	}
+
+	nodes := []html.Node{}
+	if env.Open != "" {
+		nodes = append(nodes, mo(env.Open))
+	}
+	nodes = append(nodes, table)
+	if env.Close != "" {
+		nodes = append(nodes, mo(env.Close))
+	}
+
+	return mrow(nodes...)
+}
+
+// rawGroup consumes a braced group and returns its source text verbatim,
+// with line boundaries replaced by spaces.
+func (p *parser) rawGroup(owner token) (string, error) {
+	open := p.peek()
+	if open.Kind != tokenOpenBrace {
+		return "", errorAt(owner.Span, "missing argument for %s", describe(owner))
+	}
+
+	depth := 0
+	for i := p.Index; i < len(p.Tokens); i++ {
+		tok := p.Tokens[i]
+
+		switch tok.Kind {
+		case tokenOpenBrace:
+			depth++
+
+		case tokenCloseBrace:
+			depth--
+			if depth == 0 {
+				p.Index = i + 1
+				return p.between(open, tok), nil
+			}
+
+		case tokenEOF:
+			return "", errorAt(open.Span, "missing closing brace")
+		}
+	}
+
+	return "", errorAt(open.Span, "missing closing brace")
+}
+
+// between returns the source text strictly between two tokens, joining
+// text from different input spans with a space.
+func (p *parser) between(open, closer token) string {
+	if open.Segment == closer.Segment {
+		return p.Source.Slice(source.ByteSpan{Start: open.Span.End, End: closer.Span.Start})
+	}
+
+	parts := []string{
+		p.Source.Slice(source.ByteSpan{Start: open.Span.End, End: p.Spans[open.Segment].End}),
+	}
+
+	for seg := open.Segment + 1; seg < closer.Segment; seg++ {
+		parts = append(parts, p.Source.Slice(p.Spans[seg]))
+	}
+
+	parts = append(parts, p.Source.Slice(source.ByteSpan{Start: p.Spans[closer.Segment].Start, End: closer.Span.Start}))
+
+	return strings.Join(parts, " ")
+}
+
+// unexpected reports a token that cannot appear where it was found.
+func unexpected(tok token) error {
+	switch {
+	case tok.Kind == tokenCloseBrace:
+		return errorAt(tok.Span, "unbalanced closing brace")
+	case tok.Kind == tokenAlign:
+		return errorAt(tok.Span, "alignment tab & outside of an environment")
+	case tok.Kind == tokenCommand && tok.Text == "\\":
+		return errorAt(tok.Span, "line break \\\\ outside of an environment")
+	case tok.Kind == tokenCommand && tok.Text == "end":
+		return errorAt(tok.Span, "\\end without matching \\begin")
+	case tok.Kind == tokenCommand && tok.Text == "right":
+		return errorAt(tok.Span, "\\right without matching \\left")
+	case tok.Kind == tokenEOF:
+		return errorAt(tok.Span, "unexpected end of math")
+	default:
+		return errorAt(tok.Span, "unexpected %q in math", tok.Text)
+	}
+}
+
+// describe names a token for use in a diagnostic message.
+func describe(tok token) string {
+	switch tok.Kind {
+	case tokenCommand:
+		return "\\" + tok.Text
+	case tokenSuperscript:
+		return "superscript"
+	case tokenSubscript:
+		return "subscript"
+	default:
+		return fmt.Sprintf("%q", tok.Text)
+	}
+}
+
+func isSymbol(s string) func(token) bool {
+	return func(tok token) bool {
+		return tok.Kind == tokenSymbol && tok.Text == s
+	}
+}
+
+func errorAt(span source.ByteSpan, format string, args ...any) error {
+	return diagnostic.DiagnosticError{
+		Diagnostic: diagnostic.Diagnostic{
+			Message:  fmt.Sprintf(format, args...),
+			Span:     span,
+			Severity: diagnostic.SeverityError,
+		},
+	}
+}
+
+// node constructors
+
+func element(tag string, attr html.Attributes, children ...html.Node) html.Element {
+	if attr == nil {
+		attr = html.Attributes{}
+	}
+
+	if children == nil {
+		children = []html.Node{}
+	}
+
+	return html.Element{
+		Tag:      tag,
+		Attr:     attr,
+		Children: children,
+	}
+}
+
+func mi(s string) html.Element {
+	return element("mi", nil, html.Text{Value: s})
+}
+
+// miNormal returns an upright identifier; single-character identifiers
+// are otherwise rendered in italics.
+func miNormal(s string) html.Element {
+	return element("mi", html.Attributes{"mathvariant": "normal"}, html.Text{Value: s})
+}
+
+func mn(s string) html.Element {
+	return element("mn", nil, html.Text{Value: s})
+}
+
+func mo(s string) html.Element {
+	return element("mo", nil, html.Text{Value: s})
+}
+
+// mspace returns a space of the given width. It is written with an
+// explicit end tag, since MathML elements are not void in HTML.
+func mspace(width string) html.Element {
+	return element("mspace", html.Attributes{"width": width})
+}
+
+func mrow(children ...html.Node) html.Element {
+	return element("mrow", nil, children...)
+}
+
+// rowNode returns the single node in children, or an mrow wrapping them.
+func rowNode(children []html.Node) html.Node {
+	if len(children) == 1 {
+		return children[0]
+	}
+
+	return mrow(children...)
+}
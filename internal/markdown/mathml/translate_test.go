@@ -0,0 +1,275 @@
+package mathml_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/diagnostic"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/html"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/mathml"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown/source"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestTranslate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		display bool
+		want    string
+	}{
+		// Atoms
+
+		{
+			name:  "identifiers, numbers, and operators",
+			input: "x + 3.14 = y - 1",
+			want:  "<math><mi>x</mi><mo>+</mo><mn>3.14</mn><mo>=</mo><mi>y</mi><mo>−</mo><mn>1</mn></math>",
+		},
+		{
+			name:  "greek letters",
+			input: `\alpha \Omega`,
+			want:  `<math><mi>α</mi><mi mathvariant="normal">Ω</mi></math>`,
+		},
+		{
+			name:  "relations and arrows",
+			input: `a \le b \to c`,
+			want:  "<math><mi>a</mi><mo>≤</mo><mi>b</mi><mo>→</mo><mi>c</mi></math>",
+		},
+		{
+			name:  "escaped characters are literal",
+			input: `\{ \% \}`,
+			want:  "<math><mo>{</mo><mo>%</mo><mo>}</mo></math>",
+		},
+		{
+			name:  "comments are ignored",
+			input: `x % trailing comment`,
+			want:  "<math><mi>x</mi></math>",
+		},
+
+		// Scripts
+
+		{
+			name:  "superscript and subscript",
+			input: "x_i^2",
+			want:  "<math><msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup></math>",
+		},
+		{
+			name:  "braced script",
+			input: "e^{i\\pi}",
+			want:  "<math><msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup></math>",
+		},
+		{
+			name:  "unbraced script takes a single digit",
+			input: "x^23",
+			want:  "<math><msup><mi>x</mi><mn>2</mn></msup><mn>3</mn></math>",
+		},
+		{
+			name:  "primes",
+			input: "f''",
+			want:  "<math><msup><mi>f</mi><mo>′′</mo></msup></math>",
+		},
+
+		// Fractions and roots
+
+		{
+			name:  "fraction",
+			input: `\frac{a}{b}`,
+			want:  "<math><mfrac><mi>a</mi><mi>b</mi></mfrac></math>",
+		},
+		{
+			name:  "fraction with unbraced digits",
+			input: `\frac12`,
+			want:  "<math><mfrac><mn>1</mn><mn>2</mn></mfrac></math>",
+		},
+		{
+			name:  "binomial",
+			input: `\binom{n}{k}`,
+			want:  `<math><mrow><mo>(</mo><mfrac linethickness="0"><mi>n</mi><mi>k</mi></mfrac><mo>)</mo></mrow></math>`,
+		},
+		{
+			name:  "square root and nth root",
+			input: `\sqrt{x} \sqrt[3]{y}`,
+			want:  "<math><msqrt><mi>x</mi></msqrt><mroot><mi>y</mi><mn>3</mn></mroot></math>",
+		},
+
+		// Big operators
+
+		{
+			name:  "sum takes scripts inline",
+			input: `\sum_{i=1}^n i`,
+			want:  "<math><msubsup><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup><mi>i</mi></math>",
+		},
+		{
+			name:    "sum takes limits in display",
+			input:   `\sum_{i=1}^n i`,
+			display: true,
+			want:    `<math display="block"><munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><mi>i</mi></math>`,
+		},
+		{
+			name:    "integral keeps scripts in display",
+			input:   `\int_0^1 f`,
+			display: true,
+			want:    `<math display="block"><msubsup><mo>∫</mo><mn>0</mn><mn>1</mn></msubsup><mi>f</mi></math>`,
+		},
+		{
+			name:    "named function with limits",
+			input:   `\lim_{x \to 0} \sin x`,
+			display: true,
+			want:    `<math display="block"><munder><mi>lim</mi><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder><mi>sin</mi><mi>x</mi></math>`,
+		},
+
+		// Accents, fonts, and text
+
+		{
+			name:  "accent",
+			input: `\vec{v}`,
+			want:  `<math><mover accent="true"><mi>v</mi><mo>→</mo></mover></math>`,
+		},
+		{
+			name:  "blackboard bold",
+			input: `\mathbb{R}`,
+			want:  "<math><mi>ℝ</mi></math>",
+		},
+		{
+			name:  "upright roman letter",
+			input: `\mathrm{d}x`,
+			want:  `<math><mi mathvariant="normal">d</mi><mi>x</mi></math>`,
+		},
+		{
+			name:  "text preserves spacing",
+			input: `x \text{ if } y`,
+			want:  "<math><mi>x</mi><mtext> if </mtext><mi>y</mi></math>",
+		},
+		{
+			name:  "spacing commands",
+			input: `a\,b\quad c`,
+			want:  `<math><mi>a</mi><mspace width="0.1667em"></mspace><mi>b</mi><mspace width="1em"></mspace><mi>c</mi></math>`,
+		},
+
+		// Delimiters and environments
+
+		{
+			name:  "left and right delimiters",
+			input: `\left( x \right.`,
+			want:  "<math><mrow><mo>(</mo><mi>x</mi></mrow></math>",
+		},
+		{
+			name:    "matrix environment",
+			input:   `\begin{bmatrix} 1 & 0 \\ 0 & 1 \\ \end{bmatrix}`,
+			display: true,
+			want:    `<math display="block"><mrow><mo>[</mo><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable><mo>]</mo></mrow></math>`,
+		},
+		{
+			name:  "cases environment",
+			input: `\begin{cases} 1 & x \\ 0 \end{cases}`,
+			want:  `<math><mrow><mo>{</mo><mtable columnalign="left left"><mtr><mtd><mn>1</mn></mtd><mtd><mi>x</mi></mtd></mtr><mtr><mtd><mn>0</mn></mtd></mtr></mtable></mrow></math>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := source.NewSource(tc.input)
+			span := source.ByteSpan{Start: 0, End: src.EOF()}
+
+			node, err := mathml.Translate(src, []source.ByteSpan{span}, tc.display)
+			require.NoError(t, err)
+
+			got, err := html.Render(node)
+			require.NoError(t, err)
+
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestTranslate_MultipleSpans(t *testing.T) {
+	src := source.NewSource("\\text{a\nb} + x\n")
+
+	spans := []source.ByteSpan{
+		{Start: 0, End: 7},
+		{Start: 8, End: 14},
+	}
+
+	node, err := mathml.Translate(src, spans, true)
+	require.NoError(t, err)
+
+	got, err := html.Render(node)
+	require.NoError(t, err)
+
+	assert.Equal(t, got, `<math display="block"><mtext>a b</mtext><mo>+</mo><mi>x</mi></math>`)
+}
+
+func TestTranslate_Diagnostics(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		wantMessage string
+		wantSpan    source.ByteSpan
+	}{
+		{
+			name:        "unsupported command",
+			input:       `x + \foo{y}`,
+			wantMessage: `unsupported math command \foo`,
+			wantSpan:    source.ByteSpan{Start: 4, End: 8},
+		},
+		{
+			name:        "unsupported environment",
+			input:       `\begin{tabular}x\end{tabular}`,
+			wantMessage: `unsupported math environment "tabular"`,
+			wantSpan:    source.ByteSpan{Start: 0, End: 6},
+		},
+		{
+			name:        "missing closing brace",
+			input:       `\frac{a`,
+			wantMessage: "missing closing brace",
+			wantSpan:    source.ByteSpan{Start: 5, End: 6},
+		},
+		{
+			name:        "unbalanced closing brace",
+			input:       `a}`,
+			wantMessage: "unbalanced closing brace",
+			wantSpan:    source.ByteSpan{Start: 1, End: 2},
+		},
+		{
+			name:        "double superscript",
+			input:       `x^a^b`,
+			wantMessage: "double superscript",
+			wantSpan:    source.ByteSpan{Start: 3, End: 4},
+		},
+		{
+			name:        "missing argument",
+			input:       `\sqrt`,
+			wantMessage: `missing argument for \sqrt`,
+			wantSpan:    source.ByteSpan{Start: 0, End: 5},
+		},
+		{
+			name:        "left without right",
+			input:       `\left( x`,
+			wantMessage: `\left without matching \right`,
+			wantSpan:    source.ByteSpan{Start: 0, End: 5},
+		},
+		{
+			name:        "alignment outside an environment",
+			input:       `a & b`,
+			wantMessage: "alignment tab & outside of an environment",
+			wantSpan:    source.ByteSpan{Start: 2, End: 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := source.NewSource(tc.input)
+			span := source.ByteSpan{Start: 0, End: src.EOF()}
+
+			_, err := mathml.Translate(src, []source.ByteSpan{span}, false)
+
+			var diagErr diagnostic.DiagnosticError
+			require.True(t, errors.As(err, &diagErr))
+
+			assert.Equal(t, diagErr.Diagnostic.Message, tc.wantMessage)
+			assert.Equal(t, diagErr.Diagnostic.Span, tc.wantSpan)
+			assert.Equal(t, diagErr.Diagnostic.Severity, diagnostic.SeverityError)
+		})
+	}
+}
@@ -22,6 +22,16 @@ func ASTBlockQuote(blocks ...ast.Block) ast.BlockQuote {
 	}
 }
 
+func ASTCallout(kind string, titleLevel int, title []ast.Inline, blocks ...ast.Block) ast.Callout {
+	return ast.Callout{
+		Span:       source.ByteSpan{},
+		Kind:       kind,
+		Title:      title,
+		TitleLevel: titleLevel,
+		Children:   blocks,
+	}
+}
+
 func ASTHeader(level int, inlines ...ast.Inline) ast.Header {
 	return ast.Header{
 		Span:    source.ByteSpan{},
@@ -60,6 +70,36 @@ func ASTListItem(blocks ...ast.Block) ast.ListItem {
 	}
 }
 
+func ASTDefinitionList(tight bool, items ...ast.DefinitionItem) ast.DefinitionList {
+	return ast.DefinitionList{
+		Span:  source.ByteSpan{},
+		Items: items,
+		Tight: tight,
+	}
+}
+
+func ASTDefinitionItem(terms []ast.DefinitionTerm, definitions ...ast.Definition) ast.DefinitionItem {
+	return ast.DefinitionItem{
+		Span:        source.ByteSpan{},
+		Terms:       terms,
+		Definitions: definitions,
+	}
+}
+
+func ASTDefinitionTerm(inlines ...ast.Inline) ast.DefinitionTerm {
+	return ast.DefinitionTerm{
+		Span:    source.ByteSpan{},
+		Inlines: inlines,
+	}
+}
+
+func ASTDefinition(blocks ...ast.Block) ast.Definition {
+	return ast.Definition{
+		Span:     source.ByteSpan{},
+		Children: blocks,
+	}
+}
+
 func ASTIndentedCodeBlock(inlines ...ast.Inline) ast.CodeBlock {
 	return ast.CodeBlock{
 		Span:              source.ByteSpan{},
@@ -85,6 +125,31 @@ func ASTHTMLBlock(inlines ...ast.Inline) ast.HTMLBlock {
 	}
 }
 
+// ASTMathBlock constructs a math block with one line per sample for
+// structural AST comparisons.
+func ASTMathBlock(input ...string) ast.MathBlock {
+	lines := make([]source.ByteSpan, len(input))
+
+	return ast.MathBlock{
+		Span:  source.ByteSpan{},
+		Lines: lines,
+	}
+}
+
+func ASTLineBlock(lines ...ast.Line) ast.LineBlock {
+	return ast.LineBlock{
+		Span:  source.ByteSpan{},
+		Lines: lines,
+	}
+}
+
+func ASTLine(indent int, inlines ...ast.Inline) ast.Line {
+	return ast.Line{
+		Indent:  indent,
+		Inlines: inlines,
+	}
+}
+
 func ASTPara(inlines ...ast.Inline) ast.Paragraph {
 	return ast.Paragraph{
 		Span:    source.ByteSpan{},
@@ -142,6 +207,43 @@ func ASTCodeSpan(_ ...string) ast.CodeSpan {
 	}
 }
 
+func ASTSmartPunct(kind ast.PunctKind) ast.SmartPunct {
+	return ast.SmartPunct{
+		Span: source.ByteSpan{},
+		Kind: kind,
+	}
+}
+
+// ASTEmoji constructs an emoji node for structural AST comparisons.
+func ASTEmoji(value, description string, labeled bool) ast.Emoji {
+	return ast.Emoji{
+		Span:        source.ByteSpan{},
+		Value:       value,
+		Description: description,
+		Labeled:     labeled,
+	}
+}
+
+// ASTAbbreviation constructs an abbreviation node for structural AST
+// comparisons. Optional samples are ignored and exist only to improve test
+// readability.
+func ASTAbbreviation(_ ...string) ast.Abbreviation {
+	return ast.Abbreviation{
+		Span:  source.ByteSpan{},
+		Title: source.ByteSpan{},
+	}
+}
+
+// ASTMath constructs an inline math node for structural AST comparisons.
+// Optional samples are ignored and exist only to improve test readability.
+func ASTMath(display bool, _ ...string) ast.Math {
+	return ast.Math{
+		Span:    source.ByteSpan{},
+		Content: source.ByteSpan{},
+		Display: display,
+	}
+}
+
 func ASTSoftBreak() ast.SoftBreak {
 	return ast.SoftBreak{
 		Span: source.ByteSpan{},
@@ -186,6 +288,15 @@ func NormalizeASTBlocks(blocks []ast.Block) []ast.Block {
 			b.Children = NormalizeASTBlocks(b.Children)
 			blocks[i] = b
 
+		case ast.Callout:
+			b.Span = source.ByteSpan{}
+			b.Title = NormalizeASTInlines(b.Title)
+			if b.Children == nil {
+				b.Children = []ast.Block{}
+			}
+			b.Children = NormalizeASTBlocks(b.Children)
+			blocks[i] = b
+
 		case ast.Header:
 			b.Span = source.ByteSpan{}
 			b.Inlines = NormalizeASTInlines(b.Inlines)
@@ -235,6 +346,39 @@ func NormalizeASTBlocks(blocks []ast.Block) []ast.Block {
 			b.Children = NormalizeASTBlocks(b.Children)
 			blocks[i] = b
 
+		case ast.DefinitionList:
+			b.Span = source.ByteSpan{}
+			if b.Items == nil {
+				b.Items = []ast.DefinitionItem{}
+			}
+			for j := range b.Items {
+				item := b.Items[j]
+				item.Span = source.ByteSpan{}
+				if item.Terms == nil {
+					item.Terms = []ast.DefinitionTerm{}
+				}
+				for k := range item.Terms {
+					term := item.Terms[k]
+					term.Span = source.ByteSpan{}
+					term.Inlines = NormalizeASTInlines(term.Inlines)
+					item.Terms[k] = term
+				}
+				if item.Definitions == nil {
+					item.Definitions = []ast.Definition{}
+				}
+				for k := range item.Definitions {
+					def := item.Definitions[k]
+					def.Span = source.ByteSpan{}
+					if def.Children == nil {
+						def.Children = []ast.Block{}
+					}
+					def.Children = NormalizeASTBlocks(def.Children)
+					item.Definitions[k] = def
+				}
+				b.Items[j] = item
+			}
+			blocks[i] = b
+
 		case ast.CodeBlock:
 			b.Span = source.ByteSpan{}
 			b.LanguageTokenSpan = source.ByteSpan{}
@@ -246,6 +390,26 @@ func NormalizeASTBlocks(blocks []ast.Block) []ast.Block {
 			b.Payload = NormalizeASTInlines(b.Payload)
 			blocks[i] = b
 
+		case ast.MathBlock:
+			b.Span = source.ByteSpan{}
+			if b.Lines == nil {
+				b.Lines = []source.ByteSpan{}
+			}
+			for j := range b.Lines {
+				b.Lines[j] = source.ByteSpan{}
+			}
+			blocks[i] = b
+
+		case ast.LineBlock:
+			b.Span = source.ByteSpan{}
+			if b.Lines == nil {
+				b.Lines = []ast.Line{}
+			}
+			for j := range b.Lines {
+				b.Lines[j].Inlines = NormalizeASTInlines(b.Lines[j].Inlines)
+			}
+			blocks[i] = b
+
 		case ast.Paragraph:
 			b.Span = source.ByteSpan{}
 			b.Inlines = NormalizeASTInlines(b.Inlines)
@@ -276,6 +440,11 @@ func NormalizeASTInlines(inl []ast.Inline) []ast.Inline {
 			v.Children = NormalizeASTInlines(v.Children)
 			out = append(out, v)
 
+		case ast.BracketedSpan:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
 		case ast.Emph:
 			v.Span = source.ByteSpan{}
 			v.Children = NormalizeASTInlines(v.Children)
@@ -286,6 +455,31 @@ func NormalizeASTInlines(inl []ast.Inline) []ast.Inline {
 			v.Children = NormalizeASTInlines(v.Children)
 			out = append(out, v)
 
+		case ast.Superscript:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
+		case ast.Subscript:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
+		case ast.Strikethrough:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
+		case ast.Highlight:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
+		case ast.Insert:
+			v.Span = source.ByteSpan{}
+			v.Children = NormalizeASTInlines(v.Children)
+			out = append(out, v)
+
 		case ast.Text:
 			v.Span = source.ByteSpan{}
 			out = append(out, v)
@@ -294,6 +488,31 @@ func NormalizeASTInlines(inl []ast.Inline) []ast.Inline {
 			v.Span = source.ByteSpan{}
 			out = append(out, v)
 
+		case ast.SmartPunct:
+			v.Span = source.ByteSpan{}
+			out = append(out, v)
+
+		case ast.Emoji:
+			v.Span = source.ByteSpan{}
+			out = append(out, v)
+
+		case ast.Abbreviation:
+			v.Span = source.ByteSpan{}
+			v.Title = source.ByteSpan{}
+			out = append(out, v)
+
+		case ast.WikiLink:
+			v.Span = source.ByteSpan{}
+			v.Target = source.ByteSpan{}
+			v.Fragment = source.ByteSpan{}
+			v.Label = source.ByteSpan{}
+			out = append(out, v)
+
+		case ast.Math:
+			v.Span = source.ByteSpan{}
+			v.Content = source.ByteSpan{}
+			out = append(out, v)
+
 		case ast.HardBreak:
 			v.Span = source.ByteSpan{}
 			out = append(out, v)
@@ -72,6 +72,31 @@ func IRListItem(children ...ir.Block) ir.ListItem {
 	}
 }
 
+func IRDefinitionList(tight bool, items ...ir.DefinitionItem) ir.DefinitionList {
+	return ir.DefinitionList{
+		Span:  source.ByteSpan{},
+		Items: items,
+		Tight: tight,
+	}
+}
+
+// IRDefinitionItem constructs a definition item with the given number of
+// terms. Term spans are zeroed for structural comparison.
+func IRDefinitionItem(terms int, definitions ...ir.Definition) ir.DefinitionItem {
+	return ir.DefinitionItem{
+		Span:        source.ByteSpan{},
+		Terms:       make([]source.ByteSpan, terms),
+		Definitions: definitions,
+	}
+}
+
+func IRDefinition(children ...ir.Block) ir.Definition {
+	return ir.Definition{
+		Span:     source.ByteSpan{},
+		Children: children,
+	}
+}
+
 func IRIndentedCodeBlock(input ...string) ir.IndentedCodeBlock {
 	lines := make([]source.ByteSpan, len(input))
 
@@ -101,6 +126,31 @@ func IRHTMLBlock(input ...string) ir.HTMLBlock {
 	}
 }
 
+func IRMathBlock(input ...string) ir.MathBlock {
+	lines := make([]source.ByteSpan, len(input))
+
+	return ir.MathBlock{
+		Span:  source.ByteSpan{},
+		Lines: lines,
+	}
+}
+
+func IRLineBlock(lines ...ir.LineBlockLine) ir.LineBlock {
+	return ir.LineBlock{
+		Span:  source.ByteSpan{},
+		Lines: lines,
+	}
+}
+
+// IRLineBlockLine constructs a line block line with the given indentation
+// and number of content spans. Spans are zeroed for structural comparison.
+func IRLineBlockLine(indent, spans int) ir.LineBlockLine {
+	return ir.LineBlockLine{
+		Indent: indent,
+		Spans:  make([]source.ByteSpan, spans),
+	}
+}
+
 func IRPara(input ...string) ir.Paragraph {
 	lines := make([]source.ByteSpan, len(input))
 
@@ -110,6 +160,50 @@ func IRPara(input ...string) ir.Paragraph {
 	}
 }
 
+// IRWithAttributes returns block carrying n attribute list spans. The spans
+// are zeroed for structural comparison.
+func IRWithAttributes(block ir.Block, n int) ir.Block {
+	attrs := make([]source.ByteSpan, n)
+
+	switch b := block.(type) {
+	case ir.BlockQuote:
+		b.Attributes = attrs
+		return b
+	case ir.Header:
+		b.Attributes = attrs
+		return b
+	case ir.ThematicBreak:
+		b.Attributes = attrs
+		return b
+	case ir.OrderedList:
+		b.Attributes = attrs
+		return b
+	case ir.UnorderedList:
+		b.Attributes = attrs
+		return b
+	case ir.DefinitionList:
+		b.Attributes = attrs
+		return b
+	case ir.IndentedCodeBlock:
+		b.Attributes = attrs
+		return b
+	case ir.FencedCodeBlock:
+		b.Attributes = attrs
+		return b
+	case ir.MathBlock:
+		b.Attributes = attrs
+		return b
+	case ir.LineBlock:
+		b.Attributes = attrs
+		return b
+	case ir.Paragraph:
+		b.Attributes = attrs
+		return b
+	default:
+		panic(fmt.Sprintf("block type %T does not accept attributes", b))
+	}
+}
+
 // NormalizeIR clears source-specific fields so IR values can be compared
 // structurally in tests.
 func NormalizeIR(doc ir.Document) ir.Document {
@@ -124,6 +218,7 @@ func NormalizeIR(doc ir.Document) ir.Document {
 		doc.Definitions = map[string]ir.ReferenceDefinition{}
 	}
 	doc.Definitions = NormalizeIRDefinitions(doc.Definitions)
+	doc.Abbreviations = NormalizeIRAbbreviations(doc.Abbreviations)
 
 	return doc
 }
@@ -135,6 +230,7 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 		switch b := blocks[i].(type) {
 		case ir.BlockQuote:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			if b.Children == nil {
 				b.Children = []ir.Block{}
 			}
@@ -143,16 +239,19 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 
 		case ir.Header:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			b.ContentSpan = source.ByteSpan{}
 			b.ContentLines = []source.ByteSpan{}
 			blocks[i] = b
 
 		case ir.ThematicBreak:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			blocks[i] = b
 
 		case ir.OrderedList:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			if b.Items == nil {
 				b.Items = []ir.ListItem{}
 			}
@@ -169,6 +268,7 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 
 		case ir.UnorderedList:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			if b.Items == nil {
 				b.Items = []ir.ListItem{}
 			}
@@ -191,8 +291,40 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 			b.Children = NormalizeIRBlocks(b.Children)
 			blocks[i] = b
 
+		case ir.DefinitionList:
+			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
+			if b.Items == nil {
+				b.Items = []ir.DefinitionItem{}
+			}
+			for j := range b.Items {
+				item := b.Items[j]
+				item.Span = source.ByteSpan{}
+				if item.Terms == nil {
+					item.Terms = []source.ByteSpan{}
+				}
+				for k := range item.Terms {
+					item.Terms[k] = source.ByteSpan{}
+				}
+				if item.Definitions == nil {
+					item.Definitions = []ir.Definition{}
+				}
+				for k := range item.Definitions {
+					def := item.Definitions[k]
+					def.Span = source.ByteSpan{}
+					if def.Children == nil {
+						def.Children = []ir.Block{}
+					}
+					def.Children = NormalizeIRBlocks(def.Children)
+					item.Definitions[k] = def
+				}
+				b.Items[j] = item
+			}
+			blocks[i] = b
+
 		case ir.IndentedCodeBlock:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			if b.Lines == nil {
 				b.Lines = []source.ByteSpan{}
 			}
@@ -203,6 +335,7 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 
 		case ir.FencedCodeBlock:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			b.InfoStringSpan = source.ByteSpan{}
 			if b.Lines == nil {
 				b.Lines = []source.ByteSpan{}
@@ -222,8 +355,31 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 			}
 			blocks[i] = b
 
+		case ir.MathBlock:
+			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
+			if b.Lines == nil {
+				b.Lines = []source.ByteSpan{}
+			}
+			for j := range b.Lines {
+				b.Lines[j] = source.ByteSpan{}
+			}
+			blocks[i] = b
+
+		case ir.LineBlock:
+			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
+			if b.Lines == nil {
+				b.Lines = []ir.LineBlockLine{}
+			}
+			for j := range b.Lines {
+				zeroSpans(b.Lines[j].Spans)
+			}
+			blocks[i] = b
+
 		case ir.Paragraph:
 			b.Span = source.ByteSpan{}
+			zeroSpans(b.Attributes)
 			if b.Lines == nil {
 				b.Lines = []source.ByteSpan{}
 			}
@@ -240,6 +396,13 @@ func NormalizeIRBlocks(blocks []ir.Block) []ir.Block {
 	return blocks
 }
 
+// zeroSpans clears each span in spans in place, preserving its length.
+func zeroSpans(spans []source.ByteSpan) {
+	for i := range spans {
+		spans[i] = source.ByteSpan{}
+	}
+}
+
 // NormalizeIRDefinitions strips source spans and retains only the
 // semantic fields required for comparison.
 func NormalizeIRDefinitions(defs map[string]ir.ReferenceDefinition) map[string]ir.ReferenceDefinition {
@@ -257,3 +420,16 @@ func NormalizeIRDefinitions(defs map[string]ir.ReferenceDefinition) map[string]i
 
 	return out
 }
+
+// NormalizeIRAbbreviations clears span-bearing fields from abbreviation
+// definitions, keeping only their terms.
+func NormalizeIRAbbreviations(defs map[string]ir.AbbreviationDefinition) map[string]ir.AbbreviationDefinition {
+	out := make(map[string]ir.AbbreviationDefinition, len(defs))
+	for k, def := range defs {
+		out[k] = ir.AbbreviationDefinition{
+			Term: def.Term,
+		}
+	}
+
+	return out
+}
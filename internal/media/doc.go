@@ -0,0 +1,8 @@
+// Package media processes the images that accompany site content.
+//
+// A Processor decodes JPEG, PNG, and GIF images with the standard library,
+// resamples them to a configured set of widths, and writes each variant
+// under a name derived from the source file's content hash. Encoded
+// variants are kept in a cache directory keyed by that hash, so an
+// unchanged image is copied rather than decoded and resized again.
+package media
@@ -0,0 +1,281 @@
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JPEGQuality is the quality at which JPEG variants are encoded.
+const JPEGQuality = 85
+
+// Options configures a Processor.
+//
+// Widths lists the variant widths to generate, in pixels. Widths at or
+// beyond the width of a source image are skipped, since variants are never
+// upscaled. CacheDir, when set, holds encoded variants between runs.
+type Options struct {
+	Widths   []int
+	CacheDir string
+}
+
+// Image describes a processed source image and the variants written for it.
+type Image struct {
+	Width    int
+	Height   int
+	Variants []Variant
+}
+
+// Variant is a resized copy of a source image. Name is the variant's file
+// name, written alongside the copy of its source.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Processor generates resized image variants.
+type Processor struct {
+	opts Options
+}
+
+// NewProcessor returns a Processor configured by opts.
+func NewProcessor(opts Options) *Processor {
+	return &Processor{opts: opts}
+}
+
+// IsImage reports whether path names a file the Processor can resize,
+// judged by its extension.
+func IsImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png", ".gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// Process generates the variants of the image at srcPath and writes them
+// to dstDir, returning the image description and the paths written.
+//
+// Variants are named after the source file, its content hash, and their
+// width, so a changed image never reuses a stale name. GIF variants are
+// encoded as PNG, and animated GIFs are left without variants.
+func (p *Processor) Process(srcPath, dstDir string) (Image, []string, error) {
+	src, err := readSource(srcPath)
+	if err != nil {
+		return Image{}, nil, err
+	}
+
+	img, hash, ext := p.plan(srcPath, src)
+
+	// the source is decoded only if some variant is missing from the cache
+	var decoded image.Image
+	decode := func() (image.Image, error) {
+		if decoded != nil {
+			return decoded, nil
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(src.data))
+		if err != nil {
+			return nil, err
+		}
+
+		decoded = img
+		return decoded, nil
+	}
+
+	var written []string
+	for _, v := range img.Variants {
+		dstPath := filepath.Join(dstDir, v.Name)
+		if err := p.writeVariant(dstPath, hash, ext, v, decode); err != nil {
+			return Image{}, nil, fmt.Errorf("variant: %s: %w", srcPath, err)
+		}
+
+		written = append(written, dstPath)
+	}
+
+	return img, written, nil
+}
+
+// Plan describes the image at srcPath and the variants Process would
+// write for it, without decoding the image in full or writing anything.
+func (p *Processor) Plan(srcPath string) (Image, error) {
+	src, err := readSource(srcPath)
+	if err != nil {
+		return Image{}, err
+	}
+
+	img, _, _ := p.plan(srcPath, src)
+	return img, nil
+}
+
+// plan describes src and names its variants, returning the description
+// along with the source's content hash and the variants' extension.
+func (p *Processor) plan(srcPath string, src source) (Image, string, string) {
+	cfg := src.cfg
+	img := Image{
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+
+	if src.animated {
+		return img, "", ""
+	}
+
+	sum := sha256.Sum256(src.data)
+	hash := hex.EncodeToString(sum[:])
+
+	stem := strings.TrimSuffix(filepath.Base(srcPath), filepath.Ext(srcPath))
+	ext := variantExt(src.format)
+
+	for _, w := range p.opts.Widths {
+		if w <= 0 || w >= cfg.Width {
+			continue
+		}
+
+		img.Variants = append(img.Variants, Variant{
+			Name:   fmt.Sprintf("%s-%s-%d%s", stem, hash[:12], w, ext),
+			Width:  w,
+			Height: max(1, (cfg.Height*w+cfg.Width/2)/cfg.Width),
+		})
+	}
+
+	return img, hash, ext
+}
+
+// Decode reads and fully decodes the image at srcPath, as Process does
+// before resizing it, without writing anything. The returned Image has no
+// variants.
+func Decode(srcPath string) (Image, error) {
+	src, err := readSource(srcPath)
+	if err != nil {
+		return Image{}, err
+	}
+
+	// an animated GIF was decoded in full by readSource
+	if !src.animated {
+		if _, _, err := image.Decode(bytes.NewReader(src.data)); err != nil {
+			return Image{}, fmt.Errorf("decode: %s: %w", srcPath, err)
+		}
+	}
+
+	return Image{Width: src.cfg.Width, Height: src.cfg.Height}, nil
+}
+
+// source is an image file read and checked by readSource.
+type source struct {
+	data     []byte
+	cfg      image.Config
+	format   string
+	animated bool
+}
+
+// readSource reads the image at srcPath and decodes its header. GIFs are
+// decoded in full to learn whether they are animated.
+func readSource(srcPath string) (source, error) {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return source{}, fmt.Errorf("read: %s: %w", srcPath, err)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return source{}, fmt.Errorf("decode: %s: %w", srcPath, err)
+	}
+
+	src := source{data: data, cfg: cfg, format: format}
+
+	if format == "gif" {
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return source{}, fmt.Errorf("decode: %s: %w", srcPath, err)
+		}
+		src.animated = len(anim.Image) > 1
+	}
+
+	return src, nil
+}
+
+// writeVariant writes v to dstPath, copying it from the cache when present
+// and otherwise resizing the decoded source and caching the result.
+func (p *Processor) writeVariant(dstPath, hash, ext string, v Variant, decode func() (image.Image, error)) error {
+	var cachePath string
+	if p.opts.CacheDir != "" {
+		cachePath = filepath.Join(p.opts.CacheDir, fmt.Sprintf("%s-%d%s", hash, v.Width, ext))
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return writeFile(dstPath, data)
+		}
+	}
+
+	src, err := decode()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, Resize(src, v.Width, v.Height), ext); err != nil {
+		return err
+	}
+
+	if cachePath != "" {
+		if err := writeFile(cachePath, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return writeFile(dstPath, buf.Bytes())
+}
+
+func variantExt(format string) string {
+	if format == "jpeg" {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+func encode(w io.Writer, img image.Image, ext string) error {
+	if ext == ".jpg" {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: JPEGQuality})
+	}
+	return png.Encode(w, img)
+}
+
+// writeFile writes data to path through a temporary file, so an
+// interrupted run never leaves a truncated file behind.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("mkdir: %s: %w", filepath.Dir(path), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create: %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write: %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close: %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("chmod: %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename: %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestResize(t *testing.T) {
+	testCases := []struct {
+		name   string
+		src    image.Image
+		width  int
+		height int
+		want   color.RGBA
+	}{
+		{
+			name:   "downscale preserves a solid color",
+			src:    solid(64, 48, color.RGBA{R: 200, G: 100, B: 50, A: 255}),
+			width:  16,
+			height: 12,
+			want:   color.RGBA{R: 200, G: 100, B: 50, A: 255},
+		},
+		{
+			name:   "upscale preserves a solid color",
+			src:    solid(4, 4, color.RGBA{R: 10, G: 20, B: 30, A: 255}),
+			width:  9,
+			height: 9,
+			want:   color.RGBA{R: 10, G: 20, B: 30, A: 255},
+		},
+		{
+			name:   "transparent pixels stay transparent",
+			src:    solid(10, 10, color.RGBA{}),
+			width:  3,
+			height: 3,
+			want:   color.RGBA{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Resize(tc.src, tc.width, tc.height)
+
+			assert.Equal(t, got.Bounds(), image.Rect(0, 0, tc.width, tc.height))
+			for y := 0; y < tc.height; y++ {
+				for x := 0; x < tc.width; x++ {
+					assert.Equal(t, got.RGBAAt(x, y), tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	dir := t.TempDir()
+	img := solid(100, 50, color.RGBA{R: 255, A: 255})
+
+	pngPath := filepath.Join(dir, "src", "wide.png")
+	writeTestFile(t, pngPath, func(buf *bytes.Buffer) error { return png.Encode(buf, img) })
+
+	jpgPath := filepath.Join(dir, "src", "photo.jpeg")
+	writeTestFile(t, jpgPath, func(buf *bytes.Buffer) error { return jpeg.Encode(buf, img, nil) })
+
+	gifPath := filepath.Join(dir, "src", "anim.gif")
+	writeTestFile(t, gifPath, func(buf *bytes.Buffer) error {
+		frame := image.NewPaletted(image.Rect(0, 0, 100, 50), color.Palette{color.Black, color.White})
+		return gif.EncodeAll(buf, &gif.GIF{
+			Image: []*image.Paletted{frame, frame},
+			Delay: []int{10, 10},
+		})
+	})
+
+	testCases := []struct {
+		name      string
+		path      string
+		widths    []int
+		wantSizes [][2]int
+	}{
+		{
+			name:      "variants narrower than the source are written",
+			path:      pngPath,
+			widths:    []int{40, 80, 100, 200},
+			wantSizes: [][2]int{{40, 20}, {80, 40}},
+		},
+		{
+			name:      "jpeg variants keep their format",
+			path:      jpgPath,
+			widths:    []int{50},
+			wantSizes: [][2]int{{50, 25}},
+		},
+		{
+			name:      "animated gifs are not resized",
+			path:      gifPath,
+			widths:    []int{50},
+			wantSizes: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := t.TempDir()
+			p := NewProcessor(Options{Widths: tc.widths})
+
+			got, written, err := p.Process(tc.path, out)
+			require.NoError(t, err)
+
+			assert.Equal(t, got.Width, 100)
+			assert.Equal(t, got.Height, 50)
+			assert.Equal(t, len(got.Variants), len(tc.wantSizes))
+			assert.Equal(t, len(written), len(tc.wantSizes))
+
+			for i, v := range got.Variants {
+				f, err := os.Open(filepath.Join(out, v.Name))
+				require.NoError(t, err)
+
+				cfg, _, err := image.DecodeConfig(f)
+				_ = f.Close()
+				require.NoError(t, err)
+
+				assert.Equal(t, [2]int{v.Width, v.Height}, tc.wantSizes[i])
+				assert.Equal(t, [2]int{cfg.Width, cfg.Height}, tc.wantSizes[i])
+			}
+		})
+	}
+}
+
+func TestProcess_Cache(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.png")
+	writeTestFile(t, src, func(buf *bytes.Buffer) error {
+		return png.Encode(buf, solid(20, 10, color.RGBA{B: 255, A: 255}))
+	})
+
+	p := NewProcessor(Options{Widths: []int{10}, CacheDir: filepath.Join(dir, "cache")})
+
+	first, _, err := p.Process(src, filepath.Join(dir, "out1"))
+	require.NoError(t, err)
+
+	cached, err := filepath.Glob(filepath.Join(dir, "cache", "*"))
+	require.NoError(t, err)
+	assert.Equal(t, len(cached), 1)
+
+	// a cache hit is copied as is, so a marked cache entry shows up in the
+	// second run's output
+	require.NoError(t, os.WriteFile(cached[0], []byte("cached"), 0o644))
+
+	second, _, err := p.Process(src, filepath.Join(dir, "out2"))
+	require.NoError(t, err)
+	assert.Equal(t, second, first)
+
+	data, err := os.ReadFile(filepath.Join(dir, "out2", second.Variants[0].Name))
+	require.NoError(t, err)
+	assert.Equal(t, string(data), "cached")
+
+	// changing the source changes its hash, so the cache is bypassed
+	writeTestFile(t, src, func(buf *bytes.Buffer) error {
+		return png.Encode(buf, solid(20, 10, color.RGBA{G: 255, A: 255}))
+	})
+
+	third, _, err := p.Process(src, filepath.Join(dir, "out3"))
+	require.NoError(t, err)
+	assert.NotEqual(t, third.Variants[0].Name, first.Variants[0].Name)
+}
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+
+	srcPath := filepath.Join(dir, "src", "wide.png")
+	writeTestFile(t, srcPath, func(buf *bytes.Buffer) error {
+		return png.Encode(buf, solid(100, 50, color.RGBA{B: 255, A: 255}))
+	})
+
+	p := NewProcessor(Options{Widths: []int{40, 200}})
+
+	planned, err := p.Plan(srcPath)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "out"))
+	assert.True(t, os.IsNotExist(err))
+
+	processed, _, err := p.Process(srcPath, filepath.Join(dir, "out"))
+	require.NoError(t, err)
+	assert.Equal(t, planned, processed)
+}
+
+func TestDecode(t *testing.T) {
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, solid(20, 10, color.RGBA{G: 255, A: 255})))
+
+	good := filepath.Join(dir, "good.png")
+	require.NoError(t, os.WriteFile(good, buf.Bytes(), 0o644))
+
+	// the header survives, so only a full decode finds the damage
+	truncated := filepath.Join(dir, "truncated.png")
+	require.NoError(t, os.WriteFile(truncated, buf.Bytes()[:40], 0o644))
+
+	img, err := Decode(good)
+	require.NoError(t, err)
+	assert.Equal(t, img, Image{Width: 20, Height: 10})
+
+	_, err = Decode(truncated)
+	assert.NotNil(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, len(entries), 2)
+}
+
+func TestIsImage(t *testing.T) {
+	testCases := []struct {
+		path string
+		want bool
+	}{
+		{path: "a.png", want: true},
+		{path: "a.JPG", want: true},
+		{path: "a.jpeg", want: true},
+		{path: "a.gif", want: true},
+		{path: "a.svg", want: false},
+		{path: "notes", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, IsImage(tc.path), tc.want)
+		})
+	}
+}
+
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func writeTestFile(t *testing.T, path string, encode func(*bytes.Buffer) error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
@@ -0,0 +1,126 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Resize resamples src to width by height pixels using a Catmull-Rom
+// filter.
+//
+// When shrinking, the filter is widened by the scale factor so every
+// source pixel contributes to the result, which avoids the aliasing of
+// nearest-neighbor and bilinear sampling.
+func Resize(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+
+	in := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+
+	xw := filterWeights(b.Dx(), width)
+	yw := filterWeights(b.Dy(), height)
+
+	// horizontal pass into an intermediate buffer of premultiplied
+	// channels, one row per source row
+	tmp := make([]float64, width*b.Dy()*4)
+	for y := 0; y < b.Dy(); y++ {
+		row := in.Pix[y*in.Stride:]
+		for x := 0; x < width; x++ {
+			var c [4]float64
+			for _, t := range xw[x] {
+				p := row[t.index*4:]
+				for i := range c {
+					c[i] += float64(p[i]) * t.weight
+				}
+			}
+			copy(tmp[(y*width+x)*4:], c[:])
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var c [4]float64
+			for _, t := range yw[y] {
+				p := tmp[(t.index*width+x)*4:]
+				for i := range c {
+					c[i] += p[i] * t.weight
+				}
+			}
+
+			// premultiplied color may not exceed alpha
+			alpha := clampByte(c[3])
+			o := out.Pix[y*out.Stride+x*4:]
+			for i := 0; i < 3; i++ {
+				o[i] = min(clampByte(c[i]), alpha)
+			}
+			o[3] = alpha
+		}
+	}
+
+	return out
+}
+
+type tap struct {
+	index  int
+	weight float64
+}
+
+// filterWeights returns, for each of the dst output samples, the source
+// samples that contribute to it and their normalized weights.
+func filterWeights(src, dst int) [][]tap {
+	scale := float64(src) / float64(dst)
+	filterScale := math.Max(scale, 1)
+	support := 2 * filterScale
+
+	weights := make([][]tap, dst)
+	for i := range weights {
+		center := (float64(i) + 0.5) * scale
+		lo := int(math.Floor(center - support))
+		hi := int(math.Ceil(center + support))
+
+		var taps []tap
+		var sum float64
+		for j := lo; j <= hi; j++ {
+			w := catmullRom((float64(j) + 0.5 - center) / filterScale)
+			if w == 0 {
+				continue
+			}
+
+			taps = append(taps, tap{index: min(max(j, 0), src-1), weight: w})
+			sum += w
+		}
+
+		for k := range taps {
+			taps[k].weight /= sum
+		}
+
+		weights[i] = taps
+	}
+
+	return weights
+}
+
+func catmullRom(x float64) float64 {
+	x = math.Abs(x)
+	switch {
+	case x < 1:
+		return (1.5*x-2.5)*x*x + 1
+	case x < 2:
+		return ((-0.5*x+2.5)*x-4)*x + 2
+	default:
+		return 0
+	}
+}
+
+func clampByte(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
@@ -0,0 +1,63 @@
+package site
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/templates"
+)
+
+// buildArchives writes a page for each year with posts at /blog/<yyyy>/
+// and for each month within it at /blog/<yyyy>/<mm>/.
+func buildArchives(ctx BuildContext) ([]string, error) {
+	var written []string
+	for _, y := range ctx.Archive {
+		path := pagePath(ctx.OutDir, archiveYearURL(y.Year))
+		if err := writeRendered(path, func(w io.Writer) error {
+			return templates.ArchiveYear(y).Render(ctx.render(), w)
+		}); err != nil {
+			return nil, err
+		}
+
+		written = append(written, path)
+
+		for _, m := range y.Months {
+			path := pagePath(ctx.OutDir, archiveMonthURL(m.Year, m.Month))
+			if err := writeRendered(path, func(w io.Writer) error {
+				return templates.ArchiveMonth(m).Render(ctx.render(), w)
+			}); err != nil {
+				return nil, err
+			}
+
+			written = append(written, path)
+		}
+	}
+
+	return written, nil
+}
+
+// reservedSlug reports whether a post slug would share its URL with a
+// page of the blog index or an archive: "page" and four-digit years.
+func reservedSlug(slug string) bool {
+	if slug == "page" {
+		return true
+	}
+	if len(slug) != 4 {
+		return false
+	}
+	for i := 0; i < len(slug); i++ {
+		if slug[i] < '0' || slug[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func archiveYearURL(year int) string {
+	return fmt.Sprintf("/blog/%04d/", year)
+}
+
+func archiveMonthURL(year int, month time.Month) string {
+	return fmt.Sprintf("/blog/%04d/%02d/", year, int(month))
+}
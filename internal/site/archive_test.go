@@ -0,0 +1,71 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestReservedSlug(t *testing.T) {
+	testCases := []struct {
+		slug string
+		want bool
+	}{
+		{slug: "page", want: true},
+		{slug: "2026", want: true},
+		{slug: "0001", want: true},
+		{slug: "pages", want: false},
+		{slug: "Page", want: false},
+		{slug: "202", want: false},
+		{slug: "20261", want: false},
+		{slug: "2026-recap", want: false},
+		{slug: "20x6", want: false},
+		{slug: "hello", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.slug, func(t *testing.T) {
+			assert.Equal(t, reservedSlug(tc.slug), tc.want)
+		})
+	}
+}
+
+func TestLoadPosts_ReservedSlugs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		slug    string
+		wantErr bool
+	}{
+		{name: "a slug naming a blog index page collides", slug: "page", wantErr: true},
+		{name: "a slug naming a year archive collides", slug: "2026", wantErr: true},
+		{name: "a slug that starts with a year is free", slug: "2026-recap", wantErr: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeFiles(t, map[string]string{
+				"content/posts/p/index.md": post("P", tc.slug, "", "Hello."),
+			})
+
+			posts, errs, err := loadPosts()
+			require.NoError(t, err)
+			assert.Equal(t, len(posts), 1)
+
+			if !tc.wantErr {
+				assert.NoError(t, errs.Err())
+				return
+			}
+
+			require.Equal(t, len(errs), 1)
+			assert.ErrorIs(t, errs[0], content.ErrReservedSlug)
+
+			var serr content.SourceError
+			require.ErrorAs(t, errs[0], &serr)
+			line, col := serr.Position()
+			assert.Equal(t, [2]int{line, col}, [2]int{3, 7})
+		})
+	}
+}
@@ -4,50 +4,168 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/media"
 	"github.com/spcameron/seanpatrickcameron.com/templates"
 )
 
+// contentDir is the root of the site's source content, and staticDir holds
+// the assets copied under /assets/ alongside the built pages.
+const (
+	contentDir = "content"
+	staticDir  = "static"
+)
+
+// BuildContext holds everything the build renders. PageSize is the number
+// of posts on each page of the blog index, and Archive groups the posts by
+// year and month.
 type BuildContext struct {
-	OutDir string
-	Posts  []content.Post
+	OutDir   string
+	Posts    []content.Post
+	Pages    []content.Page
+	Tags     []content.TagListing
+	Archive  []content.YearArchive
+	PageSize int
+	BaseURL  string
+}
+
+// render returns the context templates are rendered with, carrying the
+// navigation links of the site's pages and its base URL.
+func (ctx BuildContext) render() context.Context {
+	c := templates.WithNav(context.Background(), pageNav(ctx.Pages))
+	return templates.WithBaseURL(c, ctx.BaseURL)
+}
+
+// BuildOptions configures a site build.
+//
+// CacheDir holds processed media between builds, and ImageWidths lists the
+// widths of the responsive variants generated for each post image. Warn,
+// when set, receives each warning raised while compiling posts.
+//
+// Drafts and Future include draft and scheduled posts, which are otherwise
+// left out of the build, and Now is the moment the build is published at.
+// A zero Now means the current time.
+type BuildOptions struct {
+	OutDir      string
+	CacheDir    string
+	ImageWidths []int
+	Warn        func(content.SourceError)
+
+	Drafts bool
+	Future bool
+	Now    time.Time
 }
 
-func BuildSite(out string) ([]string, error) {
-	out = filepath.Clean(out)
+func BuildSite(opts BuildOptions) ([]string, error) {
+	out := filepath.Clean(opts.OutDir)
 
 	if err := os.MkdirAll(out, 0o755); err != nil {
 		return nil, fmt.Errorf("mkdir: %s: %w", out, err)
 	}
 
-	candidates, err := content.DiscoverPosts("content")
+	cfg, errs := loadConfig()
+
+	posts, postErrs, err := loadPosts()
 	if err != nil {
 		return nil, err
 	}
+	errs = append(errs, postErrs...)
 
-	posts, err := content.ParsePosts(candidates)
+	pages, pageErrs, err := loadPages()
 	if err != nil {
 		return nil, err
 	}
-	if err := validatePosts(posts); err != nil {
-		return nil, err
+	errs = append(errs, pageErrs...)
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	posts = content.SelectPosts(posts, content.SelectOptions{
+		Drafts: opts.Drafts,
+		Future: opts.Future,
+		Now:    now,
+	})
+
+	// tags are named from the posts the build includes, so a left-out
+	// draft never decides how a published tag is spelled
+	if err := content.ApplyTags(posts, cfg); err != nil {
+		errs = errs.Append(err)
 	}
 
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].FrontMatter.Date.After(posts[j].FrontMatter.Date)
+	proc := media.NewProcessor(media.Options{
+		Widths:   opts.ImageWidths,
+		CacheDir: opts.CacheDir,
 	})
 
+	images, err := planPostMedia(proc, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := content.CompilePosts(posts, content.CompileOptions{
+		ResolveWikiLink: wikiLinkResolver(posts),
+		RewriteURL:      postURLRewriter,
+		Srcset:          srcsetResolver(images),
+		CheckOutline:    true,
+	}); err != nil {
+		errs = errs.Append(err)
+	}
+
+	if err := content.CompilePages(pages, content.PageCompileOptions{
+		ResolveWikiLink: wikiLinkResolver(posts),
+		RewriteURL:      pageURLRewriter,
+		CheckOutline:    true,
+	}); err != nil {
+		errs = errs.Append(err)
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	processed, err := processPostMedia(proc, out, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Warn != nil {
+		for _, p := range posts {
+			for _, w := range p.Warnings {
+				opts.Warn(w)
+			}
+		}
+		for _, pg := range pages {
+			for _, w := range pg.Warnings {
+				opts.Warn(w)
+			}
+		}
+	}
+
+	content.SortPosts(posts)
+	content.LinkPosts(posts)
+
 	ctx := BuildContext{
-		OutDir: out,
-		Posts:  posts,
+		OutDir:   out,
+		Posts:    posts,
+		Pages:    pages,
+		Tags:     content.GroupTags(posts),
+		Archive:  content.ArchivePosts(posts),
+		PageSize: cfg.PageSize,
+		BaseURL:  cfg.BaseURL,
 	}
 
-	var written []string
+	written := processed
 
 	if w, err := buildHome(ctx); err != nil {
 		return nil, err
@@ -61,20 +179,45 @@ func BuildSite(out string) ([]string, error) {
 		written = append(written, w...)
 	}
 
+	if w, err := buildArchives(ctx); err != nil {
+		return nil, err
+	} else {
+		written = append(written, w...)
+	}
+
 	if w, err := buildBlogPosts(ctx); err != nil {
 		return nil, err
 	} else {
 		written = append(written, w...)
 	}
 
+	if w, err := buildTags(ctx); err != nil {
+		return nil, err
+	} else {
+		written = append(written, w...)
+	}
+
+	if w, err := buildPages(ctx); err != nil {
+		return nil, err
+	} else {
+		written = append(written, w...)
+	}
+
 	return written, nil
 
 }
 
+// buildHome writes the home page, unless a page at "/" replaces it.
 func buildHome(ctx BuildContext) ([]string, error) {
+	for _, pg := range ctx.Pages {
+		if pg.FrontMatter.Path == "/" {
+			return nil, nil
+		}
+	}
+
 	path := filepath.Join(ctx.OutDir, "index.html")
 	if err := writeRendered(path, func(w io.Writer) error {
-		return templates.Home().Render(context.Background(), w)
+		return templates.Home().Render(ctx.render(), w)
 	}); err != nil {
 		return nil, err
 	}
@@ -82,15 +225,50 @@ func buildHome(ctx BuildContext) ([]string, error) {
 	return []string{path}, nil
 }
 
+// buildBlogIndex writes the blog index, split into pages of ctx.PageSize
+// posts: the first at /blog/ and each later one at /blog/page/<n>/.
 func buildBlogIndex(ctx BuildContext) ([]string, error) {
-	path := blogIndexPath(ctx.OutDir)
-	if err := writeRendered(path, func(w io.Writer) error {
-		return templates.BlogIndex(ctx.Posts).Render(context.Background(), w)
-	}); err != nil {
-		return nil, err
+	pages := paginate(ctx.Posts, ctx.PageSize)
+
+	var written []string
+	for i, posts := range pages {
+		page := templates.Pagination{
+			Page:  i + 1,
+			Pages: len(pages),
+		}
+		if i > 0 {
+			page.PrevURL = blogPageURL(i)
+		}
+		if i+1 < len(pages) {
+			page.NextURL = blogPageURL(i + 2)
+		}
+
+		path := pagePath(ctx.OutDir, blogPageURL(i+1))
+		if err := writeRendered(path, func(w io.Writer) error {
+			return templates.BlogIndex(posts, page, ctx.Archive).Render(ctx.render(), w)
+		}); err != nil {
+			return nil, err
+		}
+
+		written = append(written, path)
 	}
 
-	return []string{path}, nil
+	return written, nil
+}
+
+// paginate splits posts into pages of at most size posts. There is always
+// at least one page, and a size of zero or less puts every post on it.
+func paginate(posts []content.Post, size int) [][]content.Post {
+	if size <= 0 || len(posts) <= size {
+		return [][]content.Post{posts}
+	}
+
+	var pages [][]content.Post
+	for start := 0; start < len(posts); start += size {
+		pages = append(pages, posts[start:min(start+size, len(posts))])
+	}
+
+	return pages
 }
 
 func buildBlogPosts(ctx BuildContext) ([]string, error) {
@@ -98,7 +276,7 @@ func buildBlogPosts(ctx BuildContext) ([]string, error) {
 	for _, p := range ctx.Posts {
 		path := blogPostPath(ctx.OutDir, p.FrontMatter.Slug)
 		if err := writeRendered(path, func(w io.Writer) error {
-			return templates.BlogPost(p).Render(context.Background(), w)
+			return templates.BlogPost(p).Render(ctx.render(), w)
 		}); err != nil {
 			return nil, err
 		}
@@ -119,8 +297,236 @@ func buildBlogPosts(ctx BuildContext) ([]string, error) {
 	return written, nil
 }
 
-func blogIndexPath(out string) string {
-	return filepath.Join(out, "blog", "index.html")
+// loadConfig loads the site config from the content directory. Its errors
+// are returned as a list to be reported with the content's.
+func loadConfig() (content.Config, content.ErrorList) {
+	var errs content.ErrorList
+
+	cfg, err := content.LoadConfig(content.ConfigPath(contentDir))
+	if err != nil {
+		errs = errs.Append(err)
+	}
+
+	return cfg, errs
+}
+
+// loadPosts discovers and loads every post under the content directory,
+// checking that their slugs are unique and free of generated pages. Tags
+// are left for the caller to apply to the posts it selects. Content errors
+// are gathered across every post in the returned list so they can be
+// reported together; the error is reserved for failures to discover posts
+// at all.
+func loadPosts() ([]content.Post, content.ErrorList, error) {
+	candidates, err := content.DiscoverPosts(contentDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs content.ErrorList
+
+	posts, err := content.LoadPosts(candidates)
+	if err != nil {
+		errs = errs.Append(err)
+	}
+	if err := content.CheckSlugs(posts); err != nil {
+		errs = errs.Append(err)
+	}
+	if err := content.CheckReservedSlugs(posts, reservedSlug); err != nil {
+		errs = errs.Append(err)
+	}
+
+	return posts, errs, nil
+}
+
+// wikiLinkResolver resolves wiki link targets against the slugs of posts,
+// linking to each post's page under its title.
+func wikiLinkResolver(posts []content.Post) markdown.WikiLinkResolver {
+	bySlug := make(map[string]content.Post, len(posts))
+	for _, p := range posts {
+		bySlug[p.FrontMatter.Slug] = p
+	}
+
+	return func(slug string) (markdown.WikiLinkTarget, bool) {
+		p, ok := bySlug[slug]
+		if !ok {
+			return markdown.WikiLinkTarget{}, false
+		}
+
+		target := markdown.WikiLinkTarget{
+			URL:   blogPostURL(p.FrontMatter.Slug),
+			Title: p.FrontMatter.Title,
+		}
+
+		return target, true
+	}
+}
+
+// postURLRewriter resolves relative link and image destinations in p
+// against the post's page, so media/foo.png links to the copy of the
+// post's media directory however the body is embedded.
+func postURLRewriter(p content.Post) markdown.URLRewriter {
+	return relativeURLRewriter(blogPostURL(p.FrontMatter.Slug))
+}
+
+// relativeURLRewriter resolves relative link and image destinations
+// against the page at the URL path basePath.
+func relativeURLRewriter(basePath string) markdown.URLRewriter {
+	base := &url.URL{Path: basePath}
+
+	return func(ref markdown.URLRef) markdown.URLRewrite {
+		if _, ok := relativePath(ref.URL); !ok {
+			return markdown.URLRewrite{URL: ref.URL}
+		}
+
+		u, _ := url.Parse(ref.URL)
+		return markdown.URLRewrite{URL: base.ResolveReference(u).String()}
+	}
+}
+
+// planPostMedia describes every image in each post's media directory and
+// the variants processPostMedia will write for it, without writing
+// anything. The returned images are keyed by post slug and then by the
+// image's slash-separated path relative to the post directory.
+func planPostMedia(proc *media.Processor, posts []content.Post) (map[string]map[string]media.Image, error) {
+	images := make(map[string]map[string]media.Image, len(posts))
+
+	for _, p := range posts {
+		byPath := make(map[string]media.Image)
+		err := walkPostMedia(p, func(path, rel string) error {
+			img, err := proc.Plan(path)
+			if err != nil {
+				return err
+			}
+
+			byPath[rel] = img
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		images[p.FrontMatter.Slug] = byPath
+	}
+
+	return images, nil
+}
+
+// processPostMedia generates the responsive variants of every image in
+// each post's media directory, writing them beside the post's copy of that
+// directory, and returns the paths written.
+func processPostMedia(proc *media.Processor, out string, posts []content.Post) ([]string, error) {
+	var written []string
+
+	for _, p := range posts {
+		dstPost := filepath.Join(out, "blog", p.FrontMatter.Slug)
+		err := walkPostMedia(p, func(path, rel string) error {
+			dstDir := filepath.Dir(filepath.Join(dstPost, filepath.FromSlash(rel)))
+
+			_, w, err := proc.Process(path, dstDir)
+			if err != nil {
+				return err
+			}
+
+			written = append(written, w...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return written, nil
+}
+
+// walkPostMedia calls fn for every image in the media directory of p with
+// the image's path and its slash-separated path relative to the post
+// directory. A post without a media directory has no images.
+func walkPostMedia(p content.Post, fn func(path, rel string) error) error {
+	srcMedia := filepath.Join(p.SourceDir, "media")
+
+	return filepath.WalkDir(srcMedia, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == srcMedia {
+				return filepath.SkipDir
+			}
+			return fmt.Errorf("walk %s: %w", path, err)
+		}
+		if !d.Type().IsRegular() || !media.IsImage(path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.SourceDir, path)
+		if err != nil {
+			return fmt.Errorf("rel: %s: %w", path, err)
+		}
+
+		return fn(path, filepath.ToSlash(rel))
+	})
+}
+
+// srcsetResolver returns, for each post, a resolver listing the processed
+// variants of a relative image destination followed by the original image.
+func srcsetResolver(images map[string]map[string]media.Image) func(p content.Post) markdown.SrcsetResolver {
+	return func(p content.Post) markdown.SrcsetResolver {
+		base := blogPostURL(p.FrontMatter.Slug)
+		byPath := images[p.FrontMatter.Slug]
+
+		return func(ref markdown.URLRef) ([]markdown.ImageCandidate, bool) {
+			rel, ok := relativePath(ref.URL)
+			if !ok {
+				return nil, false
+			}
+
+			img, ok := byPath[rel]
+			if !ok || len(img.Variants) == 0 {
+				return nil, false
+			}
+
+			dir := path.Dir(rel)
+			candidates := make([]markdown.ImageCandidate, 0, len(img.Variants)+1)
+			for _, v := range img.Variants {
+				candidates = append(candidates, markdown.ImageCandidate{
+					URL:   base + path.Join(dir, v.Name),
+					Width: v.Width,
+				})
+			}
+
+			candidates = append(candidates, markdown.ImageCandidate{
+				URL:   base + rel,
+				Width: img.Width,
+			})
+
+			return candidates, true
+		}
+	}
+}
+
+// relativePath returns the cleaned path of a destination relative to the
+// post, reporting false for absolute, remote, and fragment-only URLs.
+func relativePath(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+
+	return path.Clean(u.Path), true
+}
+
+func blogPostURL(slug string) string {
+	return "/blog/" + slug + "/"
+}
+
+// blogPageURL returns the URL path of page n of the blog index, counting
+// from 1.
+func blogPageURL(n int) string {
+	if n <= 1 {
+		return "/blog/"
+	}
+	return fmt.Sprintf("/blog/page/%d/", n)
+}
+
+func tagURL(slug string) string {
+	return "/tags/" + slug + "/"
 }
 
 func blogPostPath(out, slug string) string {
@@ -149,22 +555,6 @@ func writeRendered(path string, render func(io.Writer) error) error {
 	return nil
 }
 
-func validatePosts(posts []content.Post) error {
-	seen := make(map[string]struct{}, len(posts))
-	for _, p := range posts {
-		s := strings.TrimSpace(p.FrontMatter.Slug)
-		if s == "" {
-			return content.ErrMissingSlug
-		}
-		if _, ok := seen[s]; ok {
-			return fmt.Errorf("duplicate slug: %q", s)
-		}
-		seen[s] = struct{}{}
-	}
-
-	return nil
-}
-
 func copyDirIfExists(srcDir, dstDir string) ([]string, error) {
 	info, err := os.Stat(srcDir)
 	if err != nil {
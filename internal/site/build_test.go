@@ -0,0 +1,334 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/media"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+	"github.com/spcameron/seanpatrickcameron.com/templates"
+)
+
+func TestPaginate(t *testing.T) {
+	posts := make([]content.Post, 6)
+	for i := range posts {
+		posts[i].FrontMatter.Slug = string(rune('a' + i))
+	}
+
+	testCases := []struct {
+		name  string
+		posts []content.Post
+		size  int
+		want  []int
+	}{
+		{
+			name:  "no posts still makes one empty page",
+			posts: nil,
+			size:  10,
+			want:  []int{0},
+		},
+		{
+			name:  "fewer posts than the page size fit on one page",
+			posts: posts[:4],
+			size:  10,
+			want:  []int{4},
+		},
+		{
+			name:  "exactly one page of posts",
+			posts: posts[:3],
+			size:  3,
+			want:  []int{3},
+		},
+		{
+			name:  "an exact multiple of the page size has no empty last page",
+			posts: posts,
+			size:  3,
+			want:  []int{3, 3},
+		},
+		{
+			name:  "a partial last page",
+			posts: posts,
+			size:  4,
+			want:  []int{4, 2},
+		},
+		{
+			name:  "page size one puts each post on its own page",
+			posts: posts[:3],
+			size:  1,
+			want:  []int{1, 1, 1},
+		},
+		{
+			name:  "a page size of zero puts every post on one page",
+			posts: posts,
+			size:  0,
+			want:  []int{6},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pages := paginate(tc.posts, tc.size)
+
+			var got []int
+			var slugs []string
+			for _, page := range pages {
+				got = append(got, len(page))
+				for _, p := range page {
+					slugs = append(slugs, p.FrontMatter.Slug)
+				}
+			}
+			assert.Equal(t, got, tc.want)
+
+			var want []string
+			for _, p := range tc.posts {
+				want = append(want, p.FrontMatter.Slug)
+			}
+			assert.Equal(t, slugs, want)
+		})
+	}
+}
+
+func TestPlanPostMedia(t *testing.T) {
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 100, 50))))
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "a/media/photo.png"):       buf.String(),
+		filepath.Join(dir, "a/media/nested/deep.png"): buf.String(),
+		filepath.Join(dir, "a/media/notes.txt"):       "notes",
+	})
+
+	posts := []content.Post{
+		{SourceDir: filepath.Join(dir, "a"), FrontMatter: content.FrontMatter{Slug: "a"}},
+		{SourceDir: filepath.Join(dir, "b"), FrontMatter: content.FrontMatter{Slug: "b"}},
+	}
+	proc := media.NewProcessor(media.Options{Widths: []int{40, 200}})
+
+	images, err := planPostMedia(proc, posts)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(images["b"]), 0)
+	require.Equal(t, len(images["a"]), 2)
+
+	var want []string
+	for _, rel := range []string{"media/photo.png", "media/nested/deep.png"} {
+		img, ok := images["a"][rel]
+		require.True(t, ok)
+		assert.Equal(t, [2]int{img.Width, img.Height}, [2]int{100, 50})
+
+		// a variant is only planned below the source width
+		require.Equal(t, len(img.Variants), 1)
+		v := img.Variants[0]
+		assert.Equal(t, [2]int{v.Width, v.Height}, [2]int{40, 20})
+
+		stem := filepath.Base(rel)
+		stem = stem[:len(stem)-len(filepath.Ext(stem))]
+		assert.MatchesRegexp(t, v.Name, `^`+stem+`-[0-9a-f]{12}-40\.png$`)
+
+		want = append(want, filepath.Join(dir, "out", "blog", "a", filepath.Dir(filepath.FromSlash(rel)), v.Name))
+	}
+
+	written, err := processPostMedia(proc, filepath.Join(dir, "out"), posts)
+	require.NoError(t, err)
+
+	slices.Sort(written)
+	slices.Sort(want)
+	assert.Equal(t, written, want)
+	for _, path := range written {
+		_, err := os.Stat(path)
+		assert.NoError(t, err)
+	}
+}
+
+func TestSrcsetResolver(t *testing.T) {
+	images := map[string]map[string]media.Image{
+		"a": {
+			"media/photo.png": {
+				Width: 1000,
+				Variants: []media.Variant{
+					{Name: "photo-0123456789ab-480.png", Width: 480},
+					{Name: "photo-0123456789ab-960.png", Width: 960},
+				},
+			},
+			"media/nested/deep.jpg": {
+				Width:    600,
+				Variants: []media.Variant{{Name: "deep-0123456789ab-480.jpg", Width: 480}},
+			},
+			"media/small.png": {Width: 100},
+		},
+	}
+	resolve := srcsetResolver(images)(content.Post{FrontMatter: content.FrontMatter{Slug: "a"}})
+
+	testCases := []struct {
+		name string
+		url  string
+		want []string
+		ok   bool
+	}{
+		{
+			name: "variants are listed before the original",
+			url:  "media/photo.png",
+			want: []string{
+				"/blog/a/media/photo-0123456789ab-480.png 480w",
+				"/blog/a/media/photo-0123456789ab-960.png 960w",
+				"/blog/a/media/photo.png 1000w",
+			},
+			ok: true,
+		},
+		{
+			name: "the destination is cleaned before lookup",
+			url:  "./media/photo.png",
+			want: []string{
+				"/blog/a/media/photo-0123456789ab-480.png 480w",
+				"/blog/a/media/photo-0123456789ab-960.png 960w",
+				"/blog/a/media/photo.png 1000w",
+			},
+			ok: true,
+		},
+		{
+			name: "variants sit beside a nested original",
+			url:  "media/nested/deep.jpg",
+			want: []string{
+				"/blog/a/media/nested/deep-0123456789ab-480.jpg 480w",
+				"/blog/a/media/nested/deep.jpg 600w",
+			},
+			ok: true,
+		},
+		{name: "an image without variants has no srcset", url: "media/small.png"},
+		{name: "an unknown image has no srcset", url: "media/other.png"},
+		{name: "a site-absolute image has no srcset", url: "/assets/photo.png"},
+		{name: "a remote image has no srcset", url: "https://example.com/photo.png"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			candidates, ok := resolve(markdown.URLRef{URL: tc.url})
+			assert.Equal(t, ok, tc.ok)
+
+			var got []string
+			for _, c := range candidates {
+				got = append(got, fmt.Sprintf("%s %dw", c.URL, c.Width))
+			}
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestRelativeURLRewriter(t *testing.T) {
+	testCases := []struct {
+		name string
+		base string
+		url  string
+		want string
+	}{
+		{name: "media file", base: "/blog/a/", url: "media/photo.png", want: "/blog/a/media/photo.png"},
+		{name: "fragment is kept", base: "/blog/a/", url: "media/notes.pdf#page=2", want: "/blog/a/media/notes.pdf#page=2"},
+		{name: "page media", base: "/about/", url: "media/me.jpg", want: "/about/media/me.jpg"},
+		{name: "parent directory", base: "/blog/a/", url: "../b/", want: "/blog/b/"},
+		{name: "site-absolute path is unchanged", base: "/blog/a/", url: "/tags/go/", want: "/tags/go/"},
+		{name: "remote URL is unchanged", base: "/blog/a/", url: "https://example.com/x", want: "https://example.com/x"},
+		{name: "fragment only is unchanged", base: "/blog/a/", url: "#top", want: "#top"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := relativeURLRewriter(tc.base)(markdown.URLRef{URL: tc.url})
+			assert.Equal(t, got.URL, tc.want)
+		})
+	}
+}
+
+func TestBuildContext_OpenGraphImage(t *testing.T) {
+	p := content.Post{
+		FrontMatter: content.FrontMatter{
+			Title: "A",
+			Slug:  "a",
+			Date:  time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC),
+			Cover: content.Cover{Image: "media/cover.png", Alt: "A cover"},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{
+			name:    "cover is published under the base url",
+			baseURL: "https://example.com",
+			want:    `<meta property="og:image" content="https://example.com/blog/a/media/cover.png">`,
+		},
+		{
+			name:    "cover is left out without a base url",
+			baseURL: "",
+			want:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := BuildContext{BaseURL: tc.baseURL}
+
+			var b strings.Builder
+			require.NoError(t, templates.BlogPost(p).Render(ctx.render(), &b))
+
+			if tc.want == "" {
+				assert.False(t, strings.Contains(b.String(), "og:image"))
+				return
+			}
+			assert.Contains(t, b.String(), tc.want)
+		})
+	}
+}
+
+func TestBuildSite_TagNames(t *testing.T) {
+	testCases := []struct {
+		name   string
+		drafts bool
+		want   string
+	}{
+		{
+			name:   "a left-out draft does not name a published tag",
+			drafts: false,
+			want:   "Posts tagged <em>go</em>",
+		},
+		{
+			name:   "an included draft names the tag when it spells it first",
+			drafts: true,
+			want:   "Posts tagged <em>Go</em>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeFiles(t, map[string]string{
+				"content/posts/a-draft/index.md":     post("Draft", "a-draft", "draft: true\ntags: [Go]", "Soon."),
+				"content/posts/b-published/index.md": post("Published", "b-published", "tags: [go]", "Hello."),
+			})
+
+			_, err := BuildSite(BuildOptions{
+				OutDir: "out",
+				Drafts: tc.drafts,
+				Now:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			})
+			require.NoError(t, err)
+
+			page, err := os.ReadFile(filepath.Join("out", "tags", "go", "index.html"))
+			require.NoError(t, err)
+			assert.Contains(t, string(page), tc.want)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/media"
+)
+
+var (
+	ErrMissingMedia   = errors.New("missing file")
+	ErrOutsidePost    = errors.New("relative link leaves the post directory")
+	ErrOutsideMedia   = errors.New("relative link names a file outside media/, which is not published")
+	ErrBrokenInternal = errors.New("no page or file at internal link")
+)
+
+// CheckSite validates the site's content as BuildSite would, without
+// writing any output. Every post and page is checked, drafts and scheduled
+// posts included, and each relative link or image must name a file in the
+// post's or page's media directory and each site-absolute link a page or
+// file the build produces. Every image in a post's media directory must decode.
+//
+// Published posts and pages are checked against the posts a default build
+// includes, so a link to a draft is reported. Drafts and scheduled posts
+// are checked against every post, as a build with drafts and future posts
+// includes them all.
+//
+// It returns the warnings raised while compiling posts and pages and an
+// ErrorList of the errors found across all of them.
+func CheckSite() ([]content.SourceError, error) {
+	cfg, errs := loadConfig()
+
+	posts, postErrs, err := loadPosts()
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, postErrs...)
+
+	pages, pageErrs, err := loadPages()
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, pageErrs...)
+
+	now := time.Now()
+	all := content.SelectPosts(posts, content.SelectOptions{Drafts: true, Future: true, Now: now})
+	published := content.SelectPosts(posts, content.SelectOptions{Now: now})
+
+	// each set is tagged on its own, as the build that includes it would
+	// be; the tag errors among published posts are also found among all
+	// of them, so they are reported once
+	if err := content.ApplyTags(all, cfg); err != nil {
+		errs = errs.Append(err)
+	}
+	_ = content.ApplyTags(published, cfg)
+
+	var unpublished []content.Post
+	for _, p := range all {
+		if p.Status != content.Published {
+			unpublished = append(unpublished, p)
+		}
+	}
+
+	if err := checkPostMedia(all); err != nil {
+		errs = errs.Append(err)
+	}
+
+	publishedRoutes, err := siteRoutes(cfg, published, pages)
+	if err != nil {
+		return nil, err
+	}
+	previewRoutes, err := siteRoutes(cfg, all, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPosts(published, published, publishedRoutes); err != nil {
+		errs = errs.Append(err)
+	}
+	if err := checkPosts(unpublished, all, previewRoutes); err != nil {
+		errs = errs.Append(err)
+	}
+
+	checkURL := urlChecker(publishedRoutes)
+
+	if err := content.CompilePages(pages, content.PageCompileOptions{
+		ResolveWikiLink: wikiLinkResolver(published),
+		CheckOutline:    true,
+		CheckURL: func(pg content.Page, ref markdown.URLRef) error {
+			return checkURL(pg.SourceDir, ref)
+		},
+	}); err != nil {
+		errs = errs.Append(err)
+	}
+
+	var warnings []content.SourceError
+	for _, p := range slices.Concat(published, unpublished) {
+		warnings = append(warnings, p.Warnings...)
+	}
+	for _, pg := range pages {
+		warnings = append(warnings, pg.Warnings...)
+	}
+
+	return warnings, errs.Err()
+}
+
+// checkPosts compiles posts in place, resolving their wiki links against
+// targets and checking their links against routes.
+func checkPosts(posts, targets []content.Post, routes map[string]bool) error {
+	checkURL := urlChecker(routes)
+
+	return content.CompilePosts(posts, content.CompileOptions{
+		ResolveWikiLink: wikiLinkResolver(targets),
+		CheckOutline:    true,
+		CheckURL: func(p content.Post, ref markdown.URLRef) error {
+			return checkURL(p.SourceDir, ref)
+		},
+	})
+}
+
+// checkPostMedia decodes every image in each post's media directory, as
+// the build does before resizing it, and returns an ErrorList of the
+// images that fail.
+func checkPostMedia(posts []content.Post) error {
+	var errs content.ErrorList
+	for _, p := range posts {
+		err := walkPostMedia(p, func(path, _ string) error {
+			if _, err := media.Decode(path); err != nil {
+				errs = errs.Append(err)
+			}
+			return nil
+		})
+		if err != nil {
+			errs = errs.Append(err)
+		}
+	}
+
+	return errs.Err()
+}
+
+// urlChecker returns a check that relative destinations name files in the
+// media directory under dir, the only files the build publishes beside a
+// post or page, and site-absolute destinations are among routes.
+// Remote URLs, fragments, and other schemes are not checked.
+func urlChecker(routes map[string]bool) func(dir string, ref markdown.URLRef) error {
+	return func(dir string, ref markdown.URLRef) error {
+		if rel, ok := relativePath(ref.URL); ok {
+			if rel == ".." || strings.HasPrefix(rel, "../") {
+				return fmt.Errorf("%w: %s", ErrOutsidePost, rel)
+			}
+			if !strings.HasPrefix(rel, "media/") {
+				return fmt.Errorf("%w: %s", ErrOutsideMedia, rel)
+			}
+
+			info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel)))
+			if err != nil || info.IsDir() {
+				return fmt.Errorf("%w: %s", ErrMissingMedia, rel)
+			}
+			return nil
+		}
+
+		u, err := url.Parse(ref.URL)
+		if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+			return nil
+		}
+
+		if !routes[u.Path] && !routes[strings.TrimSuffix(u.Path, "/")+"/"] {
+			return fmt.Errorf("%w: %s", ErrBrokenInternal, u.Path)
+		}
+		return nil
+	}
+}
+
+// siteRoutes returns the URL path of every page and file a build of posts
+// and pages produces: the fixed pages, the pages of the blog index and its
+// archives, each post's and page's own page and media, and the static
+// assets. Page paths end in a slash.
+func siteRoutes(cfg content.Config, posts []content.Post, pages []content.Page) (map[string]bool, error) {
+	routes := map[string]bool{
+		"/":      true,
+		"/tags/": true,
+	}
+
+	for n := range paginate(posts, cfg.PageSize) {
+		routes[blogPageURL(n+1)] = true
+	}
+
+	for _, y := range content.ArchivePosts(posts) {
+		routes[archiveYearURL(y.Year)] = true
+		for _, m := range y.Months {
+			routes[archiveMonthURL(m.Year, m.Month)] = true
+		}
+	}
+
+	for _, p := range posts {
+		base := blogPostURL(p.FrontMatter.Slug)
+		routes[base] = true
+
+		for _, tag := range p.Tags {
+			routes[tagURL(tag.Slug)] = true
+		}
+
+		if err := addFileRoutes(routes, filepath.Join(p.SourceDir, "media"), base+"media/"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pg := range pages {
+		routes[pg.FrontMatter.Path] = true
+
+		if err := addFileRoutes(routes, filepath.Join(pg.SourceDir, "media"), pg.FrontMatter.Path+"media/"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := addFileRoutes(routes, staticDir, "/assets/"); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// addFileRoutes adds a route under prefix for every regular file in dir,
+// which need not exist.
+func addFileRoutes(routes map[string]bool, dir, prefix string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return fmt.Errorf("walk %s: %w", p, err)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return fmt.Errorf("rel: %s: %w", p, err)
+		}
+
+		routes[path.Join(prefix, filepath.ToSlash(rel))] = true
+		return nil
+	})
+
+	return err
+}
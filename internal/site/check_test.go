@@ -0,0 +1,217 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/assert"
+	"github.com/spcameron/seanpatrickcameron.com/internal/testsupport/require"
+)
+
+func TestCheckSite(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		wantErrs []string
+	}{
+		{
+			name: "links between published posts pass",
+			files: map[string]string{
+				"content/posts/a/index.md": post("A", "a", "", "See [[b]] and [B](/blog/b/)."),
+				"content/posts/b/index.md": post("B", "b", "", "Back to [A](/blog/a/)."),
+			},
+		},
+		{
+			name: "a published post linking to a draft is reported",
+			files: map[string]string{
+				"content/posts/a/index.md":     post("A", "a", "", "See [[draft]] and [the draft](/blog/draft/)."),
+				"content/posts/draft/index.md": post("Draft", "draft", "draft: true", "Nothing yet."),
+			},
+			wantErrs: []string{
+				`content/posts/a/index.md:6:7 unknown wiki link target "draft"`,
+				"content/posts/a/index.md:6:19 no page or file at internal link: /blog/draft/",
+			},
+		},
+		{
+			name: "a draft may link to published posts and to itself",
+			files: map[string]string{
+				"content/posts/a/index.md":     post("A", "a", "", "Hello."),
+				"content/posts/draft/index.md": post("Draft", "draft", "draft: true", "See [[a]], [A](/blog/a/) and [me](/blog/draft/)."),
+			},
+		},
+		{
+			name: "every broken link in a post is reported",
+			files: map[string]string{
+				"content/posts/a/index.md": post("A", "a", "", "[x](/nowhere/), ![y](media/y.png) and [z](notes.txt)."),
+			},
+			wantErrs: []string{
+				"content/posts/a/index.md:6:1 no page or file at internal link: /nowhere/",
+				"content/posts/a/index.md:6:17 missing file: media/y.png",
+				"content/posts/a/index.md:6:39 relative link names a file outside media/, which is not published: notes.txt",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeFiles(t, tc.files)
+
+			warnings, err := CheckSite()
+			assert.Equal(t, len(warnings), 0)
+
+			if len(tc.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var list content.ErrorList
+			require.ErrorAs(t, err, &list)
+
+			var got []string
+			for _, e := range list {
+				var serr content.SourceError
+				require.ErrorAs(t, e, &serr)
+				line, col := serr.Position()
+				got = append(got, fmt.Sprintf("%s:%d:%d %s", filepath.ToSlash(serr.Path), line, col, serr.Diagnostic.Message))
+			}
+			assert.Equal(t, got, tc.wantErrs)
+		})
+	}
+}
+
+func TestSiteRoutes(t *testing.T) {
+	date := time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "a/media/photo.png"): "png",
+	})
+
+	published := content.Post{
+		SourceDir:   filepath.Join(dir, "a"),
+		FrontMatter: content.FrontMatter{Slug: "a", Date: date},
+		Tags:        []content.Tag{{Name: "Go", Slug: "go"}},
+	}
+	draft := content.Post{
+		SourceDir:   filepath.Join(dir, "draft"),
+		FrontMatter: content.FrontMatter{Slug: "draft", Date: date},
+		Tags:        []content.Tag{{Name: "Drafts", Slug: "drafts"}},
+	}
+
+	testCases := []struct {
+		name    string
+		posts   []content.Post
+		present []string
+		absent  []string
+	}{
+		{
+			name:  "published routes omit drafts",
+			posts: []content.Post{published},
+			present: []string{
+				"/", "/blog/", "/tags/", "/blog/a/", "/blog/a/media/photo.png",
+				"/tags/go/", "/blog/2026/", "/blog/2026/02/",
+			},
+			absent: []string{"/blog/draft/", "/tags/drafts/", "/blog/page/2/"},
+		},
+		{
+			name:    "preview routes include drafts",
+			posts:   []content.Post{published, draft},
+			present: []string{"/blog/a/", "/blog/draft/", "/tags/go/", "/tags/drafts/"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(dir)
+
+			routes, err := siteRoutes(content.Config{PageSize: 10}, tc.posts, nil)
+			require.NoError(t, err)
+
+			for _, r := range tc.present {
+				assert.True(t, routes[r])
+			}
+			for _, r := range tc.absent {
+				assert.False(t, routes[r])
+			}
+		})
+	}
+}
+
+func TestURLChecker(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "media/photo.png"): "png",
+		filepath.Join(dir, "notes.txt"):       "notes",
+	})
+
+	check := urlChecker(map[string]bool{
+		"/":        true,
+		"/blog/a/": true,
+		"/about":   true,
+	})
+
+	testCases := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "media file", url: "media/photo.png"},
+		{name: "media file with a fragment", url: "media/photo.png#top"},
+		{name: "missing media file", url: "media/other.png", wantErr: ErrMissingMedia},
+		{name: "media directory", url: "media", wantErr: ErrOutsideMedia},
+		{name: "file outside media", url: "notes.txt", wantErr: ErrOutsideMedia},
+		{name: "parent directory", url: "../b/media/photo.png", wantErr: ErrOutsidePost},
+		{name: "known route", url: "/blog/a/"},
+		{name: "known route without trailing slash", url: "/blog/a"},
+		{name: "known route with a query", url: "/blog/a/?page=2"},
+		{name: "known route without slash form", url: "/about"},
+		{name: "unknown route", url: "/blog/b/", wantErr: ErrBrokenInternal},
+		{name: "remote URL", url: "https://example.com/missing"},
+		{name: "protocol-relative URL", url: "//example.com/missing"},
+		{name: "mailto", url: "mailto:me@example.com"},
+		{name: "fragment only", url: "#section"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := check(dir, markdown.URLRef{URL: tc.url})
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// post returns a post file with the given front matter and body. extra is
+// added to the front matter when it is not empty.
+func post(title, slug, extra, body string) string {
+	lines := []string{"---", "title: " + title, "slug: " + slug, "date: 2026-02-17"}
+	if extra != "" {
+		lines = append(lines, extra)
+	}
+	lines = append(lines, "---", body, "")
+
+	return strings.Join(lines, "\n")
+}
+
+// writeFiles writes each file, creating its parent directories. Relative
+// names are resolved against the working directory.
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		name = filepath.FromSlash(name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(data), 0o644))
+	}
+}
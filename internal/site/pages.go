@@ -0,0 +1,103 @@
+package site
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/spcameron/seanpatrickcameron.com/internal/content"
+	"github.com/spcameron/seanpatrickcameron.com/internal/markdown"
+	"github.com/spcameron/seanpatrickcameron.com/templates"
+)
+
+// reservedPaths lists the URL path prefixes generated by the build, which
+// pages may not be served under.
+var reservedPaths = []string{"/blog/", "/tags/", "/assets/"}
+
+// loadPages discovers and loads every page under the content directory,
+// checking their paths against each other and the reserved paths. Content
+// errors are gathered in the returned list, as they are by loadPosts.
+func loadPages() ([]content.Page, content.ErrorList, error) {
+	candidates, err := content.DiscoverPages(contentDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs content.ErrorList
+
+	pages, err := content.LoadPages(candidates)
+	if err != nil {
+		errs = errs.Append(err)
+	}
+	if err := content.CheckPagePaths(pages, reservedPaths); err != nil {
+		errs = errs.Append(err)
+	}
+
+	return pages, errs, nil
+}
+
+func buildPages(ctx BuildContext) ([]string, error) {
+	var written []string
+	for _, pg := range ctx.Pages {
+		path := pagePath(ctx.OutDir, pg.FrontMatter.Path)
+		if err := writeRendered(path, func(w io.Writer) error {
+			return templates.Page(pg).Render(ctx.render(), w)
+		}); err != nil {
+			return nil, err
+		}
+
+		written = append(written, path)
+
+		srcMedia := filepath.Join(pg.SourceDir, "media")
+		dstMedia := filepath.Join(filepath.Dir(path), "media")
+
+		copied, err := copyDirIfExists(srcMedia, dstMedia)
+		if err != nil {
+			return nil, err
+		}
+
+		written = append(written, copied...)
+	}
+
+	return written, nil
+}
+
+// pageURLRewriter resolves relative link and image destinations in pg
+// against the page's path.
+func pageURLRewriter(pg content.Page) markdown.URLRewriter {
+	return relativeURLRewriter(pg.FrontMatter.Path)
+}
+
+// pageNav returns the navigation links of the pages with a menu position,
+// in menu order and then by title. A page replacing the home page is
+// already linked as Home.
+func pageNav(pages []content.Page) []templates.NavItem {
+	var listed []content.Page
+	for _, pg := range pages {
+		if pg.FrontMatter.Menu > 0 && pg.FrontMatter.Path != "/" {
+			listed = append(listed, pg)
+		}
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		a, b := listed[i].FrontMatter, listed[j].FrontMatter
+		if a.Menu != b.Menu {
+			return a.Menu < b.Menu
+		}
+		return a.Title < b.Title
+	})
+
+	items := make([]templates.NavItem, 0, len(listed))
+	for _, pg := range listed {
+		items = append(items, templates.NavItem{
+			Title: pg.FrontMatter.Title,
+			URL:   pg.FrontMatter.Path,
+		})
+	}
+
+	return items
+}
+
+func pagePath(out, urlPath string) string {
+	return filepath.Join(out, filepath.FromSlash(urlPath), "index.html")
+}
@@ -0,0 +1,34 @@
+package site
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/spcameron/seanpatrickcameron.com/templates"
+)
+
+// buildTags writes the tag index and a page for each tag listing its
+// posts.
+func buildTags(ctx BuildContext) ([]string, error) {
+	path := filepath.Join(ctx.OutDir, "tags", "index.html")
+	if err := writeRendered(path, func(w io.Writer) error {
+		return templates.TagIndex(ctx.Tags).Render(ctx.render(), w)
+	}); err != nil {
+		return nil, err
+	}
+
+	written := []string{path}
+
+	for _, tag := range ctx.Tags {
+		path := pagePath(ctx.OutDir, tagURL(tag.Slug))
+		if err := writeRendered(path, func(w io.Writer) error {
+			return templates.TagPage(tag).Render(ctx.render(), w)
+		}); err != nil {
+			return nil, err
+		}
+
+		written = append(written, path)
+	}
+
+	return written, nil
+}